@@ -12,10 +12,12 @@ require (
 	github.com/json-iterator/go v1.1.12
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/pkg/profile v1.6.0
+	github.com/rivo/uniseg v0.4.7
 	github.com/spf13/cast v1.5.0
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/zap v1.21.0
 	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e
+	golang.org/x/text v0.13.0
 )
 
 require (
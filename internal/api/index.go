@@ -149,6 +149,48 @@ func (c *IndexController) DeleteAction() http.HandlerFunc {
 	}
 }
 
+func (c *IndexController) ReloadAnalyzersAction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := c.repo.ReloadAnalyzers(r.Context(), chi.URLParam(r, indexParam)); err != nil {
+			if errors.Is(err, index.ErrIndexNotFound) {
+				resp, status := NewErrResponse404(ErrResponseWithMsg(err.Error()))
+				render.Status(r, status)
+				render.Respond(w, r, resp)
+				return
+			}
+			handleErr(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (c *IndexController) AnalyzeAction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req index.Analyze
+		if err := decodeAndValidate(r, &req); err != nil {
+			handleErr(w, r, err)
+			return
+		}
+
+		result, err := c.repo.Analyze(r.Context(), chi.URLParam(r, indexParam), req)
+		if err != nil {
+			if errors.Is(err, index.ErrIndexNotFound) {
+				resp, status := NewErrResponse404(ErrResponseWithMsg(err.Error()))
+				render.Status(r, status)
+				render.Respond(w, r, resp)
+				return
+			}
+			handleErr(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.Respond(w, r, result)
+	}
+}
+
 func (c *IndexController) transformIndexList(i index.Index) IndexListItem {
 	return IndexListItem{
 		Name:      i.Name,
@@ -29,6 +29,8 @@ func NewHandler(ctx context.Context, indexRepository *index.Repository, docRepos
 			ic := NewIndexController(indexRepository)
 			r.Get("/", ic.ListAction())
 			r.Post("/", ic.AddAction())
+			r.Post("/{"+indexParam+"}/_reload_analyzers", ic.ReloadAnalyzersAction())
+			r.Post("/{"+indexParam+"}/_analyze", ic.AnalyzeAction())
 		})
 
 		r.Route("/docs/{"+indexParam+"}", func(r chi.Router) {
@@ -41,6 +43,8 @@ func NewHandler(ctx context.Context, indexRepository *index.Repository, docRepos
 		r.Route("/search/{"+indexParam+"}", func(r chi.Router) {
 			sc := NewSearchController(indexRepository, docRepository)
 			r.Post("/", sc.SearchAction())
+			r.Get("/_explain/{"+documentParam+"}", sc.ExplainAction())
+			r.Post("/_explain/{"+documentParam+"}", sc.ExplainAction())
 		})
 	}
 }
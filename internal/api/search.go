@@ -54,3 +54,45 @@ func (c *SearchController) SearchAction() http.HandlerFunc {
 		render.Respond(w, r, result)
 	}
 }
+
+// ExplainAction explains the document score. The query is read from the request body,
+// so the action is available by POST as well as by GET for clients which cannot send GET requests with a body
+func (c *SearchController) ExplainAction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		i, err := c.repo.Get(chi.URLParam(r, indexParam))
+		if err != nil {
+			if errors.Is(err, index.ErrIndexNotFound) {
+				resp, status := NewErrResponse404(ErrResponseWithMsg(err.Error()))
+				render.Status(r, status)
+				render.Respond(w, r, resp)
+				return
+			}
+			handleErr(w, r, err)
+			return
+		}
+
+		query := index.ExplainQuery{}
+		if err := decodeAndValidate(r, &query); err != nil {
+			resp, status := NewErrResponse400(ErrResponseWithMsg(err.Error()))
+			render.Status(r, status)
+			render.Respond(w, r, resp)
+			return
+		}
+
+		result, err := c.docs.Explain(ctx, i, chi.URLParam(r, documentParam), query)
+		if err != nil {
+			if errors.Is(err, index.ErrDocNotFound) {
+				resp, status := NewErrResponse404(ErrResponseWithMsg(err.Error()))
+				render.Status(r, status)
+				render.Respond(w, r, resp)
+				return
+			}
+			handleErr(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.Respond(w, r, result)
+	}
+}
@@ -58,6 +58,16 @@ func build(req AggsRequest) (Aggs, error) {
 			agg = new(MinAgg)
 		case "max":
 			agg = new(MaxAgg)
+		case "geohash_grid":
+			agg = new(GeohashGridAgg)
+		case "geotile_grid":
+			agg = new(GeotileGridAgg)
+		case "geo_distance":
+			agg = new(GeoDistanceAgg)
+		case "geo_bounds":
+			agg = new(GeoBoundsAgg)
+		case "geo_centroid":
+			agg = new(GeoCentroidAgg)
 		default:
 			return nil, fmt.Errorf("unknown agg type %q", aggType.Type)
 		}
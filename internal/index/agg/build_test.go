@@ -3,6 +3,8 @@ package agg
 import (
 	"testing"
 
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 )
@@ -12,6 +14,16 @@ func mustUnmarshal(t *testing.T, src string, dst interface{}) {
 	require.NoError(t, err)
 }
 
+func newTestField(t *testing.T, fieldType schema.Type, values ...interface{}) field.Field {
+	f, err := field.New(fieldType)
+	require.NoError(t, err)
+	for i, v := range values {
+		f.Add(uint32(i+1), v)
+	}
+
+	return f
+}
+
 func Test_build(t *testing.T) {
 	t.Run("must return empty result if request is nil", func(t *testing.T) {
 		result, err := build(nil)
@@ -2,6 +2,7 @@ package agg
 
 import (
 	"context"
+	"time"
 
 	"github.com/RoaringBitmap/roaring"
 	"github.com/cyradin/search/internal/index/field"
@@ -29,13 +30,20 @@ func Exec(ctx context.Context, docs *roaring.Bitmap, req AggsRequest, fields Fie
 		return nil, err
 	}
 
+	profiles := profilesFromCtx(ctx)
+
 	result := make(Result, len(aggs))
 	for key, agg := range aggs {
+		t := time.Now()
 		r, err := agg.Exec(ctx, fields, docs)
 		if err != nil {
 			return nil, err
 		}
 		result[key] = r
+
+		if profiles != nil {
+			profiles[key] = &Profile{Type: aggTypeName(agg), TimeNanos: time.Since(t).Nanoseconds()}
+		}
 	}
 
 	return result, nil
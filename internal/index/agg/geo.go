@@ -0,0 +1,355 @@
+package agg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/geo"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Agg = (*GeohashGridAgg)(nil)
+var _ Agg = (*GeotileGridAgg)(nil)
+var _ Agg = (*GeoDistanceAgg)(nil)
+var _ Agg = (*GeoBoundsAgg)(nil)
+var _ Agg = (*GeoCentroidAgg)(nil)
+
+const (
+	GeohashGridAggDefaultPrecision = 5
+	GeotileGridAggDefaultPrecision = 7
+	GeoGridAggDefaultSize          = 10000
+)
+
+type GeoGridResult struct {
+	Buckets []GeoGridBucket `json:"buckets"`
+}
+
+// GeoGridBucket is a grid cell. Document is counted in every cell containing any of its points
+type GeoGridBucket struct {
+	Key      string                 `json:"key"`
+	DocCount int                    `json:"docCount"`
+	Aggs     map[string]interface{} `json:"aggs,omitempty"`
+}
+
+// GeohashGridAgg groups documents by geohash cells of the precision. Top size cells by document count are returned
+type GeohashGridAgg struct {
+	Field     string `json:"field"`
+	Precision *int   `json:"precision"`
+	Size      int    `json:"size"`
+	Aggs      Aggs   `json:"aggs"`
+}
+
+func (a *GeohashGridAgg) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.Field, validation.Required),
+		validation.Field(&a.Precision, validation.NilOrNotEmpty, validation.Min(1), validation.Max(geo.MaxGeohashPrecision)),
+		validation.Field(&a.Size, validation.Min(0)),
+	)
+}
+
+func (a *GeohashGridAgg) Exec(ctx context.Context, fields Fields, docs *roaring.Bitmap) (interface{}, error) {
+	f, err := field.GetGeoPoint(fields, a.Field)
+	if err != nil || f == nil {
+		return GeoGridResult{}, err
+	}
+
+	precision := GeohashGridAggDefaultPrecision
+	if a.Precision != nil {
+		precision = *a.Precision
+	}
+
+	return geoGrid(ctx, fields, f, docs, a.Size, a.Aggs, func(p geo.Point) string {
+		return geo.EncodeGeohash(p, precision)
+	})
+}
+
+// GeotileGridAgg groups documents by "zoom/x/y" web map tiles of the precision (zoom). Top size tiles by document count are returned
+type GeotileGridAgg struct {
+	Field     string `json:"field"`
+	Precision *int   `json:"precision"`
+	Size      int    `json:"size"`
+	Aggs      Aggs   `json:"aggs"`
+}
+
+func (a *GeotileGridAgg) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.Field, validation.Required),
+		validation.Field(&a.Precision, validation.Min(0), validation.Max(geo.MaxGeotileZoom)),
+		validation.Field(&a.Size, validation.Min(0)),
+	)
+}
+
+func (a *GeotileGridAgg) Exec(ctx context.Context, fields Fields, docs *roaring.Bitmap) (interface{}, error) {
+	f, err := field.GetGeoPoint(fields, a.Field)
+	if err != nil || f == nil {
+		return GeoGridResult{}, err
+	}
+
+	precision := GeotileGridAggDefaultPrecision
+	if a.Precision != nil {
+		precision = *a.Precision
+	}
+
+	return geoGrid(ctx, fields, f, docs, a.Size, a.Aggs, func(p geo.Point) string {
+		return geo.EncodeGeotile(p, precision)
+	})
+}
+
+// geoGrid groups documents by cell keys of their points. Cells are ordered by document count, then by key
+func geoGrid(ctx context.Context, fields Fields, f *field.GeoPoint, docs *roaring.Bitmap, size int, aggs Aggs, cell func(p geo.Point) string) (interface{}, error) {
+	if size == 0 {
+		size = GeoGridAggDefaultSize
+	}
+
+	cells := make(map[string]*roaring.Bitmap)
+	docs.Iterate(func(id uint32) bool {
+		for _, p := range f.Points(id) {
+			key := cell(p)
+			if cells[key] == nil {
+				cells[key] = roaring.New()
+			}
+			cells[key].Add(id)
+		}
+		return true
+	})
+
+	keys := make([]string, 0, len(cells))
+	for key := range cells {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := cells[keys[i]].GetCardinality(), cells[keys[j]].GetCardinality()
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > size {
+		keys = keys[:size]
+	}
+
+	result := GeoGridResult{Buckets: make([]GeoGridBucket, len(keys))}
+	for i, key := range keys {
+		bucket := GeoGridBucket{
+			Key:      key,
+			DocCount: int(cells[key].GetCardinality()),
+		}
+		if len(aggs) > 0 {
+			bucket.Aggs = make(map[string]interface{}, len(aggs))
+			for subKey, subAgg := range aggs {
+				subAggResult, err := subAgg.Exec(ctx, fields, cells[key])
+				if err != nil {
+					return nil, err
+				}
+				bucket.Aggs[subKey] = subAggResult
+			}
+		}
+		result.Buckets[i] = bucket
+	}
+
+	return result, nil
+}
+
+type GeoDistanceResult struct {
+	Buckets []GeoDistanceBucket `json:"buckets"`
+}
+
+type GeoDistanceBucket struct {
+	Key      string                 `json:"key"`
+	DocCount int                    `json:"docCount"`
+	From     *float64               `json:"from"`
+	To       *float64               `json:"to"`
+	Aggs     map[string]interface{} `json:"aggs,omitempty"`
+}
+
+// GeoDistanceAgg groups documents by rings around the origin. Document is counted in a ring if any of its points
+// is at a distance from (inclusive) to (exclusive). Distances are in the unit, meters by default
+type GeoDistanceAgg struct {
+	Field  string                `json:"field"`
+	Origin interface{}           `json:"origin"`
+	Unit   string                `json:"unit"`
+	Ranges []GeoDistanceAggRange `json:"ranges"`
+	Aggs   Aggs                  `json:"aggs"`
+}
+
+func (a *GeoDistanceAgg) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.Field, validation.Required),
+		validation.Field(&a.Origin, validation.NotNil, validation.By(func(value interface{}) error {
+			if value == nil {
+				return nil
+			}
+			_, err := geo.ParsePoint(value)
+			return err
+		})),
+		validation.Field(&a.Unit, validation.By(func(value interface{}) error {
+			if _, ok := geo.DistanceUnits[a.Unit]; a.Unit != "" && !ok {
+				return errs.Errorf("unknown distance unit %q", a.Unit)
+			}
+			return nil
+		})),
+		validation.Field(&a.Ranges, validation.Required, validation.Length(1, 0)),
+	)
+}
+
+type GeoDistanceAggRange struct {
+	Key  string   `json:"key"`
+	From *float64 `json:"from"`
+	To   *float64 `json:"to"`
+}
+
+func (r GeoDistanceAggRange) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Key, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.From, validation.Required.When(r.To == nil), validation.Min(0.0)),
+		validation.Field(&r.To, validation.Required.When(r.From == nil), validation.By(func(value interface{}) error {
+			if r.From != nil && r.To != nil && *r.To <= *r.From {
+				return errs.Errorf("must be greater than from")
+			}
+			return nil
+		})),
+	)
+}
+
+// contains checks if the distance is inside the ring
+func (r GeoDistanceAggRange) contains(distance float64) bool {
+	return (r.From == nil || distance >= *r.From) && (r.To == nil || distance < *r.To)
+}
+
+func (a *GeoDistanceAgg) Exec(ctx context.Context, fields Fields, docs *roaring.Bitmap) (interface{}, error) {
+	f, err := field.GetGeoPoint(fields, a.Field)
+	if err != nil || f == nil {
+		return GeoDistanceResult{}, err
+	}
+	origin, err := geo.ParsePoint(a.Origin)
+	if err != nil {
+		return nil, err
+	}
+	unit := 1.0
+	if a.Unit != "" {
+		unit = geo.DistanceUnits[a.Unit]
+	}
+
+	rangeDocs := make([]*roaring.Bitmap, len(a.Ranges))
+	for i := range rangeDocs {
+		rangeDocs[i] = roaring.New()
+	}
+	docs.Iterate(func(id uint32) bool {
+		for _, p := range f.Points(id) {
+			d := geo.Distance(origin, p) / unit
+			for i, r := range a.Ranges {
+				if r.contains(d) {
+					rangeDocs[i].Add(id)
+				}
+			}
+		}
+		return true
+	})
+
+	result := GeoDistanceResult{Buckets: make([]GeoDistanceBucket, len(a.Ranges))}
+	for i, r := range a.Ranges {
+		bucket := GeoDistanceBucket{
+			Key:      r.Key,
+			DocCount: int(rangeDocs[i].GetCardinality()),
+			From:     r.From,
+			To:       r.To,
+		}
+		if len(a.Aggs) > 0 {
+			bucket.Aggs = make(map[string]interface{}, len(a.Aggs))
+			for key, subAgg := range a.Aggs {
+				subAggResult, err := subAgg.Exec(ctx, fields, rangeDocs[i])
+				if err != nil {
+					return nil, err
+				}
+				bucket.Aggs[key] = subAggResult
+			}
+		}
+		result.Buckets[i] = bucket
+	}
+
+	return result, nil
+}
+
+type GeoBoundsResult struct {
+	Bounds *GeoBounds `json:"bounds"`
+}
+
+type GeoBounds struct {
+	TopLeft     geo.Point `json:"topLeft"`
+	BottomRight geo.Point `json:"bottomRight"`
+}
+
+// GeoBoundsAgg returns the box containing all the document points. Bounds are nil if there are no points
+type GeoBoundsAgg struct {
+	Field string `json:"field"`
+}
+
+func (a *GeoBoundsAgg) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.Field, validation.Required),
+	)
+}
+
+func (a *GeoBoundsAgg) Exec(ctx context.Context, fields Fields, docs *roaring.Bitmap) (interface{}, error) {
+	f, err := field.GetGeoPoint(fields, a.Field)
+	if err != nil || f == nil {
+		return GeoBoundsResult{}, err
+	}
+
+	var points geo.Polygon
+	docs.Iterate(func(id uint32) bool {
+		points = append(points, f.Points(id)...)
+		return true
+	})
+	if len(points) == 0 {
+		return GeoBoundsResult{}, nil
+	}
+
+	r := points.Bounds()
+
+	return GeoBoundsResult{
+		Bounds: &GeoBounds{
+			TopLeft:     geo.Point{Lat: r.MaxLat, Lon: r.MinLon},
+			BottomRight: geo.Point{Lat: r.MinLat, Lon: r.MaxLon},
+		},
+	}, nil
+}
+
+type GeoCentroidResult struct {
+	Location *geo.Point `json:"location"`
+	Count    int        `json:"count"`
+}
+
+// GeoCentroidAgg returns the center of mass of all the document points. Location is nil if there are no points
+type GeoCentroidAgg struct {
+	Field string `json:"field"`
+}
+
+func (a *GeoCentroidAgg) Validate() error {
+	return validation.ValidateStruct(a,
+		validation.Field(&a.Field, validation.Required),
+	)
+}
+
+func (a *GeoCentroidAgg) Exec(ctx context.Context, fields Fields, docs *roaring.Bitmap) (interface{}, error) {
+	f, err := field.GetGeoPoint(fields, a.Field)
+	if err != nil || f == nil {
+		return GeoCentroidResult{}, err
+	}
+
+	var points []geo.Point
+	docs.Iterate(func(id uint32) bool {
+		points = append(points, f.Points(id)...)
+		return true
+	})
+
+	result := GeoCentroidResult{Count: len(points)}
+	if c, ok := geo.Centroid(points); ok {
+		result.Location = &c
+	}
+
+	return result, nil
+}
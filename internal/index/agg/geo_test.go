@@ -0,0 +1,234 @@
+package agg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/geo"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GeoGridAggs_Validate(t *testing.T) {
+	invalid := map[string]Agg{
+		`{}`:                                  new(GeohashGridAgg),
+		`{"field": "point", "precision": 0}`:  new(GeohashGridAgg),
+		`{"field": "point", "precision": 13}`: new(GeohashGridAgg),
+		`{"field": "point", "size": -1}`:      new(GeohashGridAgg),
+		`{"precision": 1}`:                    new(GeotileGridAgg),
+		`{"field": "point", "precision": 30}`: new(GeotileGridAgg),
+	}
+	for req, agg := range invalid {
+		mustUnmarshal(t, req, agg)
+		require.Error(t, validation.Validate(agg), req)
+	}
+
+	valid := map[string]Agg{
+		`{"field": "point"}`:                             new(GeohashGridAgg),
+		`{"field": "point", "precision": 12, "size": 5}`: new(GeohashGridAgg),
+		`{"field": "point", "precision": 0}`:             new(GeotileGridAgg),
+	}
+	for req, agg := range valid {
+		mustUnmarshal(t, req, agg)
+		require.NoError(t, validation.Validate(agg), req)
+	}
+}
+
+func Test_GeohashGridAgg_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := Fields{
+		"point": newTestField(t, schema.TypeGeoPoint,
+			"55.7558,37.6173", // Moscow
+			[]interface{}{"55.7512,37.6184", "59.9343,30.3351"}, // Moscow and Saint Petersburg
+			"51.5074,-0.1278", // London
+		),
+		"keyword": newTestField(t, schema.TypeKeyword, "a", "b", "b", "b"),
+	}
+
+	t.Run("must group documents by cells", func(t *testing.T) {
+		agg := new(GeohashGridAgg)
+		mustUnmarshal(t, `{"field": "point", "precision": 3}`, agg)
+
+		result, err := agg.Exec(ctx, fields, roaring.BitmapOf(1, 2, 3, 4))
+		require.NoError(t, err)
+		require.Equal(t, GeoGridResult{Buckets: []GeoGridBucket{
+			{Key: "ucf", DocCount: 2},
+			{Key: "gcp", DocCount: 1},
+			{Key: "udt", DocCount: 1},
+		}}, result)
+	})
+
+	t.Run("must return top cells with sub-aggs", func(t *testing.T) {
+		agg := new(GeohashGridAgg)
+		mustUnmarshal(t, `{
+			"field": "point",
+			"precision": 1,
+			"size": 1,
+			"aggs": {"keywords": {"type": "terms", "field": "keyword"}}
+		}`, agg)
+
+		result, err := agg.Exec(ctx, fields, roaring.BitmapOf(1, 2, 3))
+		require.NoError(t, err)
+		require.Equal(t, GeoGridResult{Buckets: []GeoGridBucket{
+			{Key: "u", DocCount: 2, Aggs: map[string]interface{}{
+				"keywords": TermsResult{Buckets: []TermsBucket{{Key: "a", DocCount: 1}, {Key: "b", DocCount: 1}}},
+			}},
+		}}, result)
+	})
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		agg := &GeohashGridAgg{Field: "unknown"}
+		result, err := agg.Exec(ctx, fields, roaring.BitmapOf(1))
+		require.NoError(t, err)
+		require.Equal(t, GeoGridResult{}, result)
+	})
+
+	t.Run("must return error if field is not a geo point", func(t *testing.T) {
+		agg := &GeohashGridAgg{Field: "keyword"}
+		_, err := agg.Exec(ctx, fields, roaring.BitmapOf(1))
+		require.Error(t, err)
+	})
+}
+
+func Test_GeotileGridAgg_Exec(t *testing.T) {
+	fields := Fields{"point": newTestField(t, schema.TypeGeoPoint,
+		"55.7558,37.6173", // Moscow
+		[]interface{}{"55.7512,37.6184", "59.9343,30.3351"}, // Moscow and Saint Petersburg
+		"51.5074,-0.1278", // London
+	)}
+
+	agg := new(GeotileGridAgg)
+	mustUnmarshal(t, `{"field": "point", "precision": 2}`, agg)
+
+	result, err := agg.Exec(context.Background(), fields, roaring.BitmapOf(1, 2, 3))
+	require.NoError(t, err)
+	require.Equal(t, GeoGridResult{Buckets: []GeoGridBucket{
+		{Key: "2/2/1", DocCount: 2},
+		{Key: "2/1/1", DocCount: 1},
+	}}, result)
+
+	agg = new(GeotileGridAgg)
+	mustUnmarshal(t, `{"field": "point", "precision": 6}`, agg)
+
+	result, err = agg.Exec(context.Background(), fields, roaring.BitmapOf(1, 2, 3))
+	require.NoError(t, err)
+	require.Equal(t, GeoGridResult{Buckets: []GeoGridBucket{
+		{Key: "6/38/20", DocCount: 2},
+		{Key: "6/31/21", DocCount: 1},
+		{Key: "6/37/18", DocCount: 1},
+	}}, result)
+}
+
+func Test_GeoDistanceAgg_Validate(t *testing.T) {
+	invalid := []string{
+		`{}`,
+		`{"field": "point", "ranges": [{"key": "a", "to": 10}]}`,
+		`{"field": "point", "origin": "1,a", "ranges": [{"key": "a", "to": 10}]}`,
+		`{"field": "point", "origin": "1,2"}`,
+		`{"field": "point", "origin": "1,2", "ranges": [{"to": 10}]}`,
+		`{"field": "point", "origin": "1,2", "ranges": [{"key": "a"}]}`,
+		`{"field": "point", "origin": "1,2", "ranges": [{"key": "a", "from": 10, "to": 5}]}`,
+		`{"field": "point", "origin": "1,2", "ranges": [{"key": "a", "from": -1}]}`,
+		`{"field": "point", "origin": "1,2", "unit": "parsec", "ranges": [{"key": "a", "to": 10}]}`,
+	}
+	for _, req := range invalid {
+		agg := new(GeoDistanceAgg)
+		mustUnmarshal(t, req, agg)
+		require.Error(t, validation.Validate(agg), req)
+	}
+
+	agg := new(GeoDistanceAgg)
+	mustUnmarshal(t, `{"field": "point", "origin": {"lat": 1, "lon": 2}, "unit": "km", "ranges": [{"key": "a", "to": 10}, {"key": "b", "from": 10}]}`, agg)
+	require.NoError(t, validation.Validate(agg))
+}
+
+func Test_GeoDistanceAgg_Exec(t *testing.T) {
+	fields := Fields{
+		"point": newTestField(t, schema.TypeGeoPoint,
+			"55.7558,37.6173", // Moscow
+			[]interface{}{"55.7512,37.6184", "59.9343,30.3351"}, // Moscow and Saint Petersburg
+			"51.5074,-0.1278", // London
+		),
+		"keyword": newTestField(t, schema.TypeKeyword, "a", "b", "b", "b"),
+	}
+
+	agg := new(GeoDistanceAgg)
+	mustUnmarshal(t, `{
+		"field": "point",
+		"origin": "55.75,37.61",
+		"unit": "km",
+		"ranges": [
+			{"key": "near", "to": 10},
+			{"key": "middle", "from": 10, "to": 1000},
+			{"key": "far", "from": 1000}
+		],
+		"aggs": {"keywords": {"type": "terms", "field": "keyword"}}
+	}`, agg)
+
+	result, err := agg.Exec(context.Background(), fields, roaring.BitmapOf(1, 2, 3, 4))
+	require.NoError(t, err)
+
+	buckets := result.(GeoDistanceResult).Buckets
+	require.Len(t, buckets, 3)
+	require.Equal(t, "near", buckets[0].Key)
+	require.Equal(t, 2, buckets[0].DocCount)
+	require.Nil(t, buckets[0].From)
+	require.Equal(t, 10.0, *buckets[0].To)
+	require.Equal(t, "middle", buckets[1].Key)
+	require.Equal(t, 1, buckets[1].DocCount)
+	require.Equal(t, TermsResult{Buckets: []TermsBucket{{Key: "b", DocCount: 1}}}, buckets[1].Aggs["keywords"])
+	require.Equal(t, "far", buckets[2].Key)
+	require.Equal(t, 1, buckets[2].DocCount)
+}
+
+func Test_GeoBoundsAgg_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := Fields{"point": newTestField(t, schema.TypeGeoPoint,
+		"55.7558,37.6173", // Moscow
+		[]interface{}{"55.7512,37.6184", "59.9343,30.3351"}, // Moscow and Saint Petersburg
+		"51.5074,-0.1278", // London
+	)}
+
+	agg := new(GeoBoundsAgg)
+	mustUnmarshal(t, `{"field": "point"}`, agg)
+	require.NoError(t, validation.Validate(agg))
+	require.Error(t, validation.Validate(new(GeoBoundsAgg)))
+
+	result, err := agg.Exec(ctx, fields, roaring.BitmapOf(1, 2, 3))
+	require.NoError(t, err)
+	require.Equal(t, GeoBoundsResult{Bounds: &GeoBounds{
+		TopLeft:     geo.Point{Lat: 59.9343, Lon: -0.1278},
+		BottomRight: geo.Point{Lat: 51.5074, Lon: 37.6184},
+	}}, result)
+
+	result, err = agg.Exec(ctx, fields, roaring.BitmapOf(4))
+	require.NoError(t, err)
+	require.Equal(t, GeoBoundsResult{}, result)
+}
+
+func Test_GeoCentroidAgg_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := Fields{"point": newTestField(t, schema.TypeGeoPoint,
+		"55.7558,37.6173", // Moscow
+		[]interface{}{"55.7512,37.6184", "59.9343,30.3351"}, // Moscow and Saint Petersburg
+		"51.5074,-0.1278", // London
+	)}
+
+	agg := new(GeoCentroidAgg)
+	mustUnmarshal(t, `{"field": "point"}`, agg)
+	require.NoError(t, validation.Validate(agg))
+	require.Error(t, validation.Validate(new(GeoCentroidAgg)))
+
+	result, err := agg.Exec(ctx, fields, roaring.BitmapOf(1, 2))
+	require.NoError(t, err)
+	centroid := result.(GeoCentroidResult)
+	require.Equal(t, 3, centroid.Count)
+	require.InDelta(t, 57.15, centroid.Location.Lat, 0.1)
+	require.InDelta(t, 35.4, centroid.Location.Lon, 0.1)
+
+	result, err = agg.Exec(ctx, fields, roaring.BitmapOf(4))
+	require.NoError(t, err)
+	require.Equal(t, GeoCentroidResult{}, result)
+}
@@ -0,0 +1,37 @@
+package agg
+
+import (
+	"context"
+	"reflect"
+)
+
+// Profile contains aggregation execution time including its sub-aggregations
+type Profile struct {
+	Type      string `json:"type"`
+	TimeNanos int64  `json:"timeNanos"`
+}
+
+// Profiles aggregation profiles by aggregation name
+type Profiles map[string]*Profile
+
+type profileKey struct{}
+
+// WithProfile enables aggregations profiling. Profiles of aggregations executed with the returned context are added to the returned map
+func WithProfile(ctx context.Context) (context.Context, Profiles) {
+	p := make(Profiles)
+	return context.WithValue(ctx, profileKey{}, p), p
+}
+
+func profilesFromCtx(ctx context.Context) Profiles {
+	p, _ := ctx.Value(profileKey{}).(Profiles)
+	return p
+}
+
+func aggTypeName(a Agg) string {
+	t := reflect.TypeOf(a)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,42 @@
+package agg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exec_Profile(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "foo")
+	f.Add(2, "bar")
+
+	req := make(AggsRequest)
+	mustUnmarshal(t, `{
+		"terms": {"type": "terms", "field": "field", "size": 10},
+		"filter": {"type": "filter", "filter": {"type": "term", "field": "field", "query": "foo"}}
+	}`, &req)
+
+	t.Run("must add profile of each aggregation", func(t *testing.T) {
+		ctx, profiles := WithProfile(context.Background())
+		result, err := Exec(ctx, roaring.BitmapOf(1, 2), req, Fields{"field": f})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		require.Len(t, profiles, 2)
+		require.Equal(t, "TermsAgg", profiles["terms"].Type)
+		require.Equal(t, "FilterAgg", profiles["filter"].Type)
+		require.Greater(t, profiles["terms"].TimeNanos, int64(0))
+	})
+
+	t.Run("must not profile if profiling is disabled", func(t *testing.T) {
+		result, err := Exec(context.Background(), roaring.BitmapOf(1, 2), req, Fields{"field": f})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+}
@@ -0,0 +1,86 @@
+package index
+
+import (
+	"strings"
+
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Analyze analyze request. Text is analyzed by the field analyzer or by the inline chain
+type Analyze struct {
+	Field       string              `json:"field"`
+	CharFilters []schema.CharFilter `json:"charFilters"`
+	Analyzers   []schema.Analyzer   `json:"analyzers"`
+	Text        string              `json:"text"`
+}
+
+func (a Analyze) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Field, validation.When(len(a.Analyzers) == 0, validation.Required).Else(validation.Empty)),
+		validation.Field(&a.CharFilters, validation.When(a.Field != "", validation.Empty)),
+		validation.Field(&a.Analyzers),
+	)
+}
+
+type AnalyzeResult struct {
+	Stages []schema.AnalyzeStage `json:"stages"`
+}
+
+// Analyze returns tokens produced by each stage of the analyzer chain
+func (d *Documents) Analyze(index Index, req Analyze) (AnalyzeResult, error) {
+	fieldIndex, err := d.fields.GetIndex(index.Name)
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+
+	fa := schema.FieldAnalyzer{CharFilters: req.CharFilters, Analyzers: req.Analyzers}
+	if req.Field != "" {
+		fa, err = fieldAnalyzer(index.Schema, req.Field)
+		if err != nil {
+			return AnalyzeResult{}, err
+		}
+	}
+
+	stages, err := fa.Analyze(req.Text, fieldIndex.AnalyzerOpts())
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+
+	return AnalyzeResult{Stages: stages}, nil
+}
+
+func fieldAnalyzer(s schema.Schema, name string) (schema.FieldAnalyzer, error) {
+	f, ok := schemaField(s, name)
+	if !ok {
+		return schema.FieldAnalyzer{}, validation.Errors{
+			"field": validation.NewError("validation_field_not_found", "field not found"),
+		}
+	}
+
+	if f.Analyzer != "" {
+		return s.Analyzers[f.Analyzer], nil
+	}
+	if f.Normalizer != "" {
+		return s.Analyzers[f.Normalizer], nil
+	}
+	if f.Type == schema.TypeText {
+		return schema.DefaultTextAnalyzer(), nil
+	}
+
+	return schema.FieldAnalyzer{}, validation.Errors{
+		"field": validation.NewError("validation_field_not_analyzed", "field has no analyzer"),
+	}
+}
+
+// schemaField finds field or sub-field ("field.sub") by name
+func schemaField(s schema.Schema, name string) (schema.Field, bool) {
+	f, ok := s.Fields[name]
+	if !ok {
+		if parent, subName, found := strings.Cut(name, "."); found {
+			f, ok = s.Fields[parent].Fields[subName]
+		}
+	}
+
+	return f, ok
+}
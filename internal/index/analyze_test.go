@@ -0,0 +1,101 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Analyze_Validate(t *testing.T) {
+	t.Run("must fail if neither field nor analyzers provided", func(t *testing.T) {
+		require.Error(t, validation.Validate(Analyze{Text: "text"}))
+	})
+
+	t.Run("must fail if both field and analyzers provided", func(t *testing.T) {
+		require.Error(t, validation.Validate(Analyze{
+			Field:     "text",
+			Analyzers: []schema.Analyzer{{Type: schema.TokenizerWhitespace}},
+		}))
+	})
+
+	t.Run("must fail if analyzer is invalid", func(t *testing.T) {
+		require.Error(t, validation.Validate(Analyze{Analyzers: []schema.Analyzer{{Type: "invalid"}}}))
+	})
+
+	t.Run("must not fail for valid request", func(t *testing.T) {
+		require.NoError(t, validation.Validate(Analyze{Field: "text", Text: "text"}))
+	})
+}
+
+func Test_Documents_Analyze(t *testing.T) {
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"text": {Type: schema.TypeText, Analyzer: "analyzer"},
+				"default": {Type: schema.TypeText, Fields: map[string]schema.Field{
+					"upper": {Type: schema.TypeText, Analyzer: "analyzer"},
+				}},
+				"bool": {Type: schema.TypeBool},
+			},
+			map[string]schema.FieldAnalyzer{
+				"analyzer": {Analyzers: []schema.Analyzer{
+					{Type: schema.TokenizerWhitespace},
+					{Type: schema.FilterUppercase},
+				}},
+			},
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	t.Run("must analyze text with field analyzer", func(t *testing.T) {
+		result, err := docs.Analyze(i, Analyze{Field: "text", Text: "hello world"})
+		require.NoError(t, err)
+		require.Len(t, result.Stages, 2)
+		require.Equal(t, "uppercase", result.Stages[1].Type)
+		require.Equal(t, []schema.AnalyzeToken{
+			{Token: "HELLO", Position: 0, StartOffset: 0, EndOffset: 5},
+			{Token: "WORLD", Position: 1, StartOffset: 6, EndOffset: 11},
+		}, result.Stages[1].Tokens)
+	})
+
+	t.Run("must analyze text with default text analyzer", func(t *testing.T) {
+		result, err := docs.Analyze(i, Analyze{Field: "default", Text: "Hello, World"})
+		require.NoError(t, err)
+		require.Len(t, result.Stages, 2)
+		require.Equal(t, "hello", result.Stages[1].Tokens[0].Token)
+	})
+
+	t.Run("must analyze text with sub-field analyzer", func(t *testing.T) {
+		result, err := docs.Analyze(i, Analyze{Field: "default.upper", Text: "hello"})
+		require.NoError(t, err)
+		require.Len(t, result.Stages, 2)
+		require.Equal(t, "HELLO", result.Stages[1].Tokens[0].Token)
+	})
+
+	t.Run("must analyze text with inline analyzers", func(t *testing.T) {
+		result, err := docs.Analyze(i, Analyze{
+			CharFilters: []schema.CharFilter{{Type: schema.CharFilterHTMLStrip}},
+			Analyzers:   []schema.Analyzer{{Type: schema.TokenizerWhitespace}},
+			Text:        "<b>hello</b>",
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Stages, 2)
+		require.Equal(t, "html_strip", result.Stages[0].Type)
+		require.Equal(t, []schema.AnalyzeToken{{Token: "hello", Position: 0, StartOffset: 3, EndOffset: 8}}, result.Stages[1].Tokens)
+	})
+
+	t.Run("must fail if field does not exist", func(t *testing.T) {
+		_, err := docs.Analyze(i, Analyze{Field: "unknown", Text: "text"})
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if field is not analyzed", func(t *testing.T) {
+		_, err := docs.Analyze(i, Analyze{Field: "bool", Text: "text"})
+		require.Error(t, err)
+	})
+}
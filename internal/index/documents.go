@@ -37,6 +37,20 @@ func (d *Documents) DeleteIndex(name string) error {
 	return nil
 }
 
+// ReloadAnalyzers rebuilds index analyzers without reindexing documents
+func (d *Documents) ReloadAnalyzers(index Index) error {
+	fieldIndex, err := d.fields.GetIndex(index.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := fieldIndex.ReloadAnalyzers(); err != nil {
+		return errs.Errorf("analyzers reload err: %w", err)
+	}
+
+	return nil
+}
+
 func (d *Documents) Add(index Index, guid string, source DocSource) (string, error) {
 	if guid == "" {
 		guid = newGUID()
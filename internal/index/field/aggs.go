@@ -14,6 +14,14 @@ func minInt(v1 int, v2 int) int {
 	return v1
 }
 
+func maxInt(v1 int, v2 int) int {
+	if v1 < v2 {
+		return v2
+	}
+
+	return v1
+}
+
 var _ heap.Interface = (*termHeap[bool])(nil)
 
 type keyValue[T comparable] struct {
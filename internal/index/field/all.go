@@ -47,6 +47,14 @@ func (f *All) RangeQuery(ctx context.Context, from interface{}, to interface{},
 	return newResult(ctx, roaring.New())
 }
 
+func (f *All) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *All) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.data.Clone())
+}
+
 func (f *All) DeleteDoc(id uint32) {
 	f.data.Remove(id)
 }
@@ -52,6 +52,14 @@ func (f *Bool) RangeQuery(ctx context.Context, from interface{}, to interface{},
 	return newResult(ctx, roaring.New())
 }
 
+func (f *Bool) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *Bool) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.values.AllDocs())
+}
+
 func (f *Bool) DeleteDoc(id uint32) {
 	f.values.DeleteDoc(id)
 }
@@ -0,0 +1,395 @@
+package field
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/gob"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/spf13/cast"
+)
+
+var _ Field = (*Completion)(nil)
+
+const defaultCompletionWeight = 1
+
+// Completion contains weighted inputs and is used for prefix completion (typeahead).
+// Suggestions are taken from the index of inputs sorted by normalized values, so inputs with the same prefix are adjacent.
+// The index is built on demand after inputs are changed.
+type Completion struct {
+	mtx        sync.RWMutex
+	normalizer func([]string) []string
+	// values contain normalized inputs and are used by queries and aggregations
+	values *docValues[string]
+	inputs map[uint32][]completionEntry
+	index  *completionIndex
+}
+
+type completionEntry struct {
+	Key    string
+	Input  string
+	Weight int
+	ID     uint32
+}
+
+// CompletionOption completion suggestion
+type CompletionOption struct {
+	Text   string
+	Weight int
+	ID     uint32
+}
+
+func newCompletion(normalizer func([]string) []string) *Completion {
+	return &Completion{
+		values:     newDocValues[string](),
+		inputs:     make(map[uint32][]completionEntry),
+		normalizer: normalizer,
+	}
+}
+
+func (f *Completion) Type() schema.Type {
+	return schema.TypeCompletion
+}
+
+// Add adds document inputs. Value is a string, an object {"input": "value", "weight": 1}
+// where input may be a list of strings, or a list of strings and objects
+func (f *Completion) Add(id uint32, value interface{}) {
+	inputs := parseCompletionInputs(value)
+	if len(inputs) == 0 {
+		return
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for _, e := range inputs {
+		key, ok := f.normalize(e.Input)
+		if !ok {
+			continue
+		}
+		e.Key = key
+		e.ID = id
+
+		f.inputs[id] = append(f.inputs[id], e)
+		f.values.Add(id, key)
+	}
+	f.index = nil
+}
+
+func parseCompletionInputs(value interface{}) []completionEntry {
+	switch v := value.(type) {
+	case string:
+		return []completionEntry{{Input: v, Weight: defaultCompletionWeight}}
+	case []string:
+		result := make([]completionEntry, len(v))
+		for i, input := range v {
+			result[i] = completionEntry{Input: input, Weight: defaultCompletionWeight}
+		}
+		return result
+	case []interface{}:
+		var result []completionEntry
+		for _, item := range v {
+			result = append(result, parseCompletionInputs(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		weight := defaultCompletionWeight
+		if w, ok := v["weight"]; ok {
+			var err error
+			weight, err = cast.ToIntE(w)
+			if err != nil || weight < 0 {
+				return nil
+			}
+		}
+
+		var inputs []string
+		switch input := v["input"].(type) {
+		case string:
+			inputs = []string{input}
+		case []string:
+			inputs = input
+		case []interface{}:
+			for _, item := range input {
+				if s, ok := item.(string); ok {
+					inputs = append(inputs, s)
+				}
+			}
+		}
+
+		result := make([]completionEntry, len(inputs))
+		for i, input := range inputs {
+			result[i] = completionEntry{Input: input, Weight: weight}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// normalize applies normalizer to the value. False is returned if nothing is left after normalization
+func (f *Completion) normalize(v string) (string, bool) {
+	if f.normalizer == nil {
+		return v, true
+	}
+
+	result := f.normalizer([]string{v})
+	if len(result) == 0 {
+		return "", false
+	}
+
+	return result[0], true
+}
+
+func (f *Completion) TermQuery(ctx context.Context, value interface{}) *QueryResult {
+	v, err := cast.ToStringE(value)
+	if err != nil {
+		return newResult(ctx, roaring.New())
+	}
+	v, ok := f.normalize(v)
+	if !ok {
+		return newResult(ctx, roaring.New())
+	}
+
+	return newResult(ctx, f.values.DocsByValue(v))
+}
+
+func (f *Completion) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
+	return f.TermQuery(ctx, value)
+}
+
+func (f *Completion) RangeQuery(ctx context.Context, from interface{}, to interface{}, incFrom, incTo bool) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *Completion) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, expandPattern(f.values, pattern, maxExpansions))
+}
+
+func (f *Completion) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.values.AllDocs())
+}
+
+// Suggest returns top weighted inputs starting with the prefix. Every document is suggested once by its top weighted input.
+// Options with equal weights are ordered by their inputs
+func (f *Completion) Suggest(prefix string, size int) []CompletionOption {
+	prefix, ok := f.normalize(prefix)
+	if !ok || size <= 0 {
+		return nil
+	}
+
+	f.mtx.RLock()
+	index := f.index
+	f.mtx.RUnlock()
+	if index == nil {
+		f.mtx.Lock()
+		if f.index == nil {
+			f.index = newCompletionIndex(f.inputs)
+		}
+		index = f.index
+		f.mtx.Unlock()
+	}
+
+	return index.top(prefix, size)
+}
+
+func (f *Completion) DeleteDoc(id uint32) {
+	if !f.values.ContainsDoc(id) {
+		return
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	delete(f.inputs, id)
+	f.values.DeleteDoc(id)
+	f.index = nil
+}
+
+func (f *Completion) Data(id uint32) []interface{} {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	result := make([]interface{}, len(f.inputs[id]))
+	for i, e := range f.inputs[id] {
+		result[i] = e.Input
+	}
+
+	return result
+}
+
+func (f *Completion) MinValue() (interface{}, *roaring.Bitmap) {
+	return f.values.MinValue()
+}
+
+func (f *Completion) MaxValue() (interface{}, *roaring.Bitmap) {
+	return f.values.MaxValue()
+}
+
+func (f *Completion) TermAgg(ctx context.Context, docs *roaring.Bitmap, size int) TermAggResult {
+	return termAgg(docs, f.values, size)
+}
+
+type completionData struct {
+	Values *docValues[string]
+	Inputs map[uint32][]completionEntry
+}
+
+func (f *Completion) MarshalBinary() ([]byte, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(completionData{Values: f.values, Inputs: f.inputs})
+
+	return buf.Bytes(), err
+}
+
+func (f *Completion) UnmarshalBinary(data []byte) error {
+	raw := completionData{}
+	buf := bytes.NewBuffer(data)
+	err := gob.NewDecoder(buf).Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.values = raw.Values
+	f.inputs = raw.Inputs
+	if f.inputs == nil {
+		f.inputs = make(map[uint32][]completionEntry)
+	}
+	f.index = nil
+
+	return nil
+}
+
+// completionIndex is an immutable list of entries sorted by keys.
+// best[k][i] is the position of the top weighted entry in range [i, i+2^k) (sparse table),
+// so the top entry of any range is found in constant time
+type completionIndex struct {
+	entries []completionEntry
+	best    [][]int
+}
+
+func newCompletionIndex(inputs map[uint32][]completionEntry) *completionIndex {
+	result := new(completionIndex)
+	for _, entries := range inputs {
+		result.entries = append(result.entries, entries...)
+	}
+	sort.Slice(result.entries, func(i, j int) bool {
+		a, b := result.entries[i], result.entries[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Input != b.Input {
+			return a.Input < b.Input
+		}
+		return a.ID < b.ID
+	})
+
+	n := len(result.entries)
+	if n == 0 {
+		return result
+	}
+
+	levels := bits.Len(uint(n))
+	result.best = make([][]int, levels)
+	result.best[0] = make([]int, n)
+	for i := range result.best[0] {
+		result.best[0][i] = i
+	}
+	for k := 1; k < levels; k++ {
+		size := n - (1 << k) + 1
+		result.best[k] = make([]int, size)
+		for i := 0; i < size; i++ {
+			result.best[k][i] = result.better(result.best[k-1][i], result.best[k-1][i+(1<<(k-1))])
+		}
+	}
+
+	return result
+}
+
+// better returns the position of the entry with greater weight. Entries with lower keys are preferred if weights are equal
+func (c *completionIndex) better(i, j int) int {
+	if c.entries[j].Weight > c.entries[i].Weight {
+		return j
+	}
+
+	return i
+}
+
+// topInRange returns the position of the top entry in range [from, to)
+func (c *completionIndex) topInRange(from, to int) int {
+	k := bits.Len(uint(to-from)) - 1
+	return c.better(c.best[k][from], c.best[k][to-(1<<k)])
+}
+
+func (c *completionIndex) top(prefix string, size int) []CompletionOption {
+	from := sort.Search(len(c.entries), func(i int) bool { return c.entries[i].Key >= prefix })
+	to := from + sort.Search(len(c.entries)-from, func(i int) bool { return !strings.HasPrefix(c.entries[from+i].Key, prefix) })
+	if from == to {
+		return nil
+	}
+
+	// the top entry of a range is taken, then the rest of the range is split into two ranges around it
+	ranges := &completionRanges{index: c}
+	heap.Push(ranges, completionRange{from: from, to: to, top: c.topInRange(from, to)})
+
+	result := make([]CompletionOption, 0, size)
+	seen := make(map[uint32]struct{}, size)
+	for ranges.Len() > 0 && len(result) < size {
+		r := heap.Pop(ranges).(completionRange)
+		e := c.entries[r.top]
+		if _, ok := seen[e.ID]; !ok {
+			seen[e.ID] = struct{}{}
+			result = append(result, CompletionOption{Text: e.Input, Weight: e.Weight, ID: e.ID})
+		}
+
+		if r.from < r.top {
+			heap.Push(ranges, completionRange{from: r.from, to: r.top, top: c.topInRange(r.from, r.top)})
+		}
+		if r.top+1 < r.to {
+			heap.Push(ranges, completionRange{from: r.top + 1, to: r.to, top: c.topInRange(r.top+1, r.to)})
+		}
+	}
+
+	return result
+}
+
+type completionRange struct {
+	from int
+	to   int
+	top  int
+}
+
+// completionRanges is a max heap of ranges by their top entries
+type completionRanges struct {
+	index  *completionIndex
+	ranges []completionRange
+}
+
+func (h *completionRanges) Len() int { return len(h.ranges) }
+func (h *completionRanges) Less(i, j int) bool {
+	a, b := h.index.entries[h.ranges[i].top], h.index.entries[h.ranges[j].top]
+	if a.Weight != b.Weight {
+		return a.Weight > b.Weight
+	}
+	return h.ranges[i].top < h.ranges[j].top
+}
+func (h *completionRanges) Swap(i, j int)      { h.ranges[i], h.ranges[j] = h.ranges[j], h.ranges[i] }
+func (h *completionRanges) Push(x interface{}) { h.ranges = append(h.ranges, x.(completionRange)) }
+func (h *completionRanges) Pop() interface{} {
+	old := h.ranges
+	n := len(old)
+	x := old[n-1]
+	h.ranges = old[:n-1]
+	return x
+}
@@ -0,0 +1,150 @@
+package field
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Completion_Add(t *testing.T) {
+	field := newCompletion(nil)
+	field.Add(1, "foo")
+	field.Add(2, []interface{}{"bar", map[string]interface{}{"input": "baz", "weight": 5}})
+	field.Add(3, map[string]interface{}{"input": []interface{}{"qux", "quux"}, "weight": 2})
+	field.Add(4, true)
+
+	require.Equal(t, []interface{}{"foo"}, field.Data(1))
+	require.Equal(t, []interface{}{"bar", "baz"}, field.Data(2))
+	require.Equal(t, []interface{}{"qux", "quux"}, field.Data(3))
+	require.Equal(t, []interface{}{}, field.Data(4))
+	require.Equal(t, []completionEntry{{Key: "qux", Input: "qux", Weight: 2, ID: 3}, {Key: "quux", Input: "quux", Weight: 2, ID: 3}}, field.inputs[3])
+}
+
+func Test_Completion_TermQuery(t *testing.T) {
+	field := newCompletion(nil)
+	field.Add(1, "foo")
+	field.Add(2, "bar")
+
+	result := field.TermQuery(context.Background(), "foo")
+	require.Equal(t, []uint32{1}, result.Docs().ToArray())
+}
+
+func Test_Completion_Suggest(t *testing.T) {
+	field := newCompletion(nil)
+	field.Add(1, map[string]interface{}{"input": "iphone 13", "weight": 10})
+	field.Add(2, map[string]interface{}{"input": "iphone 14", "weight": 20})
+	field.Add(3, map[string]interface{}{"input": []interface{}{"ipad", "iphone case"}, "weight": 5})
+	field.Add(4, "android")
+
+	t.Run("must return top weighted inputs by prefix", func(t *testing.T) {
+		require.Equal(t, []CompletionOption{
+			{Text: "iphone 14", Weight: 20, ID: 2},
+			{Text: "iphone 13", Weight: 10, ID: 1},
+		}, field.Suggest("iph", 2))
+	})
+
+	t.Run("must suggest every document once", func(t *testing.T) {
+		require.Equal(t, []CompletionOption{
+			{Text: "iphone 14", Weight: 20, ID: 2},
+			{Text: "iphone 13", Weight: 10, ID: 1},
+			{Text: "ipad", Weight: 5, ID: 3},
+		}, field.Suggest("i", 10))
+	})
+
+	t.Run("must return nothing if prefix not found", func(t *testing.T) {
+		require.Empty(t, field.Suggest("x", 10))
+	})
+
+	t.Run("must not return deleted documents", func(t *testing.T) {
+		field := newCompletion(nil)
+		field.Add(1, "foo")
+		field.Add(2, "foobar")
+		require.Len(t, field.Suggest("foo", 10), 2)
+
+		field.DeleteDoc(1)
+		require.Equal(t, []CompletionOption{{Text: "foobar", Weight: 1, ID: 2}}, field.Suggest("foo", 10))
+	})
+
+	t.Run("must apply normalizer to inputs and prefix", func(t *testing.T) {
+		normalizer, err := schema.FieldAnalyzer{Analyzers: []schema.Analyzer{{Type: schema.FilterLowercase}}}.Build()
+		require.NoError(t, err)
+
+		field := newCompletion(normalizer)
+		field.Add(1, "Hello World")
+		require.Equal(t, []CompletionOption{{Text: "Hello World", Weight: 1, ID: 1}}, field.Suggest("HEL", 10))
+	})
+
+	t.Run("must return the same result as full scan", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		field := newCompletion(nil)
+		type input struct {
+			text   string
+			weight int
+			id     uint32
+		}
+		var inputs []input
+		for id := uint32(1); id <= 500; id++ {
+			for j := 0; j < 1+rnd.Intn(3); j++ {
+				text := fmt.Sprintf("%c%c%d", 'a'+rune(rnd.Intn(3)), 'a'+rune(rnd.Intn(3)), rnd.Intn(100))
+				weight := rnd.Intn(50)
+				inputs = append(inputs, input{text: text, weight: weight, id: id})
+				field.Add(id, map[string]interface{}{"input": text, "weight": weight})
+			}
+		}
+
+		for _, prefix := range []string{"", "a", "ab", "cc1", "zz"} {
+			var matched []input
+			for _, i := range inputs {
+				if strings.HasPrefix(i.text, prefix) {
+					matched = append(matched, i)
+				}
+			}
+			sort.Slice(matched, func(i, j int) bool {
+				a, b := matched[i], matched[j]
+				if a.weight != b.weight {
+					return a.weight > b.weight
+				}
+				if a.text != b.text {
+					return a.text < b.text
+				}
+				return a.id < b.id
+			})
+
+			var expected []CompletionOption
+			seen := make(map[uint32]struct{})
+			for _, i := range matched {
+				if _, ok := seen[i.id]; ok {
+					continue
+				}
+				seen[i.id] = struct{}{}
+				expected = append(expected, CompletionOption{Text: i.text, Weight: i.weight, ID: i.id})
+				if len(expected) == 20 {
+					break
+				}
+			}
+
+			require.Equal(t, expected, field.Suggest(prefix, 20), "prefix %q", prefix)
+		}
+	})
+}
+
+func Test_Completion_Marshal(t *testing.T) {
+	field := newCompletion(nil)
+	field.Add(1, map[string]interface{}{"input": "foo", "weight": 3})
+	field.Add(2, "foobar")
+
+	data, err := field.MarshalBinary()
+	require.NoError(t, err)
+
+	field2 := newCompletion(nil)
+	err = field2.UnmarshalBinary(data)
+	require.NoError(t, err)
+	require.Equal(t, field.Suggest("foo", 10), field2.Suggest("foo", 10))
+	require.True(t, field2.values.DocsByValue("foobar").Contains(2))
+}
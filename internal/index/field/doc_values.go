@@ -64,6 +64,19 @@ func (v *docValues[T]) ContainsDocValue(id uint32, value T) bool {
 	return ok
 }
 
+func (v *docValues[T]) AllDocs() *roaring.Bitmap {
+	v.mtx.RLock()
+	defer v.mtx.RUnlock()
+
+	result := roaring.New()
+	for id, vals := range v.Values {
+		if len(vals) > 0 {
+			result.Add(id)
+		}
+	}
+	return result
+}
+
 func (v *docValues[T]) ValuesByDoc(id uint32) []T {
 	v.mtx.RLock()
 	defer v.mtx.RUnlock()
@@ -56,6 +56,19 @@ func Test_docValues_ContainsDocValue(t *testing.T) {
 	require.False(t, v.ContainsDocValue(1, 2))
 }
 
+func Test_docValues_AllDocs(t *testing.T) {
+	v := newDocValues[int32]()
+	require.True(t, v.AllDocs().IsEmpty())
+
+	v.Add(1, 1)
+	v.Add(1, 2)
+	v.Add(3, 2)
+	require.ElementsMatch(t, []uint32{1, 3}, v.AllDocs().ToArray())
+
+	v.DeleteDoc(1)
+	require.ElementsMatch(t, []uint32{3}, v.AllDocs().ToArray())
+}
+
 func Test_docValues_ValuesByDoc(t *testing.T) {
 	v := newDocValues[int32]()
 	v.Add(1, 1)
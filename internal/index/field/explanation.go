@@ -0,0 +1,40 @@
+package field
+
+// Explanation describes how a document score is calculated
+type Explanation struct {
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details,omitempty"`
+}
+
+func NewExplanation(value float64, description string, details ...Explanation) Explanation {
+	return Explanation{
+		Value:       value,
+		Description: description,
+		Details:     details,
+	}
+}
+
+// NoMatchExplanation is returned for documents not matching the query
+func NoMatchExplanation() Explanation {
+	return NewExplanation(0, "no matching documents")
+}
+
+// SumExplanation sums values of the details
+func SumExplanation(description string, details ...Explanation) Explanation {
+	var value float64
+	for _, d := range details {
+		value += d.Value
+	}
+
+	return NewExplanation(value, description, details...)
+}
+
+// withBoostExplanation multiplies explanation value by boost
+func withBoostExplanation(e Explanation, boost float64) Explanation {
+	if boost == 1 {
+		return e
+	}
+
+	return NewExplanation(e.Value*boost, "product of:", e, NewExplanation(boost, "boost"))
+}
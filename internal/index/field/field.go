@@ -11,8 +11,14 @@ import (
 )
 
 type FieldOpts struct {
-	Analyzer func([]string) []string
-	Scoring  *Scoring
+	Analyzer       func([]string) []string
+	SearchAnalyzer func([]string) []string
+	Normalizer     func([]string) []string
+	Scoring        *Scoring
+	Similarity     Similarity
+	// Dims and VectorSimilarity are dense vector options
+	Dims             int
+	VectorSimilarity VectorSimilarity
 }
 
 type Range struct {
@@ -35,6 +41,10 @@ type Field interface {
 	MatchQuery(ctx context.Context, value interface{}) *QueryResult
 	// RangeQuery get documents by values from .. to ...
 	RangeQuery(ctx context.Context, from interface{}, to interface{}, incFrom, incTo bool) *QueryResult
+	// PatternQuery get documents by values matching the pattern. At most maxExpansions values are used if maxExpansions > 0
+	PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult
+	// ExistsQuery get documents having any value
+	ExistsQuery(ctx context.Context) *QueryResult
 	// Delete document field values
 	DeleteDoc(id uint32)
 	// Data get stored field values
@@ -49,6 +59,12 @@ type Field interface {
 	TermAgg(ctx context.Context, docs *roaring.Bitmap, size int) TermAggResult
 }
 
+// analyzedField is implemented by fields which values are analyzed
+type analyzedField interface {
+	// setAnalyzers replaces field analyzers. Analyzer is used as search analyzer if the latter is nil
+	setAnalyzers(analyzer func([]string) []string, searchAnalyzer func([]string) []string)
+}
+
 func New(t schema.Type, opts ...FieldOpts) (Field, error) {
 	var field Field
 
@@ -58,12 +74,38 @@ func New(t schema.Type, opts ...FieldOpts) (Field, error) {
 	case schema.TypeBool:
 		field = newBool()
 	case schema.TypeKeyword:
-		field = newKeyword()
+		var normalizer func([]string) []string
+		if len(opts) > 0 {
+			normalizer = opts[0].Normalizer
+		}
+		field = newKeyword(normalizer)
+	case schema.TypeCompletion:
+		var normalizer func([]string) []string
+		if len(opts) > 0 {
+			normalizer = opts[0].Normalizer
+		}
+		field = newCompletion(normalizer)
 	case schema.TypeText:
 		if len(opts) == 0 || opts[0].Scoring == nil {
 			return nil, errs.Errorf("field scoring data required, but not provided")
 		}
-		field = newText(opts[0].Analyzer, opts[0].Scoring)
+		text := newText(opts[0].Analyzer, opts[0].Scoring)
+		text.setAnalyzers(opts[0].Analyzer, opts[0].SearchAnalyzer)
+		if opts[0].Similarity != nil {
+			text.similarity = opts[0].Similarity
+		}
+		field = text
+	case schema.TypeDenseVector:
+		if len(opts) == 0 || opts[0].Dims <= 0 {
+			return nil, errs.Errorf("field dims required, but not provided")
+		}
+		similarity := opts[0].VectorSimilarity
+		if similarity == nil {
+			similarity = CosineSimilarity{}
+		}
+		field = newDenseVector(opts[0].Dims, similarity)
+	case schema.TypeGeoPoint:
+		field = newGeoPoint()
 	// @todo implement slice type
 	// case schema.TypeSlice:
 	// 	i.fields[f.Name] = field.NewSlice()
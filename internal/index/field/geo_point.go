@@ -0,0 +1,242 @@
+package field
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"math"
+	"sync"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/geo"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Field = (*GeoPoint)(nil)
+
+// geoMaxCoverCells max number of geohash cells used to find query candidates
+const geoMaxCoverCells = 64
+
+// GeoPoint contains geographic points. Every point is indexed by its geohash prefixes of all precisions,
+// so documents inside a cell are found by a single term. Geo queries take candidates from the cells covering
+// the query shape and check their points exactly
+type GeoPoint struct {
+	mtx    sync.RWMutex
+	points map[uint32][]geo.Point
+	values *docValues[string]
+}
+
+func newGeoPoint() *GeoPoint {
+	return &GeoPoint{
+		points: make(map[uint32][]geo.Point),
+		values: newDocValues[string](),
+	}
+}
+
+// GetGeoPoint returns nil if field is not found and error if it is not a geo_point field
+func GetGeoPoint(fields map[string]Field, name string) (*GeoPoint, error) {
+	f, ok := fields[name]
+	if !ok {
+		return nil, nil
+	}
+	gf, ok := f.(*GeoPoint)
+	if !ok {
+		return nil, validation.Errors{
+			"field": validation.NewError("validation_field_not_geo_point", "field is not a geo_point field"),
+		}
+	}
+
+	return gf, nil
+}
+
+func (f *GeoPoint) Type() schema.Type {
+	return schema.TypeGeoPoint
+}
+
+// Add adds a point or a list of points. Invalid values are ignored
+func (f *GeoPoint) Add(id uint32, value interface{}) {
+	points, err := geo.ParsePoints(value)
+	if err != nil {
+		return
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.points[id] = append(f.points[id], points...)
+	for _, p := range points {
+		f.addCells(id, p)
+	}
+}
+
+// addCells must be called under the lock
+func (f *GeoPoint) addCells(id uint32, p geo.Point) {
+	hash := geo.EncodeGeohash(p, geo.MaxGeohashPrecision)
+	for i := 1; i <= len(hash); i++ {
+		f.values.Add(id, hash[:i])
+	}
+}
+
+// TermQuery get documents having points inside the geohash cell
+func (f *GeoPoint) TermQuery(ctx context.Context, value interface{}) *QueryResult {
+	v, ok := value.(string)
+	if !ok {
+		return newResult(ctx, roaring.New())
+	}
+
+	return newResult(ctx, f.values.DocsByValue(v))
+}
+
+func (f *GeoPoint) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
+	return f.TermQuery(ctx, value)
+}
+
+func (f *GeoPoint) RangeQuery(ctx context.Context, from interface{}, to interface{}, incFrom, incTo bool) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *GeoPoint) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *GeoPoint) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.values.AllDocs())
+}
+
+// DistanceQuery get documents having points within the distance (in meters) from the origin
+func (f *GeoPoint) DistanceQuery(ctx context.Context, origin geo.Point, distance float64) *QueryResult {
+	return f.shapeQuery(ctx, geo.CircleRect(origin, distance), func(p geo.Point) bool {
+		return geo.Distance(origin, p) <= distance
+	})
+}
+
+// BoundingBoxQuery get documents having points inside the rect
+func (f *GeoPoint) BoundingBoxQuery(ctx context.Context, rect geo.Rect) *QueryResult {
+	return f.shapeQuery(ctx, rect, rect.Contains)
+}
+
+// PolygonQuery get documents having points inside the polygon
+func (f *GeoPoint) PolygonQuery(ctx context.Context, polygon geo.Polygon) *QueryResult {
+	return f.shapeQuery(ctx, polygon.Bounds(), polygon.Contains)
+}
+
+// shapeQuery takes candidates from cells covering the bounds and returns those having any point inside the shape
+func (f *GeoPoint) shapeQuery(ctx context.Context, bounds geo.Rect, contains func(p geo.Point) bool) *QueryResult {
+	candidates := roaring.New()
+	for _, r := range bounds.Split() {
+		for _, cell := range geo.CoverGeohashes(r, geoMaxCoverCells) {
+			candidates.Or(f.values.DocsByValue(cell))
+		}
+	}
+
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	result := roaring.New()
+	candidates.Iterate(func(id uint32) bool {
+		for _, p := range f.points[id] {
+			if contains(p) {
+				result.Add(id)
+				break
+			}
+		}
+		return true
+	})
+
+	return newResult(ctx, result)
+}
+
+// Distance returns distance in meters from the origin to the closest document point. False is returned if document has no points
+func (f *GeoPoint) Distance(id uint32, origin geo.Point) (float64, bool) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	points := f.points[id]
+	if len(points) == 0 {
+		return 0, false
+	}
+
+	result := math.Inf(1)
+	for _, p := range points {
+		result = math.Min(result, geo.Distance(origin, p))
+	}
+
+	return result, true
+}
+
+// Points returns document points
+func (f *GeoPoint) Points(id uint32) []geo.Point {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	result := make([]geo.Point, len(f.points[id]))
+	copy(result, f.points[id])
+
+	return result
+}
+
+func (f *GeoPoint) DeleteDoc(id uint32) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	delete(f.points, id)
+	f.values.DeleteDoc(id)
+}
+
+func (f *GeoPoint) Data(id uint32) []interface{} {
+	points := f.Points(id)
+	result := make([]interface{}, len(points))
+	for i, p := range points {
+		result[i] = p
+	}
+
+	return result
+}
+
+func (f *GeoPoint) MinValue() (interface{}, *roaring.Bitmap) {
+	return nil, roaring.New()
+}
+
+func (f *GeoPoint) MaxValue() (interface{}, *roaring.Bitmap) {
+	return nil, roaring.New()
+}
+
+func (f *GeoPoint) TermAgg(ctx context.Context, docs *roaring.Bitmap, size int) TermAggResult {
+	return TermAggResult{
+		Buckets: []TermBucket{},
+	}
+}
+
+// MarshalBinary stores only points, geohash cells are rebuilt on load
+func (f *GeoPoint) MarshalBinary() ([]byte, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(f.points)
+
+	return buf.Bytes(), err
+}
+
+func (f *GeoPoint) UnmarshalBinary(data []byte) error {
+	raw := make(map[uint32][]geo.Point)
+	buf := bytes.NewBuffer(data)
+	err := gob.NewDecoder(buf).Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.points = raw
+	f.values = newDocValues[string]()
+	for id, points := range f.points {
+		for _, p := range points {
+			f.addCells(id, p)
+		}
+	}
+
+	return nil
+}
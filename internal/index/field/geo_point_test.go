@@ -0,0 +1,136 @@
+package field
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGeoPoint() *GeoPoint {
+	field := newGeoPoint()
+	// Moscow
+	field.Add(1, map[string]interface{}{"lat": json.Number("55.7558"), "lon": json.Number("37.6173")})
+	// Saint Petersburg and Kazan
+	field.Add(2, []interface{}{"59.9343,30.3351", "55.7963,49.1088"})
+	// London
+	field.Add(3, "gcpvj0duq")
+	// Fiji, near the antimeridian
+	field.Add(4, "-17.7134,178.065")
+	field.Add(5, "invalid")
+
+	return field
+}
+
+func Test_GetGeoPoint(t *testing.T) {
+	points := newTestGeoPoint()
+	fields := map[string]Field{"point": points, "keyword": newKeyword(nil)}
+
+	f, err := GetGeoPoint(fields, "point")
+	require.NoError(t, err)
+	require.Same(t, points, f)
+
+	f, err = GetGeoPoint(fields, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, f)
+
+	_, err = GetGeoPoint(fields, "keyword")
+	require.Error(t, err)
+}
+
+func Test_GeoPoint_Add(t *testing.T) {
+	field := newTestGeoPoint()
+
+	require.Equal(t, []interface{}{geo.Point{Lat: 55.7558, Lon: 37.6173}}, field.Data(1))
+	require.Len(t, field.Data(2), 2)
+	require.Len(t, field.Data(3), 1)
+	require.Empty(t, field.Data(5))
+	require.Equal(t, []uint32{1, 2, 3, 4}, field.ExistsQuery(context.Background()).Docs().ToArray())
+}
+
+func Test_GeoPoint_TermQuery(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+
+	require.Equal(t, []uint32{1, 2}, field.TermQuery(ctx, "u").Docs().ToArray())
+	require.Equal(t, []uint32{3}, field.TermQuery(ctx, "gcpvj0").Docs().ToArray())
+	require.True(t, field.TermQuery(ctx, "zzz").Docs().IsEmpty())
+	require.True(t, field.TermQuery(ctx, 1).Docs().IsEmpty())
+}
+
+func Test_GeoPoint_DistanceQuery(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+	moscow := geo.Point{Lat: 55.7558, Lon: 37.6173}
+
+	require.Equal(t, []uint32{1}, field.DistanceQuery(ctx, moscow, 10000).Docs().ToArray())
+	require.Equal(t, []uint32{1, 2}, field.DistanceQuery(ctx, moscow, 700000).Docs().ToArray())
+	require.Equal(t, []uint32{1, 2, 3}, field.DistanceQuery(ctx, moscow, 3000000).Docs().ToArray())
+	require.Equal(t, []uint32{4}, field.DistanceQuery(ctx, geo.Point{Lat: -17.7, Lon: -179.9}, 300000).Docs().ToArray())
+}
+
+func Test_GeoPoint_BoundingBoxQuery(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+
+	r := geo.NewRect(geo.Point{Lat: 60, Lon: 20}, geo.Point{Lat: 50, Lon: 40})
+	require.Equal(t, []uint32{1, 2}, field.BoundingBoxQuery(ctx, r).Docs().ToArray())
+
+	r = geo.NewRect(geo.Point{Lat: 56, Lon: 40}, geo.Point{Lat: 50, Lon: 50})
+	require.Equal(t, []uint32{2}, field.BoundingBoxQuery(ctx, r).Docs().ToArray())
+
+	r = geo.NewRect(geo.Point{Lat: 0, Lon: 170}, geo.Point{Lat: -30, Lon: -170})
+	require.Equal(t, []uint32{4}, field.BoundingBoxQuery(ctx, r).Docs().ToArray())
+}
+
+func Test_GeoPoint_PolygonQuery(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+
+	// triangle containing Moscow and Saint Petersburg, but not Kazan
+	polygon := geo.Polygon{{Lat: 54, Lon: 36}, {Lat: 61, Lon: 29}, {Lat: 57, Lon: 45}}
+	require.Equal(t, []uint32{1, 2}, field.PolygonQuery(ctx, polygon).Docs().ToArray())
+
+	polygon = geo.Polygon{{Lat: 50, Lon: -1}, {Lat: 52, Lon: -1}, {Lat: 52, Lon: 1}, {Lat: 50, Lon: 1}}
+	require.Equal(t, []uint32{3}, field.PolygonQuery(ctx, polygon).Docs().ToArray())
+}
+
+func Test_GeoPoint_Distance(t *testing.T) {
+	field := newTestGeoPoint()
+	kazan := geo.Point{Lat: 55.7963, Lon: 49.1088}
+
+	d, ok := field.Distance(2, kazan)
+	require.True(t, ok)
+	require.Equal(t, 0.0, d)
+
+	d, ok = field.Distance(1, kazan)
+	require.True(t, ok)
+	require.InDelta(t, 720000, d, 10000)
+
+	_, ok = field.Distance(5, kazan)
+	require.False(t, ok)
+}
+
+func Test_GeoPoint_DeleteDoc(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+	field.DeleteDoc(2)
+
+	require.Empty(t, field.Data(2))
+	require.Equal(t, []uint32{1}, field.TermQuery(ctx, "u").Docs().ToArray())
+}
+
+func Test_GeoPoint_Marshal(t *testing.T) {
+	ctx := context.Background()
+	field := newTestGeoPoint()
+
+	data, err := field.MarshalBinary()
+	require.NoError(t, err)
+
+	field2 := newGeoPoint()
+	require.NoError(t, field2.UnmarshalBinary(data))
+	require.Equal(t, field.Data(2), field2.Data(2))
+	require.Equal(t, []uint32{1, 2}, field2.TermQuery(ctx, "u").Docs().ToArray())
+}
@@ -0,0 +1,348 @@
+package field
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// hnswM max number of node neighbors on upper layers. Nodes of the bottom layer have up to 2*hnswM neighbors
+	hnswM = 16
+	// hnswEfConstruction number of candidates checked when node neighbors are chosen
+	hnswEfConstruction = 100
+	hnswSeed           = 1
+)
+
+// hnsw is a hierarchical navigable small world graph (https://arxiv.org/abs/1603.09320).
+// Every node is present on the layers from 0 to its level. Search starts from the entry point on the top layer
+// and greedily descends to the bottom layer containing all the nodes.
+// Node vectors are provided by the vector func, nodes are compared by the score func (greater score means closer nodes)
+type hnsw struct {
+	vector func(id uint32) []float32
+	score  func(a []float32, b []float32) float64
+	rnd    *rand.Rand
+
+	nodes    map[uint32]*hnswNode
+	entry    uint32
+	maxLevel int
+}
+
+type hnswNode struct {
+	Level int
+	// Neighbors are node neighbors by layers
+	Neighbors [][]uint32
+}
+
+// hnswData is a serializable graph representation
+type hnswData struct {
+	Nodes    map[uint32]*hnswNode
+	Entry    uint32
+	MaxLevel int
+}
+
+type hnswItem struct {
+	id    uint32
+	score float64
+}
+
+func newHNSW(vector func(id uint32) []float32, score func(a []float32, b []float32) float64) *hnsw {
+	return &hnsw{
+		vector:   vector,
+		score:    score,
+		rnd:      rand.New(rand.NewSource(hnswSeed)),
+		nodes:    make(map[uint32]*hnswNode),
+		maxLevel: -1,
+	}
+}
+
+func (g *hnsw) data() hnswData {
+	return hnswData{Nodes: g.nodes, Entry: g.entry, MaxLevel: g.maxLevel}
+}
+
+func (g *hnsw) load(data hnswData) {
+	g.nodes = data.Nodes
+	if g.nodes == nil {
+		g.nodes = make(map[uint32]*hnswNode)
+	}
+	g.entry = data.Entry
+	g.maxLevel = data.MaxLevel
+	if len(g.nodes) == 0 {
+		g.maxLevel = -1
+	}
+}
+
+func (g *hnsw) Len() int {
+	return len(g.nodes)
+}
+
+// maxNeighbors max number of node neighbors on the layer
+func (g *hnsw) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * hnswM
+	}
+
+	return hnswM
+}
+
+// randomLevel returns exponentially distributed node level
+func (g *hnsw) randomLevel() int {
+	return int(math.Floor(-math.Log(1-g.rnd.Float64()) / math.Log(hnswM)))
+}
+
+// Add adds a node. Node vector must be available by the vector func
+func (g *hnsw) Add(id uint32) {
+	if _, ok := g.nodes[id]; ok {
+		g.Delete(id)
+	}
+
+	level := g.randomLevel()
+	node := &hnswNode{Level: level, Neighbors: make([][]uint32, level+1)}
+	if len(g.nodes) == 0 {
+		g.nodes[id] = node
+		g.entry = id
+		g.maxLevel = level
+		return
+	}
+
+	q := g.vector(id)
+	entry := []hnswItem{{id: g.entry, score: g.score(q, g.vector(g.entry))}}
+	for l := g.maxLevel; l > level; l-- {
+		entry = g.searchLayer(q, entry, 1, l, nil)
+	}
+
+	g.nodes[id] = node
+	for l := minInt(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(q, entry, hnswEfConstruction, l, nil)
+		node.Neighbors[l] = g.selectNeighbors(q, candidates, g.maxNeighbors(l))
+
+		for _, n := range node.Neighbors[l] {
+			neighbor := g.nodes[n]
+			neighbor.Neighbors[l] = append(neighbor.Neighbors[l], id)
+			if len(neighbor.Neighbors[l]) > g.maxNeighbors(l) {
+				g.shrink(n, l, neighbor.Neighbors[l])
+			}
+		}
+		entry = candidates
+	}
+
+	if level > g.maxLevel {
+		g.maxLevel = level
+		g.entry = id
+	}
+}
+
+// Delete removes a node. Nodes linked to it are reconnected to its neighbors
+func (g *hnsw) Delete(id uint32) {
+	node, ok := g.nodes[id]
+	if !ok {
+		return
+	}
+	delete(g.nodes, id)
+
+	for n, other := range g.nodes {
+		for l := 0; l < len(other.Neighbors); l++ {
+			idx := -1
+			for i, nn := range other.Neighbors[l] {
+				if nn == id {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				continue
+			}
+
+			candidates := make([]uint32, 0, len(other.Neighbors[l])+hnswM)
+			candidates = append(candidates, other.Neighbors[l][:idx]...)
+			candidates = append(candidates, other.Neighbors[l][idx+1:]...)
+			if l <= node.Level {
+				for _, nn := range node.Neighbors[l] {
+					if nn != n && !containsID(candidates, nn) {
+						candidates = append(candidates, nn)
+					}
+				}
+			}
+			g.shrink(n, l, candidates)
+		}
+	}
+
+	if g.entry != id {
+		return
+	}
+	g.maxLevel = -1
+	for n, other := range g.nodes {
+		if other.Level > g.maxLevel || (other.Level == g.maxLevel && n < g.entry) {
+			g.maxLevel = other.Level
+			g.entry = n
+		}
+	}
+}
+
+// shrink replaces node neighbors on the layer with the best of the candidates
+func (g *hnsw) shrink(id uint32, level int, candidates []uint32) {
+	q := g.vector(id)
+	items := make([]hnswItem, len(candidates))
+	for i, c := range candidates {
+		items[i] = hnswItem{id: c, score: g.score(q, g.vector(c))}
+	}
+	sortHNSWItems(items)
+
+	g.nodes[id].Neighbors[level] = g.selectNeighbors(q, items, g.maxNeighbors(level))
+}
+
+// selectNeighbors chooses up to m neighbors from candidates sorted by score.
+// Candidates closer to already selected neighbors than to the node are skipped to keep the graph navigable,
+// they are used only if there are not enough other candidates
+func (g *hnsw) selectNeighbors(q []float32, candidates []hnswItem, m int) []uint32 {
+	result := make([]uint32, 0, m)
+	selected := make([][]float32, 0, m)
+	var skipped []uint32
+	for _, c := range candidates {
+		if len(result) >= m {
+			break
+		}
+
+		v := g.vector(c.id)
+		ok := true
+		for _, s := range selected {
+			if g.score(v, s) > c.score {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			skipped = append(skipped, c.id)
+			continue
+		}
+
+		result = append(result, c.id)
+		selected = append(selected, v)
+	}
+
+	for i := 0; i < len(skipped) && len(result) < m; i++ {
+		result = append(result, skipped[i])
+	}
+
+	return result
+}
+
+// Search returns k nodes closest to q. At least ef candidates are checked on the bottom layer.
+// Only nodes accepted by the accept func are returned, all nodes are accepted if it is nil
+func (g *hnsw) Search(q []float32, k int, ef int, accept func(id uint32) bool) []hnswItem {
+	if len(g.nodes) == 0 || k <= 0 {
+		return nil
+	}
+
+	entry := []hnswItem{{id: g.entry, score: g.score(q, g.vector(g.entry))}}
+	for l := g.maxLevel; l > 0; l-- {
+		entry = g.searchLayer(q, entry, 1, l, nil)
+	}
+
+	result := g.searchLayer(q, entry, maxInt(ef, k), 0, accept)
+	if len(result) > k {
+		result = result[:k]
+	}
+
+	return result
+}
+
+// searchLayer returns up to ef nodes closest to q on the layer sorted by score.
+// Not accepted nodes are traversed, but not returned
+func (g *hnsw) searchLayer(q []float32, entry []hnswItem, ef int, level int, accept func(id uint32) bool) []hnswItem {
+	visited := make(map[uint32]struct{}, ef*hnswM)
+	candidates := &hnswQueue{best: true}
+	results := &hnswQueue{}
+
+	for _, e := range entry {
+		visited[e.id] = struct{}{}
+		heap.Push(candidates, e)
+		if accept == nil || accept(e.id) {
+			heap.Push(results, e)
+		}
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswItem)
+		if results.Len() >= ef && c.score < results.top().score {
+			break
+		}
+
+		node := g.nodes[c.id]
+		if level >= len(node.Neighbors) {
+			continue
+		}
+		for _, n := range node.Neighbors[level] {
+			if _, ok := visited[n]; ok {
+				continue
+			}
+			visited[n] = struct{}{}
+
+			item := hnswItem{id: n, score: g.score(q, g.vector(n))}
+			if results.Len() >= ef && item.score <= results.top().score {
+				continue
+			}
+			heap.Push(candidates, item)
+			if accept == nil || accept(n) {
+				heap.Push(results, item)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	result := results.items
+	sortHNSWItems(result)
+
+	return result
+}
+
+// sortHNSWItems sorts items by score desc, then by id
+func sortHNSWItems(items []hnswItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].score != items[j].score {
+			return items[i].score > items[j].score
+		}
+		return items[i].id < items[j].id
+	})
+}
+
+func containsID(ids []uint32, id uint32) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hnswQueue is a heap of items. The best scored item is on top if best is true, the worst one otherwise
+type hnswQueue struct {
+	items []hnswItem
+	best  bool
+}
+
+func (h *hnswQueue) top() hnswItem { return h.items[0] }
+
+func (h *hnswQueue) Len() int { return len(h.items) }
+func (h *hnswQueue) Less(i, j int) bool {
+	if h.best {
+		return h.items[i].score > h.items[j].score
+	}
+	return h.items[i].score < h.items[j].score
+}
+func (h *hnswQueue) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *hnswQueue) Push(x interface{}) { h.items = append(h.items, x.(hnswItem)) }
+func (h *hnswQueue) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	x := old[n-1]
+	h.items = old[:n-1]
+	return x
+}
@@ -10,39 +10,57 @@ import (
 var ErrDocNotFound = fmt.Errorf("document not found")
 
 type Index struct {
-	name   string
-	schema schema.Schema
+	name         string
+	schema       schema.Schema
+	analyzerOpts schema.AnalyzerOpts
 
 	fields map[string]Field
 }
 
-func NewIndex(name string, s schema.Schema) (*Index, error) {
+func NewIndex(name string, s schema.Schema, opts ...schema.AnalyzerOpts) (*Index, error) {
 	result := &Index{
 		name:   name,
 		schema: s,
 		fields: make(map[string]Field),
 	}
+	if len(opts) > 0 {
+		result.analyzerOpts = opts[0]
+	}
 
 	// add "allField" which contains all documents
-	fieldsCopy := make(map[string]schema.Field)
-	for name, field := range s.Fields {
-		fieldsCopy[name] = field
-	}
+	fieldsCopy := flatFields(s)
 	fieldsCopy[AllField] = schema.NewField(schema.TypeAll, false, "")
 
 	for name, f := range fieldsCopy {
 		fdata := FieldOpts{}
 
-		if f.Analyzer != "" {
-			a, err := s.Analyzers[f.Analyzer].Build()
+		var err error
+		fdata.Analyzer, fdata.SearchAnalyzer, err = result.buildAnalyzers(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.Normalizer != "" {
+			fdata.Normalizer, err = s.Analyzers[f.Normalizer].Build(result.analyzerOpts)
 			if err != nil {
-				return nil, errs.Errorf("analyzer build err: %w", err)
+				return nil, errs.Errorf("normalizer build err: %w", err)
 			}
-			fdata.Analyzer = a
 		}
 
 		if f.Type == schema.TypeText {
 			fdata.Scoring = NewScoring()
+			fdata.Similarity, err = NewSimilarity(f.Similarity)
+			if err != nil {
+				return nil, errs.Errorf("similarity build err: %w", err)
+			}
+		}
+
+		if f.Type == schema.TypeDenseVector {
+			fdata.Dims = f.Dims
+			fdata.VectorSimilarity, err = NewVectorSimilarity(f.Similarity)
+			if err != nil {
+				return nil, errs.Errorf("similarity build err: %w", err)
+			}
 		}
 
 		field, err := New(f.Type, fdata)
@@ -55,12 +73,90 @@ func NewIndex(name string, s schema.Schema) (*Index, error) {
 	return result, nil
 }
 
-// Add insert or replace document
+// buildAnalyzers builds field analyzer and search analyzer
+func (s *Index) buildAnalyzers(f schema.Field) (analyzer schema.AnalyzerFunc, searchAnalyzer schema.AnalyzerFunc, err error) {
+	if f.Analyzer != "" {
+		analyzer, err = s.schema.Analyzers[f.Analyzer].Build(s.analyzerOpts)
+		if err != nil {
+			return nil, nil, errs.Errorf("analyzer build err: %w", err)
+		}
+	} else if f.Type == schema.TypeText {
+		analyzer, err = schema.DefaultTextAnalyzer().Build(s.analyzerOpts)
+		if err != nil {
+			return nil, nil, errs.Errorf("analyzer build err: %w", err)
+		}
+	}
+
+	if f.SearchAnalyzer != "" {
+		searchAnalyzer, err = s.schema.Analyzers[f.SearchAnalyzer].Build(s.analyzerOpts)
+		if err != nil {
+			return nil, nil, errs.Errorf("search analyzer build err: %w", err)
+		}
+	}
+
+	return analyzer, searchAnalyzer, nil
+}
+
+// AnalyzerOpts returns options used to build index analyzers
+func (s *Index) AnalyzerOpts() schema.AnalyzerOpts {
+	return s.analyzerOpts
+}
+
+// ReloadAnalyzers rebuilds field analyzers, e.g. to apply changed synonym files. Indexed values are not changed
+func (s *Index) ReloadAnalyzers() error {
+	type analyzers struct {
+		field          analyzedField
+		analyzer       schema.AnalyzerFunc
+		searchAnalyzer schema.AnalyzerFunc
+	}
+
+	// build all the analyzers first not to leave the index partially updated
+	var result []analyzers
+	for name, f := range flatFields(s.schema) {
+		field, ok := s.fields[name].(analyzedField)
+		if !ok {
+			continue
+		}
+
+		analyzer, searchAnalyzer, err := s.buildAnalyzers(f)
+		if err != nil {
+			return errs.Errorf("field %q: %w", name, err)
+		}
+		result = append(result, analyzers{field: field, analyzer: analyzer, searchAnalyzer: searchAnalyzer})
+	}
+
+	for _, item := range result {
+		item.field.setAnalyzers(item.analyzer, item.searchAnalyzer)
+	}
+
+	return nil
+}
+
+// flatFields returns schema fields with their sub-fields
+func flatFields(s schema.Schema) map[string]schema.Field {
+	result := make(map[string]schema.Field)
+	for name, field := range s.Fields {
+		result[name] = field
+		for subName, subField := range field.Fields {
+			result[schema.SubFieldName(name, subName)] = subField
+		}
+	}
+
+	return result
+}
+
+// Add insert or replace document. Values are also added to sub-fields
 func (s *Index) Add(id uint32, source map[string]interface{}) {
 	s.fields[AllField].Add(id, true)
 	for key, value := range source {
-		if f, ok := s.fields[key]; ok {
-			f.Add(id, value)
+		sf, ok := s.schema.Fields[key]
+		if !ok {
+			continue
+		}
+
+		s.fields[key].Add(id, value)
+		for subName := range sf.Fields {
+			s.fields[schema.SubFieldName(key, subName)].Add(id, value)
 		}
 	}
 }
@@ -70,12 +166,10 @@ func (s *Index) Get(id uint32) (map[string]interface{}, error) {
 		return nil, ErrDocNotFound
 	}
 
+	// sub-fields contain the same values as their parents, so they are skipped
 	result := make(map[string]interface{})
-	for k, f := range s.fields {
-		if k == AllField {
-			continue
-		}
-		result[k] = f.Data(id)
+	for k := range s.schema.Fields {
+		result[k] = s.fields[k].Data(id)
 	}
 	return result, nil
 }
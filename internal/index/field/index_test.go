@@ -2,6 +2,8 @@ package field
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/cyradin/search/internal/index/schema"
@@ -61,4 +63,115 @@ func Test_index(t *testing.T) {
 		fields := index.Fields()
 		require.EqualValues(t, index.fields, fields)
 	})
+
+	t.Run("must analyze query values with search analyzer", func(t *testing.T) {
+		ctx := context.Background()
+
+		s := schema.New(map[string]schema.Field{
+			"text": {Type: schema.TypeText, Analyzer: "autocomplete", SearchAnalyzer: "whitespace"},
+		}, map[string]schema.FieldAnalyzer{
+			"autocomplete": {Analyzers: []schema.Analyzer{
+				{Type: schema.TokenizerWhitespace},
+				{Type: schema.TokenizerEdgeNgram, Settings: map[string]interface{}{"minGram": 1, "maxGram": 5}},
+			}},
+			"whitespace": {Analyzers: []schema.Analyzer{{Type: schema.TokenizerWhitespace}}},
+		})
+		index, err := NewIndex("name", s)
+		require.NoError(t, err)
+
+		index.Add(1, map[string]interface{}{"text": "hello world"})
+
+		require.ElementsMatch(t, []uint32{1}, index.fields["text"].MatchQuery(ctx, "hel").Docs().ToArray())
+		require.Empty(t, index.fields["text"].MatchQuery(ctx, "hxl").Docs().ToArray())
+	})
+
+	t.Run("must use default analyzer for text fields without analyzer", func(t *testing.T) {
+		ctx := context.Background()
+
+		s := schema.New(map[string]schema.Field{
+			"text": {Type: schema.TypeText},
+		}, nil)
+		index, err := NewIndex("name", s)
+		require.NoError(t, err)
+
+		index.Add(1, map[string]interface{}{"text": "Hello,World"})
+
+		require.ElementsMatch(t, []uint32{1}, index.fields["text"].MatchQuery(ctx, "world").Docs().ToArray())
+	})
+}
+
+func Test_Index_SubFields(t *testing.T) {
+	ctx := context.Background()
+
+	s := schema.New(map[string]schema.Field{
+		"title": {Type: schema.TypeText, Fields: map[string]schema.Field{
+			"raw":   {Type: schema.TypeKeyword},
+			"ngram": {Type: schema.TypeText, Analyzer: "ngram"},
+		}},
+	}, map[string]schema.FieldAnalyzer{
+		"ngram": {Analyzers: []schema.Analyzer{
+			{Type: schema.TokenizerNgram, Settings: map[string]interface{}{"minGram": 3, "maxGram": 3}},
+		}},
+	})
+	index, err := NewIndex("name", s)
+	require.NoError(t, err)
+	require.Contains(t, index.fields, "title.raw")
+	require.Contains(t, index.fields, "title.ngram")
+
+	index.Add(1, map[string]interface{}{"title": "Hello World"})
+	index.Add(2, map[string]interface{}{"title": "hello"})
+
+	t.Run("must add value to sub-fields", func(t *testing.T) {
+		require.ElementsMatch(t, []uint32{1, 2}, index.fields["title"].MatchQuery(ctx, "hello").Docs().ToArray())
+		require.ElementsMatch(t, []uint32{1}, index.fields["title.raw"].TermQuery(ctx, "Hello World").Docs().ToArray())
+		require.ElementsMatch(t, []uint32{1}, index.fields["title.ngram"].MatchQuery(ctx, "orl").Docs().ToArray())
+	})
+
+	t.Run("must not return sub-fields values", func(t *testing.T) {
+		doc, err := index.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"title": []interface{}{"Hello World"}}, doc)
+	})
+
+	t.Run("must ignore sub-field keys in source", func(t *testing.T) {
+		index.Add(3, map[string]interface{}{"title.raw": "foo"})
+		require.Empty(t, index.fields["title.raw"].TermQuery(ctx, "foo").Docs().ToArray())
+	})
+
+	t.Run("must delete document from sub-fields", func(t *testing.T) {
+		index.Delete(1)
+		require.Empty(t, index.fields["title.raw"].TermQuery(ctx, "Hello World").Docs().ToArray())
+	})
+}
+
+func Test_Index_ReloadAnalyzers(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "synonyms.txt")
+	require.NoError(t, os.WriteFile(file, []byte("quick, fast\n"), 0644))
+
+	s := schema.New(map[string]schema.Field{
+		"text": {Type: schema.TypeText, Analyzer: "whitespace", SearchAnalyzer: "synonyms"},
+	}, map[string]schema.FieldAnalyzer{
+		"whitespace": {Analyzers: []schema.Analyzer{{Type: schema.TokenizerWhitespace}}},
+		"synonyms": {Analyzers: []schema.Analyzer{
+			{Type: schema.TokenizerWhitespace},
+			{Type: schema.FilterSynonym, Settings: map[string]interface{}{"synonymsPath": "synonyms.txt"}},
+		}},
+	})
+	index, err := NewIndex("name", s, schema.AnalyzerOpts{DataDir: dir})
+	require.NoError(t, err)
+
+	index.Add(1, map[string]interface{}{"text": "fast car"})
+	index.Add(2, map[string]interface{}{"text": "rapid car"})
+
+	require.ElementsMatch(t, []uint32{1}, index.fields["text"].MatchQuery(ctx, "quick").Docs().ToArray())
+
+	require.NoError(t, os.WriteFile(file, []byte("quick, rapid\n"), 0644))
+	require.NoError(t, index.ReloadAnalyzers())
+	require.ElementsMatch(t, []uint32{2}, index.fields["text"].MatchQuery(ctx, "quick").Docs().ToArray())
+
+	require.NoError(t, os.Remove(file))
+	require.Error(t, index.ReloadAnalyzers())
+	require.ElementsMatch(t, []uint32{2}, index.fields["text"].MatchQuery(ctx, "quick").Docs().ToArray())
 }
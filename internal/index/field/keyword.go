@@ -14,12 +14,21 @@ var _ Field = (*Keyword)(nil)
 
 type Keyword struct {
 	values *docValues[string]
+	// raw contains source values if normalizer is set, values contain normalized ones
+	raw        *docValues[string]
+	normalizer func([]string) []string
 }
 
-func newKeyword() *Keyword {
-	return &Keyword{
-		values: newDocValues[string](),
+func newKeyword(normalizer func([]string) []string) *Keyword {
+	result := &Keyword{
+		values:     newDocValues[string](),
+		normalizer: normalizer,
 	}
+	if normalizer != nil {
+		result.raw = newDocValues[string]()
+	}
+
+	return result
 }
 
 func (f *Keyword) Type() schema.Type {
@@ -32,7 +41,12 @@ func (f *Keyword) Add(id uint32, value interface{}) {
 		return
 	}
 
-	f.values.Add(id, v)
+	if f.raw != nil {
+		f.raw.Add(id, v)
+	}
+	if v, ok := f.normalize(v); ok {
+		f.values.Add(id, v)
+	}
 }
 
 func (f *Keyword) TermQuery(ctx context.Context, value interface{}) *QueryResult {
@@ -40,6 +54,10 @@ func (f *Keyword) TermQuery(ctx context.Context, value interface{}) *QueryResult
 	if err != nil {
 		return newResult(ctx, roaring.New())
 	}
+	v, ok := f.normalize(v)
+	if !ok {
+		return newResult(ctx, roaring.New())
+	}
 
 	return newResult(ctx, f.values.DocsByValue(v))
 }
@@ -52,12 +70,42 @@ func (f *Keyword) RangeQuery(ctx context.Context, from interface{}, to interface
 	return newResult(ctx, roaring.New())
 }
 
+func (f *Keyword) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, expandPattern(f.values, pattern, maxExpansions))
+}
+
+func (f *Keyword) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.values.AllDocs())
+}
+
+// normalize applies normalizer to the value. False is returned if nothing is left after normalization
+func (f *Keyword) normalize(v string) (string, bool) {
+	if f.normalizer == nil {
+		return v, true
+	}
+
+	result := f.normalizer([]string{v})
+	if len(result) == 0 {
+		return "", false
+	}
+
+	return result[0], true
+}
+
 func (f *Keyword) DeleteDoc(id uint32) {
 	f.values.DeleteDoc(id)
+	if f.raw != nil {
+		f.raw.DeleteDoc(id)
+	}
 }
 
 func (f *Keyword) Data(id uint32) []interface{} {
-	values := f.values.ValuesByDoc(id)
+	source := f.values
+	if f.raw != nil {
+		source = f.raw
+	}
+
+	values := source.ValuesByDoc(id)
 	result := make([]interface{}, len(values))
 	for i, v := range values {
 		result[i] = v
@@ -80,11 +128,12 @@ func (f *Keyword) TermAgg(ctx context.Context, docs *roaring.Bitmap, size int) T
 
 type keywordData struct {
 	Values *docValues[string]
+	Raw    *docValues[string]
 }
 
 func (f *Keyword) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
-	err := gob.NewEncoder(&buf).Encode(keywordData{Values: f.values})
+	err := gob.NewEncoder(&buf).Encode(keywordData{Values: f.values, Raw: f.raw})
 
 	return buf.Bytes(), err
 }
@@ -97,6 +146,7 @@ func (f *Keyword) UnmarshalBinary(data []byte) error {
 		return err
 	}
 	f.values = raw.Values
+	f.raw = raw.Raw
 
 	return nil
 }
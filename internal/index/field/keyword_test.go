@@ -5,19 +5,20 @@ import (
 	"testing"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/schema"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_Keyword_Add(t *testing.T) {
 	t.Run("bool", func(t *testing.T) {
-		field := newKeyword()
+		field := newKeyword(nil)
 		field.Add(1, true)
 
 		require.EqualValues(t, 1, field.values.DocsByValue("true").GetCardinality())
 		require.True(t, field.values.DocsByValue("true").Contains(1))
 	})
 	t.Run("string", func(t *testing.T) {
-		field := newKeyword()
+		field := newKeyword(nil)
 		field.Add(1, "foo")
 		field.Add(1, "bar")
 		field.Add(2, "foo")
@@ -32,7 +33,7 @@ func Test_Keyword_Add(t *testing.T) {
 }
 
 func Test_Keyword_TermQuery(t *testing.T) {
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 
 	result := field.TermQuery(context.Background(), "foo")
@@ -45,7 +46,7 @@ func Test_Keyword_TermQuery(t *testing.T) {
 }
 
 func Test_Keyword_MatchQuery(t *testing.T) {
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 
 	result := field.MatchQuery(context.Background(), "foo")
@@ -57,8 +58,34 @@ func Test_Keyword_MatchQuery(t *testing.T) {
 	require.EqualValues(t, 0, result.Docs().GetCardinality())
 }
 
+func Test_Keyword_PatternQuery(t *testing.T) {
+	field := newKeyword(nil)
+	field.Add(1, "foo")
+	field.Add(2, "foobar")
+	field.Add(3, "bar")
+
+	p, err := NewPrefixPattern("foo", false)
+	require.NoError(t, err)
+	result := field.PatternQuery(context.Background(), p, 0)
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+
+	p, err = NewWildcardPattern("*bar", false)
+	require.NoError(t, err)
+	result = field.PatternQuery(context.Background(), p, 0)
+	require.ElementsMatch(t, []uint32{2, 3}, result.Docs().ToArray())
+}
+
+func Test_Keyword_ExistsQuery(t *testing.T) {
+	field := newKeyword(nil)
+	field.Add(1, "foo")
+	field.Add(3, "bar")
+
+	result := field.ExistsQuery(context.Background())
+	require.ElementsMatch(t, []uint32{1, 3}, result.Docs().ToArray())
+}
+
 func Test_Keyword_DeleteDoc(t *testing.T) {
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 	field.Add(1, "bar")
 	field.Add(2, "foo")
@@ -76,7 +103,7 @@ func Test_Keyword_DeleteDoc(t *testing.T) {
 }
 
 func Test_Keyword_Data(t *testing.T) {
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 	field.Add(1, "bar")
 	field.Add(2, "foo")
@@ -92,7 +119,7 @@ func Test_Keyword_TermAgg(t *testing.T) {
 	bm := roaring.New()
 	bm.Add(1)
 
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 	result := field.TermAgg(context.Background(), bm, 20)
 	require.Equal(t, []TermBucket{
@@ -101,7 +128,7 @@ func Test_Keyword_TermAgg(t *testing.T) {
 }
 
 func Test_Keyword_Marshal(t *testing.T) {
-	field := newKeyword()
+	field := newKeyword(nil)
 	field.Add(1, "foo")
 	field.Add(1, "bar")
 	field.Add(2, "foo")
@@ -109,7 +136,7 @@ func Test_Keyword_Marshal(t *testing.T) {
 	data, err := field.MarshalBinary()
 	require.NoError(t, err)
 
-	field2 := newKeyword()
+	field2 := newKeyword(nil)
 	err = field2.UnmarshalBinary(data)
 	require.NoError(t, err)
 	require.True(t, field2.values.DocsByValue("foo").Contains(1))
@@ -118,3 +145,52 @@ func Test_Keyword_Marshal(t *testing.T) {
 	require.True(t, field2.values.DocsByValue("foo").Contains(2))
 	require.ElementsMatch(t, []string{"foo"}, field.values.ValuesByDoc(2))
 }
+
+func Test_Keyword_Normalizer(t *testing.T) {
+	ctx := context.Background()
+	normalizer, err := schema.FieldAnalyzer{Analyzers: []schema.Analyzer{
+		{Type: schema.FilterTrim},
+		{Type: schema.FilterLowercase},
+		{Type: schema.FilterASCIIFolding},
+	}}.Build()
+	require.NoError(t, err)
+
+	field := newKeyword(normalizer)
+	field.Add(1, "ACME ")
+	field.Add(2, "acme")
+	field.Add(3, "Açme")
+	field.Add(4, "  ")
+
+	t.Run("must normalize query values", func(t *testing.T) {
+		require.ElementsMatch(t, []uint32{1, 2, 3}, field.TermQuery(ctx, " Acme").Docs().ToArray())
+		require.Empty(t, field.TermQuery(ctx, " ").Docs().ToArray())
+	})
+
+	t.Run("must aggregate normalized values", func(t *testing.T) {
+		result := field.TermAgg(ctx, roaring.BitmapOf(1, 2, 3, 4), 20)
+		require.Equal(t, []TermBucket{
+			{Key: "acme", Docs: roaring.BitmapOf(1, 2, 3)},
+		}, result.Buckets)
+	})
+
+	t.Run("must return source values", func(t *testing.T) {
+		require.Equal(t, []interface{}{"ACME "}, field.Data(1))
+		require.Equal(t, []interface{}{"  "}, field.Data(4))
+	})
+
+	t.Run("must keep source values on marshal", func(t *testing.T) {
+		data, err := field.MarshalBinary()
+		require.NoError(t, err)
+
+		field2 := newKeyword(normalizer)
+		require.NoError(t, field2.UnmarshalBinary(data))
+		require.Equal(t, []interface{}{"Açme"}, field2.Data(3))
+		require.ElementsMatch(t, []uint32{1, 2, 3}, field2.TermQuery(ctx, "ACME").Docs().ToArray())
+	})
+
+	t.Run("must delete source values", func(t *testing.T) {
+		field.DeleteDoc(1)
+		require.Empty(t, field.Data(1))
+		require.ElementsMatch(t, []uint32{2, 3}, field.TermQuery(ctx, "acme").Docs().ToArray())
+	})
+}
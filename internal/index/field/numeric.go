@@ -10,6 +10,13 @@ import (
 )
 
 var _ Field = (*Numeric[int32])(nil)
+var _ NumericValues = (*Numeric[int32])(nil)
+
+// NumericValues is implemented by numeric fields to get document values without boxing them
+type NumericValues interface {
+	// FloatValues get document values converted to float64
+	FloatValues(id uint32) []float64
+}
 
 type NumericConstraint interface {
 	int8 | int16 | int32 | int64 | uint64 | float32 | float64
@@ -97,6 +104,14 @@ func (f *Numeric[T]) RangeQuery(ctx context.Context, from interface{}, to interf
 	return newResult(ctx, rangeQuery(ctx, f.values, vFrom, vTo, incFrom, incTo), opts...)
 }
 
+func (f *Numeric[T]) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *Numeric[T]) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.values.AllDocs())
+}
+
 func (f *Numeric[T]) DeleteDoc(id uint32) {
 	f.values.DeleteDoc(id)
 }
@@ -111,6 +126,16 @@ func (f *Numeric[T]) Data(id uint32) []interface{} {
 	return result
 }
 
+func (f *Numeric[T]) FloatValues(id uint32) []float64 {
+	values := f.values.ValuesByDoc(id)
+	result := make([]float64, len(values))
+	for i, v := range values {
+		result[i] = float64(v)
+	}
+
+	return result
+}
+
 func (f *Numeric[T]) MinValue() (interface{}, *roaring.Bitmap) {
 	return f.values.MinValue()
 }
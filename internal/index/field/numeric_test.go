@@ -228,6 +228,25 @@ func test_Numeric_Data[T NumericConstraint](t *testing.T) {
 	require.ElementsMatch(t, []T{T(1)}, result)
 }
 
+func Test_Numeric_FloatValues(t *testing.T) {
+	t.Run("int8", test_Numeric_FloatValues[int8])
+	t.Run("int16", test_Numeric_FloatValues[int16])
+	t.Run("int32", test_Numeric_FloatValues[int32])
+	t.Run("int64", test_Numeric_FloatValues[int64])
+	t.Run("uint64", test_Numeric_FloatValues[uint64])
+	t.Run("float32", test_Numeric_FloatValues[float32])
+	t.Run("float64", test_Numeric_FloatValues[float64])
+}
+
+func test_Numeric_FloatValues[T NumericConstraint](t *testing.T) {
+	field := newNumeric[T]()
+	field.Add(1, 1)
+	field.Add(1, 2)
+
+	require.ElementsMatch(t, []float64{1, 2}, field.FloatValues(1))
+	require.Empty(t, field.FloatValues(2))
+}
+
 func Test_Numeric_TermAgg(t *testing.T) {
 	t.Run("int8", test_Numeric_TermAgg[int8])
 	t.Run("int16", test_Numeric_TermAgg[int16])
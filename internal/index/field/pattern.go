@@ -0,0 +1,123 @@
+package field
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+)
+
+// Pattern matches terms of the field term dictionary.
+// Every matching term starts with the literal prefix, so only a contiguous range of the sorted term list is scanned.
+type Pattern struct {
+	prefix string
+	re     *regexp.Regexp // nil if every term starting with prefix matches
+}
+
+// NewPrefixPattern matches terms starting with prefix
+func NewPrefixPattern(prefix string, caseInsensitive bool) (Pattern, error) {
+	if !caseInsensitive {
+		return Pattern{prefix: prefix}, nil
+	}
+
+	return newPattern("^"+regexp.QuoteMeta(prefix), true)
+}
+
+// NewWildcardPattern matches terms by wildcard expression.
+// "*" matches any sequence of characters, "?" matches a single character, "\" escapes the next character.
+func NewWildcardPattern(expr string, caseInsensitive bool) (Pattern, error) {
+	var (
+		b       strings.Builder
+		escaped bool
+	)
+	for _, r := range expr {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if escaped {
+		return Pattern{}, errs.Errorf("wildcard %q ends with an escape character", expr)
+	}
+
+	return newPattern("^(?:"+b.String()+")$", caseInsensitive)
+}
+
+// NewRegexpPattern matches terms by regular expression. The expression must match the whole term.
+func NewRegexpPattern(expr string, caseInsensitive bool) (Pattern, error) {
+	flags := syntax.Perl | syntax.DotNL
+	if caseInsensitive {
+		flags |= syntax.FoldCase
+	}
+
+	re, err := syntax.Parse(expr, flags)
+	if err != nil {
+		return Pattern{}, errs.Errorf("pattern compile err: %w", err)
+	}
+
+	// anchors are added to the parsed expression, so the expression cannot escape them ("a)|(b")
+	anchored := &syntax.Regexp{Op: syntax.OpConcat, Sub: []*syntax.Regexp{{Op: syntax.OpBeginText}, re, {Op: syntax.OpEndText}}}
+
+	return newPattern(anchored.String(), caseInsensitive)
+}
+
+func newPattern(expr string, caseInsensitive bool) (Pattern, error) {
+	flags := "(?s)"
+	if caseInsensitive {
+		flags = "(?is)"
+	}
+
+	re, err := regexp.Compile(flags + expr)
+	if err != nil {
+		return Pattern{}, errs.Errorf("pattern compile err: %w", err)
+	}
+
+	prefix, _ := re.LiteralPrefix()
+
+	return Pattern{prefix: prefix, re: re}, nil
+}
+
+// Match checks if the term matches the pattern
+func (p Pattern) Match(term string) bool {
+	if !strings.HasPrefix(term, p.prefix) {
+		return false
+	}
+
+	return p.re == nil || p.re.MatchString(term)
+}
+
+// expandPattern returns documents containing up to maxExpansions terms matching the pattern
+func expandPattern(data *docValues[string], p Pattern, maxExpansions int) *roaring.Bitmap {
+	data.mtx.RLock()
+	defer data.mtx.RUnlock()
+
+	bm := make([]*roaring.Bitmap, 0)
+	for i := sort.SearchStrings(data.List, p.prefix); i < len(data.List); i++ {
+		term := data.List[i]
+		if !strings.HasPrefix(term, p.prefix) {
+			break
+		}
+		if !p.Match(term) {
+			continue
+		}
+
+		bm = append(bm, data.Docs[term])
+		if maxExpansions > 0 && len(bm) >= maxExpansions {
+			break
+		}
+	}
+
+	return roaring.FastOr(bm...)
+}
@@ -0,0 +1,123 @@
+package field
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Pattern_Match(t *testing.T) {
+	t.Run("prefix", func(t *testing.T) {
+		p, err := NewPrefixPattern("ab", false)
+		require.NoError(t, err)
+		require.Equal(t, "ab", p.prefix)
+		require.True(t, p.Match("ab"))
+		require.True(t, p.Match("abc"))
+		require.False(t, p.Match("Abc"))
+		require.False(t, p.Match("ba"))
+	})
+
+	t.Run("case insensitive prefix", func(t *testing.T) {
+		p, err := NewPrefixPattern("ab", true)
+		require.NoError(t, err)
+		require.True(t, p.Match("abc"))
+		require.True(t, p.Match("ABc"))
+		require.False(t, p.Match("bab"))
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		p, err := NewWildcardPattern("ab?d*", false)
+		require.NoError(t, err)
+		require.Equal(t, "ab", p.prefix)
+		require.True(t, p.Match("abcd"))
+		require.True(t, p.Match("abcdef"))
+		require.False(t, p.Match("abd"))
+		require.False(t, p.Match("xabcd"))
+	})
+
+	t.Run("wildcard with escaped characters", func(t *testing.T) {
+		p, err := NewWildcardPattern(`a\*b.`, false)
+		require.NoError(t, err)
+		require.True(t, p.Match("a*b."))
+		require.False(t, p.Match("aab."))
+		require.False(t, p.Match("a*bc"))
+	})
+
+	t.Run("wildcard must not end with escape character", func(t *testing.T) {
+		_, err := NewWildcardPattern(`ab\`, false)
+		require.Error(t, err)
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		p, err := NewRegexpPattern("sku-[0-9]+", false)
+		require.NoError(t, err)
+		require.Equal(t, "sku-", p.prefix)
+		require.True(t, p.Match("sku-123"))
+		require.False(t, p.Match("sku-123a"))
+		require.False(t, p.Match("SKU-123"))
+	})
+
+	t.Run("case insensitive regexp", func(t *testing.T) {
+		p, err := NewRegexpPattern("sku-[0-9]+", true)
+		require.NoError(t, err)
+		require.Equal(t, "", p.prefix)
+		require.True(t, p.Match("SKU-123"))
+	})
+
+	t.Run("regexp alternation must match the whole term", func(t *testing.T) {
+		p, err := NewRegexpPattern("a|b", false)
+		require.NoError(t, err)
+		require.True(t, p.Match("a"))
+		require.True(t, p.Match("b"))
+		require.False(t, p.Match("ab"))
+		require.False(t, p.Match("xb"))
+	})
+
+	t.Run("regexp dot must match new lines", func(t *testing.T) {
+		p, err := NewRegexpPattern("a.b", false)
+		require.NoError(t, err)
+		require.True(t, p.Match("a\nb"))
+	})
+
+	t.Run("invalid regexp", func(t *testing.T) {
+		_, err := NewRegexpPattern("(", false)
+		require.Error(t, err)
+	})
+
+	t.Run("regexp must not escape anchors", func(t *testing.T) {
+		_, err := NewRegexpPattern("a)|(b", false)
+		require.Error(t, err)
+	})
+}
+
+func Test_expandPattern(t *testing.T) {
+	values := newDocValues[string]()
+	values.Add(1, "apple")
+	values.Add(2, "apricot")
+	values.Add(3, "banana")
+	values.Add(4, "Apple")
+
+	t.Run("can expand prefix", func(t *testing.T) {
+		p, err := NewPrefixPattern("ap", false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, expandPattern(values, p, 0).ToArray())
+	})
+
+	t.Run("can expand case insensitive prefix", func(t *testing.T) {
+		p, err := NewPrefixPattern("ap", true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2, 4}, expandPattern(values, p, 0).ToArray())
+	})
+
+	t.Run("must stop after max expansions", func(t *testing.T) {
+		p, err := NewPrefixPattern("ap", false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, expandPattern(values, p, 1).ToArray())
+	})
+
+	t.Run("must return empty result if nothing found", func(t *testing.T) {
+		p, err := NewWildcardPattern("c*", false)
+		require.NoError(t, err)
+		require.True(t, expandPattern(values, p, 0).IsEmpty())
+	})
+}
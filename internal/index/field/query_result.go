@@ -17,14 +17,12 @@ func IsScoringDisabled(ctx context.Context) bool {
 	return false
 }
 
-const bm25K1 = 1.2
-const bm25B = 0.75
-
 type ResultOpt func(r *QueryResult)
 
 type QueryResult struct {
 	tokens          []string
 	scoring         *Scoring
+	similarity      Similarity
 	scoringDisabled bool
 	docs            *roaring.Bitmap
 	boost           float64
@@ -41,11 +39,12 @@ func newResult(ctx context.Context, docs *roaring.Bitmap, opts ...ResultOpt) *Qu
 	return result.WithOpts(ctx, opts...)
 }
 
-func newResultWithScoring(ctx context.Context, docs *roaring.Bitmap, scoring *Scoring, opts ...ResultOpt) *QueryResult {
+func newResultWithScoring(ctx context.Context, docs *roaring.Bitmap, scoring *Scoring, similarity Similarity, opts ...ResultOpt) *QueryResult {
 	result := &QueryResult{
-		docs:    docs,
-		boost:   1.0,
-		scoring: scoring,
+		docs:       docs,
+		boost:      1.0,
+		scoring:    scoring,
+		similarity: similarity,
 	}
 
 	return result.WithOpts(ctx, opts...)
@@ -81,13 +80,66 @@ func (r *QueryResult) Score(id uint32) float64 {
 		score = 1.0
 	} else {
 		for _, token := range r.tokens {
-			score += r.scoring.BM25(id, bm25K1, bm25B, token)
+			score += r.similarity.Score(r.scoring, id, token)
 		}
 	}
 
 	return score * r.boost
 }
 
+// Tokens returns analyzed query tokens the result is scored by
+func (r *QueryResult) Tokens() []string {
+	return r.tokens
+}
+
+// TokenScore returns the document score for a single query token
+func (r *QueryResult) TokenScore(id uint32, token string) float64 {
+	if r.scoringDisabled || r.scoring == nil {
+		return 0
+	}
+
+	if !r.docs.Contains(id) {
+		return 0
+	}
+
+	return r.similarity.Score(r.scoring, id, token) * r.boost
+}
+
+// Explain describes how the document score is calculated
+func (r *QueryResult) Explain(id uint32) Explanation {
+	if r.scoringDisabled {
+		return NewExplanation(0, "scoring disabled")
+	}
+
+	if !r.docs.Contains(id) {
+		return NoMatchExplanation()
+	}
+
+	if len(r.tokens) == 0 {
+		return withBoostExplanation(NewExplanation(1, "constant score"), r.boost)
+	}
+
+	details := make([]Explanation, len(r.tokens))
+	for i, token := range r.tokens {
+		details[i] = r.similarity.Explain(r.scoring, id, token)
+	}
+
+	return withBoostExplanation(SumExplanation("sum of:", details...), r.boost)
+}
+
+// ExplainToken describes how the document score for a single query token is calculated
+func (r *QueryResult) ExplainToken(id uint32, token string) Explanation {
+	if r.scoringDisabled || r.scoring == nil {
+		return NewExplanation(0, "scoring disabled")
+	}
+
+	if !r.docs.Contains(id) {
+		return NoMatchExplanation()
+	}
+
+	return withBoostExplanation(r.similarity.Explain(r.scoring, id, token), r.boost)
+}
+
 func (r *QueryResult) From() interface{} {
 	return r.from
 }
@@ -95,13 +95,14 @@ func (s *Scoring) AvgDocLen() float64 {
 	return s.data.AvgDocLen
 }
 
+// BM25 calculates Okapi BM25 score using raw term frequency
 func (i *Scoring) BM25(docID uint32, k1 float64, b float64, word string) float64 {
-	tf := i.TF(docID, word)
+	tf := float64(i.DocWordCount(docID, word))
 	if tf == 0 {
 		return 0
 	}
 
-	idf := i.IDF(docID, word)
+	idf := i.BM25IDF(word)
 	if idf == 0 {
 		return 0
 	}
@@ -114,6 +115,19 @@ func (i *Scoring) BM25(docID uint32, k1 float64, b float64, word string) float64
 	return idf * (tf * (k1 + 1)) / (tf + k1*(1-b+b*docLen/i.AvgDocLen()))
 }
 
+// BM25IDF calculates BM25 inverse document frequency which is always positive
+func (i *Scoring) BM25IDF(word string) float64 {
+	wordCnt := float64(i.IndexWordCount(word))
+	totalCnt := float64(i.IndexDocCount())
+
+	if wordCnt == 0 || totalCnt == 0 {
+		return 0
+	}
+
+	return math.Log(1 + (totalCnt-wordCnt+0.5)/(wordCnt+0.5))
+}
+
+// TF calculates term frequency normalized by document length
 func (i *Scoring) TF(docID uint32, word string) float64 {
 	docCnt := i.DocWordCount(docID, word)
 	docLen := i.DocLen(docID)
@@ -1,6 +1,7 @@
 package field
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -195,18 +196,33 @@ func Test_Scoring_BM25(t *testing.T) {
 		require.Equal(t, 0.0, result)
 	})
 
-	t.Run("can properly calculate TFIDF", func(t *testing.T) {
+	t.Run("can properly calculate BM25", func(t *testing.T) {
 		index := NewScoring()
 		index.Add(1, []string{"foo", "bar"})
 		index.Add(2, []string{"foo", "baz"})
 
 		result := index.BM25(1, 2.0, 0.75, "foo")
-		assert.Equal(t, 0.6, result)
+		assert.InDelta(t, math.Log(1.2), result, 1e-9)
 
 		result = index.BM25(1, 2.0, 0.75, "bar")
-		assert.Equal(t, 1.0158883083359673, result)
+		assert.InDelta(t, math.Log(2), result, 1e-9)
 
 		result = index.BM25(2, 2.0, 0.75, "baz")
-		assert.Equal(t, 1.0158883083359673, result)
+		assert.InDelta(t, math.Log(2), result, 1e-9)
+	})
+
+	t.Run("must use raw term frequency", func(t *testing.T) {
+		index := NewScoring()
+		index.Add(1, []string{"foo", "foo", "bar", "bar"})
+		index.Add(2, []string{"baz", "qux"})
+
+		// avgDocLen = 3, docLen = 4, tf = 2
+		idf := math.Log(1 + 1.5/1.5)
+		expected := idf * 2 * (1.2 + 1) / (2 + 1.2*(1-0.75+0.75*4.0/3.0))
+		assert.InDelta(t, expected, index.BM25(1, 1.2, 0.75, "foo"), 1e-9)
+
+		// no length normalization if b = 0
+		expected = idf * 2 * (1.2 + 1) / (2 + 1.2)
+		assert.InDelta(t, expected, index.BM25(1, 1.2, 0, "foo"), 1e-9)
 	})
 }
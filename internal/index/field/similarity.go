@@ -0,0 +1,134 @@
+package field
+
+import (
+	"fmt"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/schema"
+)
+
+// Similarity calculates document score for a single query token
+type Similarity interface {
+	Score(s *Scoring, docID uint32, token string) float64
+	// Explain describes how the score is calculated. Explanation value must be equal to the score
+	Explain(s *Scoring, docID uint32, token string) Explanation
+}
+
+var (
+	_ Similarity = BM25Similarity{}
+	_ Similarity = ClassicSimilarity{}
+	_ Similarity = BooleanSimilarity{}
+)
+
+// NewSimilarity builds similarity by its schema definition
+func NewSimilarity(s schema.Similarity) (Similarity, error) {
+	switch s.Type {
+	case "", schema.SimilarityBM25:
+		k1, b, err := s.BM25Params()
+		if err != nil {
+			return nil, err
+		}
+		return BM25Similarity{K1: k1, B: b}, nil
+	case schema.SimilarityClassic:
+		return ClassicSimilarity{}, nil
+	case schema.SimilarityBoolean:
+		return BooleanSimilarity{}, nil
+	}
+
+	return nil, errs.Errorf("unknown similarity type %q", s.Type)
+}
+
+type BM25Similarity struct {
+	K1 float64
+	B  float64
+}
+
+func (s BM25Similarity) Score(scoring *Scoring, docID uint32, token string) float64 {
+	return scoring.BM25(docID, s.K1, s.B, token)
+}
+
+func (s BM25Similarity) Explain(scoring *Scoring, docID uint32, token string) Explanation {
+	freq := float64(scoring.DocWordCount(docID, token))
+	if freq == 0 {
+		return tokenNotFoundExplanation(token)
+	}
+
+	docLen := float64(scoring.DocLen(docID))
+	avgDocLen := scoring.AvgDocLen()
+	tf := freq / (freq + s.K1*(1-s.B+s.B*docLen/avgDocLen))
+
+	return NewExplanation(
+		s.Score(scoring, docID, token),
+		fmt.Sprintf("score of token %q, computed as idf * tf * (k1 + 1) from:", token),
+		NewExplanation(
+			scoring.BM25IDF(token),
+			"idf, computed as log(1 + (N - n + 0.5) / (n + 0.5)) from:",
+			NewExplanation(float64(scoring.IndexWordCount(token)), "n, number of documents containing token"),
+			NewExplanation(float64(scoring.IndexDocCount()), "N, total number of documents"),
+		),
+		NewExplanation(
+			tf,
+			"tf, computed as freq / (freq + k1 * (1 - b + b * docLen / avgDocLen)) from:",
+			NewExplanation(freq, "freq, occurrences of token within document"),
+			NewExplanation(s.K1, "k1, term saturation parameter"),
+			NewExplanation(s.B, "b, length normalization parameter"),
+			NewExplanation(docLen, "docLen, length of document"),
+			NewExplanation(avgDocLen, "avgDocLen, average length of documents"),
+		),
+		NewExplanation(s.K1+1, "k1 + 1"),
+	)
+}
+
+// ClassicSimilarity scores documents by TF-IDF
+type ClassicSimilarity struct{}
+
+func (s ClassicSimilarity) Score(scoring *Scoring, docID uint32, token string) float64 {
+	return scoring.TFIDF(docID, token)
+}
+
+func (s ClassicSimilarity) Explain(scoring *Scoring, docID uint32, token string) Explanation {
+	freq := scoring.DocWordCount(docID, token)
+	if freq == 0 {
+		return tokenNotFoundExplanation(token)
+	}
+
+	return NewExplanation(
+		s.Score(scoring, docID, token),
+		fmt.Sprintf("score of token %q, computed as tf * idf from:", token),
+		NewExplanation(
+			scoring.TF(docID, token),
+			"tf, computed as freq / docLen from:",
+			NewExplanation(float64(freq), "freq, occurrences of token within document"),
+			NewExplanation(float64(scoring.DocLen(docID)), "docLen, length of document"),
+		),
+		NewExplanation(
+			scoring.IDF(docID, token),
+			"idf, computed as log(N / n) + 1 from:",
+			NewExplanation(float64(scoring.IndexWordCount(token)), "n, number of documents containing token"),
+			NewExplanation(float64(scoring.IndexDocCount()), "N, total number of documents"),
+		),
+	)
+}
+
+// BooleanSimilarity scores documents by the number of matching tokens ignoring their frequencies
+type BooleanSimilarity struct{}
+
+func (s BooleanSimilarity) Score(scoring *Scoring, docID uint32, token string) float64 {
+	if scoring.DocWordCount(docID, token) == 0 {
+		return 0
+	}
+
+	return 1
+}
+
+func (s BooleanSimilarity) Explain(scoring *Scoring, docID uint32, token string) Explanation {
+	if scoring.DocWordCount(docID, token) == 0 {
+		return tokenNotFoundExplanation(token)
+	}
+
+	return NewExplanation(1, fmt.Sprintf("score of token %q, document contains token", token))
+}
+
+func tokenNotFoundExplanation(token string) Explanation {
+	return NewExplanation(0, fmt.Sprintf("token %q not found in document", token))
+}
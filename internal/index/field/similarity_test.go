@@ -0,0 +1,143 @@
+package field
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewSimilarity(t *testing.T) {
+	t.Run("must return BM25 with default params by default", func(t *testing.T) {
+		s, err := NewSimilarity(schema.Similarity{})
+		require.NoError(t, err)
+		require.Equal(t, BM25Similarity{K1: schema.DefaultBM25K1, B: schema.DefaultBM25B}, s)
+	})
+
+	t.Run("must return BM25 with custom params", func(t *testing.T) {
+		s, err := NewSimilarity(schema.Similarity{Type: schema.SimilarityBM25, Settings: map[string]interface{}{"k1": 2, "b": 0.5}})
+		require.NoError(t, err)
+		require.Equal(t, BM25Similarity{K1: 2, B: 0.5}, s)
+	})
+
+	t.Run("must return error if BM25 params are invalid", func(t *testing.T) {
+		s, err := NewSimilarity(schema.Similarity{Type: schema.SimilarityBM25, Settings: map[string]interface{}{"b": 2}})
+		require.Error(t, err)
+		require.Nil(t, s)
+	})
+
+	t.Run("must return error if type is unknown", func(t *testing.T) {
+		s, err := NewSimilarity(schema.Similarity{Type: "unknown"})
+		require.Error(t, err)
+		require.Nil(t, s)
+	})
+}
+
+func Test_Similarity_Score(t *testing.T) {
+	scoring := NewScoring()
+	scoring.Add(1, []string{"foo", "foo", "bar"})
+	scoring.Add(2, []string{"bar"})
+
+	t.Run("classic", func(t *testing.T) {
+		require.Equal(t, scoring.TFIDF(1, "foo"), ClassicSimilarity{}.Score(scoring, 1, "foo"))
+		require.Equal(t, 0.0, ClassicSimilarity{}.Score(scoring, 2, "foo"))
+	})
+
+	t.Run("boolean", func(t *testing.T) {
+		require.Equal(t, 1.0, BooleanSimilarity{}.Score(scoring, 1, "foo"))
+		require.Equal(t, 1.0, BooleanSimilarity{}.Score(scoring, 2, "bar"))
+		require.Equal(t, 0.0, BooleanSimilarity{}.Score(scoring, 2, "foo"))
+	})
+
+	t.Run("must be used by query results", func(t *testing.T) {
+		ctx := context.Background()
+		field, err := New(schema.TypeText, FieldOpts{
+			Analyzer:   func(s []string) []string { return s },
+			Scoring:    NewScoring(),
+			Similarity: BooleanSimilarity{},
+		})
+		require.NoError(t, err)
+		field.Add(1, "foo")
+
+		result := field.TermQuery(ctx, "foo")
+		require.Equal(t, 1.0, result.Score(1))
+	})
+}
+
+func Test_Similarity_Explain(t *testing.T) {
+	scoring := NewScoring()
+	scoring.Add(1, []string{"foo", "foo", "bar"})
+	scoring.Add(2, []string{"bar"})
+
+	similarities := map[string]Similarity{
+		"bm25":    BM25Similarity{K1: schema.DefaultBM25K1, B: schema.DefaultBM25B},
+		"classic": ClassicSimilarity{},
+		"boolean": BooleanSimilarity{},
+	}
+	for name, s := range similarities {
+		t.Run(name+" explanation value must be equal to score", func(t *testing.T) {
+			for _, id := range []uint32{1, 2} {
+				for _, token := range []string{"foo", "bar"} {
+					require.InDelta(t, s.Score(scoring, id, token), s.Explain(scoring, id, token).Value, 1e-9)
+				}
+			}
+		})
+	}
+
+	t.Run("bm25 explanation must contain score components", func(t *testing.T) {
+		e := BM25Similarity{K1: 1.2, B: 0.75}.Explain(scoring, 1, "foo")
+		require.Len(t, e.Details, 3)
+
+		idf := e.Details[0]
+		require.Equal(t, scoring.BM25IDF("foo"), idf.Value)
+		require.Equal(t, 1.0, idf.Details[0].Value)
+		require.Equal(t, 2.0, idf.Details[1].Value)
+
+		tf := e.Details[1]
+		require.Equal(t, []float64{2, 1.2, 0.75, 3, 2}, explanationValues(tf.Details))
+		require.Equal(t, 2.2, e.Details[2].Value)
+	})
+}
+
+func Test_QueryResult_Explain(t *testing.T) {
+	ctx := context.Background()
+	field, err := New(schema.TypeText, FieldOpts{
+		Analyzer: func(s []string) []string { return strings.Fields(s[0]) },
+		Scoring:  NewScoring(),
+	})
+	require.NoError(t, err)
+	field.Add(1, "foo foo bar")
+	field.Add(2, "bar")
+
+	t.Run("explanation value must be equal to score", func(t *testing.T) {
+		result := field.MatchQuery(ctx, "foo bar").WithOpts(ctx, WithBoost(2))
+		for _, id := range []uint32{1, 2} {
+			e := result.Explain(id)
+			require.InDelta(t, result.Score(id), e.Value, 1e-9)
+			require.Equal(t, "product of:", e.Description)
+			require.Len(t, e.Details[0].Details, 2)
+		}
+	})
+
+	t.Run("must return no match explanation if document not found", func(t *testing.T) {
+		result := field.MatchQuery(ctx, "foo")
+		require.Equal(t, NoMatchExplanation(), result.Explain(2))
+		require.Equal(t, NoMatchExplanation(), result.ExplainToken(2, "foo"))
+	})
+
+	t.Run("must explain single token", func(t *testing.T) {
+		result := field.MatchQuery(ctx, "foo bar")
+		require.InDelta(t, result.TokenScore(1, "foo"), result.ExplainToken(1, "foo").Value, 1e-9)
+	})
+}
+
+func explanationValues(details []Explanation) []float64 {
+	result := make([]float64, len(details))
+	for i, d := range details {
+		result[i] = d.Value
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package field
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// TermCandidate is a term dictionary value close to a token
+type TermCandidate struct {
+	Term     string
+	Distance int
+	DocFreq  int
+}
+
+// SearchTokens analyzes value by the search analyzer
+func (f *Text) SearchTokens(value string) []string {
+	f.analyzerMtx.RLock()
+	analyzer := f.searchAnalyzer
+	f.analyzerMtx.RUnlock()
+
+	return analyzer([]string{value})
+}
+
+// DocCount returns number of documents having field value
+func (f *Text) DocCount() int {
+	return f.scoring.IndexDocCount()
+}
+
+// TermCount returns number of distinct terms
+func (f *Text) TermCount() int {
+	return f.values.Cardinality()
+}
+
+// DocFreq returns number of documents containing the term
+func (f *Text) DocFreq(term string) int {
+	return f.scoring.IndexWordCount(term)
+}
+
+// CoDocFreq returns number of documents containing both terms
+func (f *Text) CoDocFreq(term1 string, term2 string) int {
+	f.values.mtx.RLock()
+	defer f.values.mtx.RUnlock()
+
+	docs1, ok1 := f.values.Docs[term1]
+	docs2, ok2 := f.values.Docs[term2]
+	if !ok1 || !ok2 {
+		return 0
+	}
+
+	return int(docs1.AndCardinality(docs2))
+}
+
+// TermCandidates returns terms within maxEdits edit distance of the token (the token itself is excluded).
+// Candidates must share the first prefixLength characters with the token. They are ordered by document frequency.
+func (f *Text) TermCandidates(token string, maxEdits int, prefixLength int) []TermCandidate {
+	prefix := token
+	if utf8.RuneCountInString(token) > prefixLength {
+		prefix = string([]rune(token)[:prefixLength])
+	}
+	tokenRunes := []rune(token)
+
+	var terms []TermCandidate
+	f.values.mtx.RLock()
+	for i := sort.SearchStrings(f.values.List, prefix); i < len(f.values.List); i++ {
+		term := f.values.List[i]
+		if len(term) < len(prefix) || term[:len(prefix)] != prefix {
+			break
+		}
+		if term == token {
+			continue
+		}
+
+		if d := editDistance(tokenRunes, []rune(term), maxEdits); d <= maxEdits {
+			terms = append(terms, TermCandidate{Term: term, Distance: d})
+		}
+	}
+	f.values.mtx.RUnlock()
+
+	for i := range terms {
+		terms[i].DocFreq = f.DocFreq(terms[i].Term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].DocFreq != terms[j].DocFreq {
+			return terms[i].DocFreq > terms[j].DocFreq
+		}
+		if terms[i].Distance != terms[j].Distance {
+			return terms[i].Distance < terms[j].Distance
+		}
+		return terms[i].Term < terms[j].Term
+	})
+
+	return terms
+}
+
+// editDistance calculates Damerau-Levenshtein (optimal string alignment) distance.
+// Calculation stops if the distance exceeds max, max+1 is returned in this case
+func editDistance(a []rune, b []rune, max int) int {
+	if d := len(a) - len(b); d > max || -d > max {
+		return max + 1
+	}
+
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = minInt(cur[j], prev2[j-2]+1)
+			}
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	if prev[len(b)] > max {
+		return max + 1
+	}
+
+	return prev[len(b)]
+}
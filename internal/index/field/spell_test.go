@@ -0,0 +1,73 @@
+package field
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_editDistance(t *testing.T) {
+	data := []struct {
+		a, b     string
+		max      int
+		expected int
+	}{
+		{a: "", b: "", max: 2, expected: 0},
+		{a: "foo", b: "foo", max: 2, expected: 0},
+		{a: "foo", b: "fo", max: 2, expected: 1},
+		{a: "foo", b: "fao", max: 2, expected: 1},
+		{a: "form", b: "from", max: 2, expected: 1},
+		{a: "kitten", b: "sitting", max: 3, expected: 3},
+		{a: "kitten", b: "sitting", max: 2, expected: 3},
+		{a: "a", b: "abcd", max: 2, expected: 3},
+		{a: "привет", b: "привте", max: 2, expected: 1},
+	}
+
+	for _, d := range data {
+		require.Equal(t, d.expected, editDistance([]rune(d.a), []rune(d.b), d.max), "%q %q", d.a, d.b)
+	}
+}
+
+func Test_Text_TermCandidates(t *testing.T) {
+	field := newText(func(s []string) []string { return strings.Fields(s[0]) }, NewScoring())
+	field.Add(1, "search engine")
+	field.Add(2, "search query")
+	field.Add(3, "seared tuna")
+	field.Add(4, "march")
+	field.Add(5, "serve")
+
+	t.Run("must return candidates ordered by document frequency", func(t *testing.T) {
+		require.Equal(t, []TermCandidate{
+			{Term: "search", Distance: 1, DocFreq: 2},
+			{Term: "serve", Distance: 2, DocFreq: 1},
+		}, field.TermCandidates("serch", 2, 1))
+	})
+
+	t.Run("must respect max edits", func(t *testing.T) {
+		require.Equal(t, []TermCandidate{{Term: "search", Distance: 1, DocFreq: 2}}, field.TermCandidates("serch", 1, 1))
+	})
+
+	t.Run("must respect prefix length", func(t *testing.T) {
+		require.Empty(t, field.TermCandidates("tearch", 2, 1))
+		require.Equal(t, []TermCandidate{{Term: "search", Distance: 1, DocFreq: 2}}, field.TermCandidates("tearch", 1, 0))
+	})
+
+	t.Run("must not return the token itself", func(t *testing.T) {
+		require.Equal(t, []TermCandidate{{Term: "seared", Distance: 2, DocFreq: 1}}, field.TermCandidates("search", 2, 1))
+	})
+}
+
+func Test_Text_CoDocFreq(t *testing.T) {
+	field := newText(func(s []string) []string { return strings.Fields(s[0]) }, NewScoring())
+	field.Add(1, "foo bar")
+	field.Add(2, "foo baz")
+	field.Add(3, "foo bar baz")
+
+	require.Equal(t, 2, field.CoDocFreq("foo", "bar"))
+	require.Equal(t, 1, field.CoDocFreq("bar", "baz"))
+	require.Equal(t, 0, field.CoDocFreq("foo", "qux"))
+	require.Equal(t, 3, field.DocFreq("foo"))
+	require.Equal(t, 3, field.DocCount())
+	require.Equal(t, 3, field.TermCount())
+}
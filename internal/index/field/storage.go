@@ -62,7 +62,7 @@ func (s *Storage) AddIndex(name string, sc schema.Schema) (*Index, error) {
 		return nil, errs.Errorf("index dir %q create err: %w", src, err)
 	}
 
-	index, err := NewIndex(name, sc)
+	index, err := NewIndex(name, sc, schema.AnalyzerOpts{DataDir: s.src})
 	if err != nil {
 		return nil, errs.Errorf("index %q init err: %w", name, err)
 	}
@@ -104,7 +104,7 @@ func (s *Storage) loadIndex(index *Index) error {
 			return err
 		}
 
-		name := strings.TrimRight(info.Name(), fieldFileExt)
+		name := strings.TrimSuffix(info.Name(), fieldFileExt)
 		field, ok := index.fields[name]
 		if !ok {
 			return nil
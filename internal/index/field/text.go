@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"sync"
 
 	"github.com/RoaringBitmap/roaring"
 	"github.com/cyradin/search/internal/index/schema"
@@ -11,23 +12,40 @@ import (
 )
 
 type Text struct {
-	analyzer func([]string) []string
-	scoring  *Scoring
-	values   *docValues[string]
-	raw      *docValues[string]
+	analyzerMtx    sync.RWMutex
+	analyzer       func([]string) []string
+	searchAnalyzer func([]string) []string
+	scoring        *Scoring
+	similarity     Similarity
+	values         *docValues[string]
+	raw            *docValues[string]
 }
 
 var _ Field = (*Text)(nil)
 
 func newText(analyzer func([]string) []string, scoring *Scoring) *Text {
 	return &Text{
-		values:   newDocValues[string](),
-		raw:      newDocValues[string](),
-		analyzer: analyzer,
-		scoring:  scoring,
+		values:         newDocValues[string](),
+		raw:            newDocValues[string](),
+		analyzer:       analyzer,
+		searchAnalyzer: analyzer,
+		scoring:        scoring,
+		similarity:     BM25Similarity{K1: schema.DefaultBM25K1, B: schema.DefaultBM25B},
 	}
 }
 
+func (f *Text) setAnalyzers(analyzer func([]string) []string, searchAnalyzer func([]string) []string) {
+	if searchAnalyzer == nil {
+		searchAnalyzer = analyzer
+	}
+
+	f.analyzerMtx.Lock()
+	defer f.analyzerMtx.Unlock()
+
+	f.analyzer = analyzer
+	f.searchAnalyzer = searchAnalyzer
+}
+
 func (f *Text) Type() schema.Type {
 	return schema.TypeText
 }
@@ -40,7 +58,11 @@ func (f *Text) Add(id uint32, value interface{}) {
 
 	f.raw.Add(id, v)
 
-	terms := f.analyzer([]string{v})
+	f.analyzerMtx.RLock()
+	analyzer := f.analyzer
+	f.analyzerMtx.RUnlock()
+
+	terms := analyzer([]string{v})
 	f.scoring.Add(id, terms)
 
 	for _, vv := range terms {
@@ -55,7 +77,7 @@ func (f *Text) TermQuery(ctx context.Context, value interface{}) *QueryResult {
 	}
 	docs := f.values.DocsByValue(v)
 
-	return newResultWithScoring(ctx, docs, f.scoring, WithTokens([]string{v}))
+	return newResultWithScoring(ctx, docs, f.scoring, f.similarity, WithTokens([]string{v}))
 }
 
 func (f *Text) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
@@ -63,7 +85,11 @@ func (f *Text) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
 	if err != nil {
 		return newResult(ctx, roaring.New())
 	}
-	tokens := f.analyzer([]string{val})
+	f.analyzerMtx.RLock()
+	analyzer := f.searchAnalyzer
+	f.analyzerMtx.RUnlock()
+
+	tokens := analyzer([]string{val})
 
 	var result *roaring.Bitmap
 	for _, value := range tokens {
@@ -84,13 +110,21 @@ func (f *Text) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
 		return newResult(ctx, roaring.New())
 	}
 
-	return newResultWithScoring(ctx, result, f.scoring, WithTokens(tokens))
+	return newResultWithScoring(ctx, result, f.scoring, f.similarity, WithTokens(tokens))
 }
 
 func (f *Text) RangeQuery(ctx context.Context, from interface{}, to interface{}, incFrom, incTo bool) *QueryResult {
 	return newResult(ctx, roaring.New())
 }
 
+func (f *Text) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, expandPattern(f.values, pattern, maxExpansions))
+}
+
+func (f *Text) ExistsQuery(ctx context.Context) *QueryResult {
+	return newResult(ctx, f.raw.AllDocs())
+}
+
 func (f *Text) DeleteDoc(id uint32) {
 	if !f.values.ContainsDoc(id) {
 		return
@@ -71,6 +71,27 @@ func Test_Text_MatchQuery(t *testing.T) {
 	})
 }
 
+func Test_Text_PatternQuery(t *testing.T) {
+	f := newText(testAnalyzer2, NewScoring())
+	f.Add(1, "foo bar")
+	f.Add(2, "baz")
+
+	p, err := NewRegexpPattern("ba[rz]", false)
+	require.NoError(t, err)
+	result := f.PatternQuery(context.Background(), p, 0)
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	require.Equal(t, 1.0, result.Score(1))
+}
+
+func Test_Text_ExistsQuery(t *testing.T) {
+	f := newText(testAnalyzer2, NewScoring())
+	f.Add(1, "foo bar")
+	f.Add(2, "baz")
+
+	result := f.ExistsQuery(context.Background())
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+}
+
 func Test_Text_DeleteDoc(t *testing.T) {
 	field := newText(testAnalyzer2, NewScoring())
 	field.Add(1, "foo")
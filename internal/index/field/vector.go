@@ -0,0 +1,347 @@
+package field
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/spf13/cast"
+)
+
+var _ Field = (*DenseVector)(nil)
+
+// VectorSimilarity compares dense vectors. Score is positive and greater for closer vectors
+type VectorSimilarity interface {
+	Score(a []float32, b []float32) float64
+	// Description describes how the score is calculated
+	Description() string
+}
+
+func NewVectorSimilarity(s schema.Similarity) (VectorSimilarity, error) {
+	switch s.Type {
+	case "", schema.SimilarityCosine:
+		return CosineSimilarity{}, nil
+	case schema.SimilarityDotProduct:
+		return DotProductSimilarity{}, nil
+	case schema.SimilarityL2Norm:
+		return L2NormSimilarity{}, nil
+	}
+
+	return nil, errs.Errorf("unknown vector similarity type %q", s.Type)
+}
+
+// CosineSimilarity score is (1 + cos(a, b)) / 2
+type CosineSimilarity struct{}
+
+func (CosineSimilarity) Score(a []float32, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return (1 + dot/math.Sqrt(normA*normB)) / 2
+}
+
+func (CosineSimilarity) Description() string {
+	return "cosine similarity, (1 + cos(query, vector)) / 2"
+}
+
+// DotProductSimilarity score is 1 + dot(a, b) for positive products and 1 / (1 - dot(a, b)) for negative ones
+type DotProductSimilarity struct{}
+
+func (DotProductSimilarity) Score(a []float32, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	if dot < 0 {
+		return 1 / (1 - dot)
+	}
+
+	return 1 + dot
+}
+
+func (DotProductSimilarity) Description() string {
+	return "dot product similarity, 1 + dot(query, vector) or 1 / (1 - dot(query, vector)) if negative"
+}
+
+// L2NormSimilarity score is 1 / (1 + l2_norm(a, b)^2)
+type L2NormSimilarity struct{}
+
+func (L2NormSimilarity) Score(a []float32, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+
+	return 1 / (1 + sum)
+}
+
+func (L2NormSimilarity) Description() string {
+	return "l2 norm similarity, 1 / (1 + l2_norm(query, vector)^2)"
+}
+
+// VectorHit is a document found by kNN search
+type VectorHit struct {
+	ID    uint32
+	Score float64
+}
+
+// DenseVector contains fixed size vectors. One vector per document is allowed.
+// Approximate kNN search is performed by HNSW graph which is updated with every added document
+type DenseVector struct {
+	mtx        sync.RWMutex
+	dims       int
+	similarity VectorSimilarity
+	vectors    map[uint32][]float32
+	docs       *roaring.Bitmap
+	graph      *hnsw
+}
+
+func newDenseVector(dims int, similarity VectorSimilarity) *DenseVector {
+	result := &DenseVector{
+		dims:       dims,
+		similarity: similarity,
+		vectors:    make(map[uint32][]float32),
+		docs:       roaring.New(),
+	}
+	result.graph = newHNSW(result.vector, similarity.Score)
+
+	return result
+}
+
+func (f *DenseVector) Type() schema.Type {
+	return schema.TypeDenseVector
+}
+
+// Dims returns number of vector dimensions
+func (f *DenseVector) Dims() int {
+	return f.dims
+}
+
+// Similarity returns vector similarity used to score documents
+func (f *DenseVector) Similarity() VectorSimilarity {
+	return f.similarity
+}
+
+// vector must be called under the lock
+func (f *DenseVector) vector(id uint32) []float32 {
+	return f.vectors[id]
+}
+
+// Add adds or replaces the document vector. Values with invalid number of dimensions are ignored
+func (f *DenseVector) Add(id uint32, value interface{}) {
+	v, err := CastVector(value)
+	if err != nil || len(v) != f.dims {
+		return
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.graph.Delete(id)
+	f.vectors[id] = v
+	f.docs.Add(id)
+	f.graph.Add(id)
+}
+
+// CastVector converts a list of numbers to vector
+func CastVector(value interface{}) ([]float32, error) {
+	switch v := value.(type) {
+	case []float32:
+		return v, nil
+	case []float64:
+		result := make([]float32, len(v))
+		for i, item := range v {
+			result[i] = float32(item)
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]float32, len(v))
+		for i, item := range v {
+			f, err := cast.ToFloat32E(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = f
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unable to cast %#v of type %T to vector", value, value)
+}
+
+func (f *DenseVector) TermQuery(ctx context.Context, value interface{}) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *DenseVector) MatchQuery(ctx context.Context, value interface{}) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *DenseVector) RangeQuery(ctx context.Context, from interface{}, to interface{}, incFrom, incTo bool) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *DenseVector) PatternQuery(ctx context.Context, pattern Pattern, maxExpansions int) *QueryResult {
+	return newResult(ctx, roaring.New())
+}
+
+func (f *DenseVector) ExistsQuery(ctx context.Context) *QueryResult {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return newResult(ctx, f.docs.Clone())
+}
+
+// KNN returns k documents closest to the vector ordered by score. numCandidates nearest candidates are checked on the HNSW graph bottom layer.
+// Only documents from filter are returned if it is not nil. Exact search is used if there are no more than numCandidates documents in the filter
+func (f *DenseVector) KNN(vector []float32, k int, numCandidates int, filter *roaring.Bitmap) []VectorHit {
+	if filter != nil && filter.GetCardinality() <= uint64(numCandidates) {
+		return f.ExactKNN(vector, k, filter)
+	}
+
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	var accept func(id uint32) bool
+	if filter != nil {
+		accept = filter.Contains
+	}
+
+	items := f.graph.Search(vector, k, numCandidates, accept)
+	result := make([]VectorHit, len(items))
+	for i, item := range items {
+		result[i] = VectorHit{ID: item.id, Score: item.score}
+	}
+
+	return result
+}
+
+// ExactKNN returns k documents closest to the vector ordered by score. Vector is compared with all the documents.
+// Only documents from filter are checked if it is not nil
+func (f *DenseVector) ExactKNN(vector []float32, k int, filter *roaring.Bitmap) []VectorHit {
+	if k <= 0 {
+		return nil
+	}
+
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	docs := f.docs
+	if filter != nil {
+		docs = roaring.And(docs, filter)
+	}
+
+	// the worst of the best k hits is on top
+	results := &hnswQueue{}
+	docs.Iterate(func(id uint32) bool {
+		item := hnswItem{id: id, score: f.similarity.Score(vector, f.vectors[id])}
+		if results.Len() < k {
+			heap.Push(results, item)
+		} else if item.score > results.top().score {
+			results.items[0] = item
+			heap.Fix(results, 0)
+		}
+		return true
+	})
+
+	sortHNSWItems(results.items)
+	result := make([]VectorHit, len(results.items))
+	for i, item := range results.items {
+		result[i] = VectorHit{ID: item.id, Score: item.score}
+	}
+
+	return result
+}
+
+func (f *DenseVector) DeleteDoc(id uint32) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !f.docs.Contains(id) {
+		return
+	}
+
+	f.graph.Delete(id)
+	delete(f.vectors, id)
+	f.docs.Remove(id)
+}
+
+func (f *DenseVector) Data(id uint32) []interface{} {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	v, ok := f.vectors[id]
+	if !ok {
+		return []interface{}{}
+	}
+
+	return []interface{}{v}
+}
+
+func (f *DenseVector) MinValue() (interface{}, *roaring.Bitmap) {
+	return nil, roaring.New()
+}
+
+func (f *DenseVector) MaxValue() (interface{}, *roaring.Bitmap) {
+	return nil, roaring.New()
+}
+
+func (f *DenseVector) TermAgg(ctx context.Context, docs *roaring.Bitmap, size int) TermAggResult {
+	return TermAggResult{
+		Buckets: []TermBucket{},
+	}
+}
+
+type denseVectorData struct {
+	Vectors map[uint32][]float32
+	Graph   hnswData
+}
+
+func (f *DenseVector) MarshalBinary() ([]byte, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(denseVectorData{Vectors: f.vectors, Graph: f.graph.data()})
+
+	return buf.Bytes(), err
+}
+
+func (f *DenseVector) UnmarshalBinary(data []byte) error {
+	raw := denseVectorData{}
+	buf := bytes.NewBuffer(data)
+	err := gob.NewDecoder(buf).Decode(&raw)
+	if err != nil {
+		return err
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.vectors = raw.Vectors
+	if f.vectors == nil {
+		f.vectors = make(map[uint32][]float32)
+	}
+	f.docs = roaring.New()
+	for id := range f.vectors {
+		f.docs.Add(id)
+	}
+	f.graph.load(raw.Graph)
+
+	return nil
+}
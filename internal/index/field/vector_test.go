@@ -0,0 +1,228 @@
+package field
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVectors(rnd *rand.Rand, n int, dims int) [][]float32 {
+	result := make([][]float32, n)
+	for i := range result {
+		result[i] = make([]float32, dims)
+		for j := range result[i] {
+			result[i][j] = rnd.Float32()*2 - 1
+		}
+	}
+
+	return result
+}
+
+// recall returns share of expected hits found
+func recall(expected []VectorHit, actual []VectorHit) float64 {
+	ids := make(map[uint32]struct{}, len(actual))
+	for _, h := range actual {
+		ids[h.ID] = struct{}{}
+	}
+
+	found := 0
+	for _, h := range expected {
+		if _, ok := ids[h.ID]; ok {
+			found++
+		}
+	}
+
+	return float64(found) / float64(len(expected))
+}
+
+func Test_VectorSimilarity(t *testing.T) {
+	data := []struct {
+		name       string
+		similarity VectorSimilarity
+		a          []float32
+		b          []float32
+		expected   float64
+	}{
+		{name: "cosine same direction", similarity: CosineSimilarity{}, a: []float32{1, 0}, b: []float32{2, 0}, expected: 1},
+		{name: "cosine orthogonal", similarity: CosineSimilarity{}, a: []float32{1, 0}, b: []float32{0, 1}, expected: 0.5},
+		{name: "cosine opposite", similarity: CosineSimilarity{}, a: []float32{1, 0}, b: []float32{-1, 0}, expected: 0},
+		{name: "dot product positive", similarity: DotProductSimilarity{}, a: []float32{1, 2}, b: []float32{3, 4}, expected: 12},
+		{name: "dot product negative", similarity: DotProductSimilarity{}, a: []float32{1, 0}, b: []float32{-3, 0}, expected: 0.25},
+		{name: "l2 norm same", similarity: L2NormSimilarity{}, a: []float32{1, 2}, b: []float32{1, 2}, expected: 1},
+		{name: "l2 norm", similarity: L2NormSimilarity{}, a: []float32{0, 0}, b: []float32{1, 1}, expected: 1.0 / 3},
+	}
+
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			require.InDelta(t, d.expected, d.similarity.Score(d.a, d.b), 1e-9)
+		})
+	}
+}
+
+func Test_DenseVector_Add(t *testing.T) {
+	field := newDenseVector(2, CosineSimilarity{})
+	field.Add(1, []interface{}{json.Number("1"), json.Number("0.5")})
+	field.Add(2, []float32{0, 1})
+	field.Add(3, []interface{}{json.Number("1")})
+	field.Add(4, "foo")
+
+	require.Equal(t, []interface{}{[]float32{1, 0.5}}, field.Data(1))
+	require.Equal(t, []interface{}{[]float32{0, 1}}, field.Data(2))
+	require.Equal(t, []interface{}{}, field.Data(3))
+	require.Equal(t, []interface{}{}, field.Data(4))
+	require.Equal(t, []uint32{1, 2}, field.ExistsQuery(context.Background()).Docs().ToArray())
+
+	field.Add(2, []float32{1, 1})
+	require.Equal(t, []interface{}{[]float32{1, 1}}, field.Data(2))
+	require.Equal(t, 2, field.graph.Len())
+}
+
+func Test_DenseVector_KNN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	vectors := randomVectors(rnd, 1000, 8)
+	queries := randomVectors(rnd, 20, 8)
+
+	similarities := []struct {
+		name       string
+		similarity VectorSimilarity
+	}{
+		{name: "cosine", similarity: CosineSimilarity{}},
+		{name: "dot product", similarity: DotProductSimilarity{}},
+		{name: "l2 norm", similarity: L2NormSimilarity{}},
+	}
+	for _, s := range similarities {
+		similarity := s.similarity
+		field := newDenseVector(8, similarity)
+		for i, v := range vectors {
+			field.Add(uint32(i+1), v)
+		}
+
+		t.Run(s.name, func(t *testing.T) {
+			total := 0.0
+			for _, q := range queries {
+				expected := field.ExactKNN(q, 10, nil)
+				require.Len(t, expected, 10)
+				for i := 1; i < len(expected); i++ {
+					require.GreaterOrEqual(t, expected[i-1].Score, expected[i].Score)
+				}
+
+				actual := field.KNN(q, 10, 50, nil)
+				require.Len(t, actual, 10)
+				for _, h := range actual {
+					require.InDelta(t, similarity.Score(q, vectors[h.ID-1]), h.Score, 1e-9)
+				}
+				total += recall(expected, actual)
+			}
+			require.Greater(t, total/float64(len(queries)), 0.9)
+		})
+	}
+}
+
+func Test_DenseVector_KNN_Filter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	field := newDenseVector(4, L2NormSimilarity{})
+	for i, v := range randomVectors(rnd, 500, 4) {
+		field.Add(uint32(i+1), v)
+	}
+	q := randomVectors(rnd, 1, 4)[0]
+
+	t.Run("must return only filtered documents", func(t *testing.T) {
+		filter := roaring.New()
+		for i := uint32(2); i <= 500; i += 2 {
+			filter.Add(i)
+		}
+
+		expected := field.ExactKNN(q, 10, filter)
+		actual := field.KNN(q, 10, 50, filter)
+		for _, h := range actual {
+			require.True(t, filter.Contains(h.ID))
+		}
+		require.Greater(t, recall(expected, actual), 0.7)
+	})
+
+	t.Run("must use exact search for small filters", func(t *testing.T) {
+		filter := roaring.BitmapOf(3, 30, 300, 1000)
+
+		actual := field.KNN(q, 10, 50, filter)
+		require.Equal(t, field.ExactKNN(q, 10, filter), actual)
+		require.Len(t, actual, 3)
+	})
+}
+
+func Test_DenseVector_DeleteDoc(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	vectors := randomVectors(rnd, 300, 4)
+	field := newDenseVector(4, CosineSimilarity{})
+	for i, v := range vectors {
+		field.Add(uint32(i+1), v)
+	}
+
+	for i := uint32(1); i <= 300; i += 3 {
+		field.DeleteDoc(i)
+	}
+	field.DeleteDoc(field.graph.entry)
+	require.Equal(t, field.docs.GetCardinality(), uint64(field.graph.Len()))
+
+	for _, node := range field.graph.nodes {
+		for _, neighbors := range node.Neighbors {
+			for _, n := range neighbors {
+				require.True(t, field.docs.Contains(n))
+			}
+		}
+	}
+
+	total := 0.0
+	queries := randomVectors(rnd, 10, 4)
+	for _, q := range queries {
+		expected := field.ExactKNN(q, 10, nil)
+		actual := field.KNN(q, 10, 50, nil)
+		for _, h := range actual {
+			require.True(t, field.docs.Contains(h.ID))
+		}
+		total += recall(expected, actual)
+	}
+	require.Greater(t, total/float64(len(queries)), 0.9)
+
+	for id := range field.graph.nodes {
+		field.DeleteDoc(id)
+	}
+	require.Equal(t, 0, field.graph.Len())
+	require.Empty(t, field.KNN(vectors[1], 10, 50, nil))
+}
+
+func Test_DenseVector_Marshal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	field := newDenseVector(4, CosineSimilarity{})
+	for i, v := range randomVectors(rnd, 100, 4) {
+		field.Add(uint32(i+1), v)
+	}
+	field.DeleteDoc(10)
+
+	data, err := field.MarshalBinary()
+	require.NoError(t, err)
+
+	field2 := newDenseVector(4, CosineSimilarity{})
+	err = field2.UnmarshalBinary(data)
+	require.NoError(t, err)
+
+	require.Equal(t, field.vectors, field2.vectors)
+	require.True(t, field.docs.Equals(field2.docs))
+	require.Equal(t, field.graph.entry, field2.graph.entry)
+	require.Equal(t, field.graph.maxLevel, field2.graph.maxLevel)
+	require.Equal(t, field.graph.Len(), field2.graph.Len())
+	for id, node := range field.graph.nodes {
+		require.Equal(t, node.Level, field2.graph.nodes[id].Level)
+		require.Len(t, field2.graph.nodes[id].Neighbors, len(node.Neighbors))
+		for l := range node.Neighbors {
+			require.ElementsMatch(t, node.Neighbors[l], field2.graph.nodes[id].Neighbors[l])
+		}
+	}
+
+	q := []float32{0.5, -0.5, 0.1, math.SmallestNonzeroFloat32}
+	require.Equal(t, field.KNN(q, 5, 20, nil), field2.KNN(q, 5, 20, nil))
+}
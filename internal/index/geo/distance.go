@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/spf13/cast"
+)
+
+// EarthRadius mean earth radius in meters
+const EarthRadius = 6371008.8
+
+// DistanceUnits are distance units with their lengths in meters
+var DistanceUnits = map[string]float64{
+	"mm":  0.001,
+	"cm":  0.01,
+	"m":   1,
+	"km":  1000,
+	"in":  0.0254,
+	"ft":  0.3048,
+	"yd":  0.9144,
+	"mi":  1609.344,
+	"nmi": 1852,
+}
+
+// Distance returns great-circle distance between points in meters (haversine formula)
+func Distance(a Point, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ParseDistance parses distance to meters. Numbers are meters, strings may have a unit suffix, e.g. "1.5km"
+func ParseDistance(value interface{}) (float64, error) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case json.Number, float64, float32, int, int64, int32, uint, uint64, uint32:
+		d, err := cast.ToFloat64E(v)
+		if err != nil || d < 0 {
+			return 0, errs.Errorf("invalid distance %v", value)
+		}
+		return d, nil
+	default:
+		return 0, errs.Errorf("required number or string, got %#v", value)
+	}
+
+	s = strings.TrimSpace(s)
+	number, unit := s, "m"
+	for i, c := range s {
+		if (c < '0' || c > '9') && c != '.' {
+			number, unit = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+			break
+		}
+	}
+
+	multiplier, ok := DistanceUnits[unit]
+	if !ok {
+		return 0, errs.Errorf("unknown distance unit %q", unit)
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errs.Errorf("invalid distance %q", s)
+	}
+
+	return v * multiplier, nil
+}
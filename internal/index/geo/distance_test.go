@@ -0,0 +1,40 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Distance(t *testing.T) {
+	moscow := Point{Lat: 55.7558, Lon: 37.6173}
+	london := Point{Lat: 51.5074, Lon: -0.1278}
+
+	require.InDelta(t, 2500000, Distance(moscow, london), 10000)
+	require.Equal(t, Distance(moscow, london), Distance(london, moscow))
+	require.Equal(t, 0.0, Distance(moscow, moscow))
+	require.InDelta(t, 111195, Distance(Point{Lat: 0, Lon: 179.5}, Point{Lat: 0, Lon: -179.5}), 1)
+}
+
+func Test_ParseDistance(t *testing.T) {
+	valid := map[interface{}]float64{
+		json.Number("100"): 100,
+		1.5:                1.5,
+		"12":               12,
+		"1.5km":            1500,
+		"2 mi":             3218.688,
+		"10nmi":            18520,
+		"30cm":             0.3,
+	}
+	for v, expected := range valid {
+		d, err := ParseDistance(v)
+		require.NoError(t, err, v)
+		require.InDelta(t, expected, d, 1e-9, v)
+	}
+
+	for _, v := range []interface{}{nil, -1, "", "km", "1 parsec", "1.2.3m", true} {
+		_, err := ParseDistance(v)
+		require.Error(t, err, v)
+	}
+}
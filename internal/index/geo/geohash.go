@@ -0,0 +1,143 @@
+package geo
+
+import (
+	"math"
+	"strings"
+
+	"github.com/cyradin/search/internal/errs"
+)
+
+// MaxGeohashPrecision max geohash length. Cells of this precision are smaller than 4 cm
+const MaxGeohashPrecision = 12
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash returns geohash of the point with the given length
+func EncodeGeohash(p Point, precision int) string {
+	latMin, latMax := -90.0, 90.0
+	lonMin, lonMax := -180.0, 180.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	bit, ch, even := 0, 0, true
+	for sb.Len() < precision {
+		// even bits are longitude bits
+		if even {
+			mid := (lonMin + lonMax) / 2
+			if p.Lon >= mid {
+				ch = ch<<1 | 1
+				lonMin = mid
+			} else {
+				ch <<= 1
+				lonMax = mid
+			}
+		} else {
+			mid := (latMin + latMax) / 2
+			if p.Lat >= mid {
+				ch = ch<<1 | 1
+				latMin = mid
+			} else {
+				ch <<= 1
+				latMax = mid
+			}
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return sb.String()
+}
+
+// DecodeGeohash returns geohash cell
+func DecodeGeohash(hash string) (Rect, error) {
+	if hash == "" || len(hash) > MaxGeohashPrecision {
+		return Rect{}, errs.Errorf("geohash length must be between 1 and %d, got %q", MaxGeohashPrecision, hash)
+	}
+
+	r := Rect{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(geohashAlphabet, hash[i])
+		if idx < 0 {
+			return Rect{}, errs.Errorf("invalid geohash %q", hash)
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := idx>>bit&1 == 1
+			if even {
+				mid := (r.MinLon + r.MaxLon) / 2
+				if set {
+					r.MinLon = mid
+				} else {
+					r.MaxLon = mid
+				}
+			} else {
+				mid := (r.MinLat + r.MaxLat) / 2
+				if set {
+					r.MinLat = mid
+				} else {
+					r.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return r, nil
+}
+
+// geohashCellSize returns cell height and width in degrees
+func geohashCellSize(precision int) (float64, float64) {
+	bits := 5 * precision
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+
+	return 180 / float64(uint64(1)<<latBits), 360 / float64(uint64(1)<<lonBits)
+}
+
+// CoverGeohashes returns geohashes of cells covering the rect. The most precise cells are used
+// if their number does not exceed maxCells. Rects crossing the antimeridian are not supported, use Split first
+func CoverGeohashes(r Rect, maxCells int) []string {
+	precision := 1
+	for ; precision < MaxGeohashPrecision; precision++ {
+		if coverSize(r, precision+1) > maxCells {
+			break
+		}
+	}
+
+	height, width := geohashCellSize(precision)
+	minLat, maxLat := cellIndex(r.MinLat, -90, height), cellIndex(r.MaxLat, -90, height)
+	minLon, maxLon := cellIndex(r.MinLon, -180, width), cellIndex(r.MaxLon, -180, width)
+
+	result := make([]string, 0, (maxLat-minLat+1)*(maxLon-minLon+1))
+	for i := minLat; i <= maxLat; i++ {
+		for j := minLon; j <= maxLon; j++ {
+			center := Point{Lat: -90 + (float64(i)+0.5)*height, Lon: -180 + (float64(j)+0.5)*width}
+			result = append(result, EncodeGeohash(center, precision))
+		}
+	}
+
+	return result
+}
+
+func coverSize(r Rect, precision int) int {
+	height, width := geohashCellSize(precision)
+	lat := cellIndex(r.MaxLat, -90, height) - cellIndex(r.MinLat, -90, height) + 1
+	lon := cellIndex(r.MaxLon, -180, width) - cellIndex(r.MinLon, -180, width) + 1
+
+	return lat * lon
+}
+
+// cellIndex returns index of the cell containing the coordinate. The last cell includes the max coordinate
+func cellIndex(v float64, min float64, size float64) int {
+	idx := int((v - min) / size)
+	if max := int(math.Round((-2 * min) / size)); idx >= max {
+		idx = max - 1
+	}
+
+	return idx
+}
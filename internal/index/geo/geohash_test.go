@@ -0,0 +1,65 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncodeGeohash(t *testing.T) {
+	p := Point{Lat: 57.64911, Lon: 10.40744}
+
+	require.Equal(t, "u4pruydqqvj8", EncodeGeohash(p, MaxGeohashPrecision))
+	require.Equal(t, "u4pru", EncodeGeohash(p, 5))
+	require.Equal(t, "u", EncodeGeohash(p, 1))
+}
+
+func Test_DecodeGeohash(t *testing.T) {
+	r, err := DecodeGeohash("u4pruydqqvj8")
+	require.NoError(t, err)
+	require.True(t, r.Contains(Point{Lat: 57.64911, Lon: 10.40744}))
+	require.InDelta(t, 57.64911, r.Center().Lat, 1e-6)
+	require.InDelta(t, 10.40744, r.Center().Lon, 1e-6)
+
+	r, err = DecodeGeohash("s")
+	require.NoError(t, err)
+	require.Equal(t, Rect{MinLat: 0, MaxLat: 45, MinLon: 0, MaxLon: 45}, r)
+
+	for _, hash := range []string{"", "a", "u4pruydqqvj8u"} {
+		_, err := DecodeGeohash(hash)
+		require.Error(t, err, hash)
+	}
+}
+
+func Test_CoverGeohashes(t *testing.T) {
+	t.Run("must cover the rect with cells", func(t *testing.T) {
+		r := Rect{MinLat: 55.5, MaxLat: 56, MinLon: 37.3, MaxLon: 38}
+		cells := CoverGeohashes(r, 64)
+		require.NotEmpty(t, cells)
+		require.LessOrEqual(t, len(cells), 64)
+
+		for _, p := range []Point{{Lat: 55.5, Lon: 37.3}, {Lat: 56, Lon: 38}, {Lat: 55.75, Lon: 37.61}} {
+			found := false
+			for _, cell := range cells {
+				cr, err := DecodeGeohash(cell)
+				require.NoError(t, err)
+				if cr.Contains(p) {
+					found = true
+					break
+				}
+			}
+			require.True(t, found, p)
+		}
+	})
+
+	t.Run("must return the whole world cells", func(t *testing.T) {
+		cells := CoverGeohashes(Rect{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}, 1)
+		require.Len(t, cells, 32)
+	})
+
+	t.Run("must return one precise cell for a point", func(t *testing.T) {
+		p := Point{Lat: 57.64911, Lon: 10.40744}
+		cells := CoverGeohashes(Rect{MinLat: p.Lat, MaxLat: p.Lat, MinLon: p.Lon, MaxLon: p.Lon}, 64)
+		require.Equal(t, []string{EncodeGeohash(p, MaxGeohashPrecision)}, cells)
+	})
+}
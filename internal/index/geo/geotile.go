@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cyradin/search/internal/errs"
+)
+
+// MaxGeotileZoom max geotile zoom level
+const MaxGeotileZoom = 29
+
+// maxMercatorLat web mercator projection latitude limit. Points beyond it belong to the edge tiles
+const maxMercatorLat = 85.0511287798066
+
+// EncodeGeotile returns "zoom/x/y" key of the web map tile containing the point
+func EncodeGeotile(p Point, zoom int) string {
+	tiles := float64(uint64(1) << zoom)
+	lat := math.Max(-maxMercatorLat, math.Min(maxMercatorLat, p.Lat)) * math.Pi / 180
+
+	x := int(math.Floor((p.Lon + 180) / 360 * tiles))
+	y := int(math.Floor((1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * tiles))
+
+	return fmt.Sprintf("%d/%d/%d", zoom, clampTile(x, zoom), clampTile(y, zoom))
+}
+
+func clampTile(v int, zoom int) int {
+	if v < 0 {
+		return 0
+	}
+	if max := 1<<zoom - 1; v > max {
+		return max
+	}
+
+	return v
+}
+
+// DecodeGeotile returns bounds of the "zoom/x/y" tile
+func DecodeGeotile(key string) (Rect, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return Rect{}, errs.Errorf("invalid geotile %q", key)
+	}
+
+	var v [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Rect{}, errs.Errorf("invalid geotile %q", key)
+		}
+		v[i] = n
+	}
+	zoom, x, y := v[0], v[1], v[2]
+	if zoom > MaxGeotileZoom || x >= 1<<zoom || y >= 1<<zoom {
+		return Rect{}, errs.Errorf("invalid geotile %q", key)
+	}
+
+	return Rect{
+		MinLat: tileLat(y+1, zoom),
+		MaxLat: tileLat(y, zoom),
+		MinLon: tileLon(x, zoom),
+		MaxLon: tileLon(x+1, zoom),
+	}, nil
+}
+
+func tileLon(x int, zoom int) float64 {
+	return float64(x)/float64(uint64(1)<<zoom)*360 - 180
+}
+
+func tileLat(y int, zoom int) float64 {
+	n := math.Pi * (1 - 2*float64(y)/float64(uint64(1)<<zoom))
+	return math.Atan(math.Sinh(n)) * 180 / math.Pi
+}
@@ -0,0 +1,35 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncodeGeotile(t *testing.T) {
+	p := Point{Lat: 55.7558, Lon: 37.6173}
+
+	require.Equal(t, "0/0/0", EncodeGeotile(p, 0))
+	require.Equal(t, "1/1/0", EncodeGeotile(p, 1))
+	require.Equal(t, "10/619/320", EncodeGeotile(p, 10))
+	require.Equal(t, "2/3/3", EncodeGeotile(Point{Lat: -90, Lon: 180}, 2))
+	require.Equal(t, "2/0/0", EncodeGeotile(Point{Lat: 90, Lon: -180}, 2))
+}
+
+func Test_DecodeGeotile(t *testing.T) {
+	r, err := DecodeGeotile("1/1/0")
+	require.NoError(t, err)
+	require.Equal(t, 0.0, r.MinLat)
+	require.InDelta(t, maxMercatorLat, r.MaxLat, 1e-9)
+	require.Equal(t, Rect{MinLat: 0, MaxLat: r.MaxLat, MinLon: 0, MaxLon: 180}, r)
+
+	p := Point{Lat: 55.7558, Lon: 37.6173}
+	r, err = DecodeGeotile(EncodeGeotile(p, 15))
+	require.NoError(t, err)
+	require.True(t, r.Contains(p))
+
+	for _, key := range []string{"", "1/1", "a/1/1", "1/2/0", "1/0/-1", "30/0/0"} {
+		_, err := DecodeGeotile(key)
+		require.Error(t, err, key)
+	}
+}
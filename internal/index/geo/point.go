@@ -0,0 +1,145 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/spf13/cast"
+)
+
+// Point geographic point in degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func (p Point) Validate() error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return errs.Errorf("latitude must be between -90 and 90, got %v", p.Lat)
+	}
+	if p.Lon < -180 || p.Lon > 180 {
+		return errs.Errorf("longitude must be between -180 and 180, got %v", p.Lon)
+	}
+
+	return nil
+}
+
+// ParsePoint parses a point from an object {"lat": 1.5, "lon": 2}, a "lat,lon" string or a geohash.
+// Geohash is parsed to its cell center
+func ParsePoint(value interface{}) (Point, error) {
+	var (
+		p   Point
+		err error
+	)
+
+	switch v := value.(type) {
+	case Point:
+		p = v
+	case map[string]interface{}:
+		for key := range v {
+			if key != "lat" && key != "lon" {
+				return Point{}, errs.Errorf("key %q is not allowed", key)
+			}
+		}
+		lat, ok1 := v["lat"]
+		lon, ok2 := v["lon"]
+		if !ok1 || !ok2 {
+			return Point{}, errs.Errorf("lat and lon are required")
+		}
+		if p.Lat, err = parseCoordinate(lat); err != nil {
+			return Point{}, errs.Errorf("lat: %w", err)
+		}
+		if p.Lon, err = parseCoordinate(lon); err != nil {
+			return Point{}, errs.Errorf("lon: %w", err)
+		}
+	case string:
+		if !strings.Contains(v, ",") {
+			cell, err := DecodeGeohash(v)
+			if err != nil {
+				return Point{}, err
+			}
+			return cell.Center(), nil
+		}
+
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return Point{}, errs.Errorf("required \"lat,lon\" string, got %q", v)
+		}
+		if p.Lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+			return Point{}, errs.Errorf("cannot parse %q as latitude", parts[0])
+		}
+		if p.Lon, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+			return Point{}, errs.Errorf("cannot parse %q as longitude", parts[1])
+		}
+	default:
+		return Point{}, errs.Errorf("required object, \"lat,lon\" string or geohash, got %#v", value)
+	}
+
+	if err := p.Validate(); err != nil {
+		return Point{}, err
+	}
+
+	return p, nil
+}
+
+// ParsePoints parses a point or a list of points
+func ParsePoints(value interface{}) ([]Point, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		p, err := ParsePoint(value)
+		if err != nil {
+			return nil, err
+		}
+		return []Point{p}, nil
+	}
+
+	result := make([]Point, len(list))
+	for i, item := range list {
+		if _, ok := item.([]interface{}); ok {
+			return nil, errs.Errorf("nested lists are not allowed")
+		}
+		p, err := ParsePoint(item)
+		if err != nil {
+			return nil, errs.Errorf("%d: %w", i, err)
+		}
+		result[i] = p
+	}
+
+	return result, nil
+}
+
+func parseCoordinate(v interface{}) (float64, error) {
+	switch vv := v.(type) {
+	case json.Number:
+		return vv.Float64()
+	case string:
+		return 0, errs.Errorf("required number, got %q", vv)
+	}
+
+	return cast.ToFloat64E(v)
+}
+
+// Centroid returns the center of mass of the points on the sphere. False is returned if there are no points
+// or the center cannot be determined, e.g. for two antipodal points
+func Centroid(points []Point) (Point, bool) {
+	var x, y, z float64
+	for _, p := range points {
+		lat, lon := p.Lat*math.Pi/180, p.Lon*math.Pi/180
+		x += math.Cos(lat) * math.Cos(lon)
+		y += math.Cos(lat) * math.Sin(lon)
+		z += math.Sin(lat)
+	}
+
+	norm := math.Sqrt(x*x + y*y + z*z)
+	if len(points) == 0 || norm < 1e-12 {
+		return Point{}, false
+	}
+
+	return Point{
+		Lat: math.Asin(z/norm) * 180 / math.Pi,
+		Lon: math.Atan2(y, x) * 180 / math.Pi,
+	}, true
+}
@@ -0,0 +1,85 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParsePoint(t *testing.T) {
+	t.Run("must parse object", func(t *testing.T) {
+		p, err := ParsePoint(map[string]interface{}{"lat": json.Number("55.75"), "lon": json.Number("-37.5")})
+		require.NoError(t, err)
+		require.Equal(t, Point{Lat: 55.75, Lon: -37.5}, p)
+
+		p, err = ParsePoint(map[string]interface{}{"lat": 1.5, "lon": 2})
+		require.NoError(t, err)
+		require.Equal(t, Point{Lat: 1.5, Lon: 2}, p)
+	})
+
+	t.Run("must parse lat,lon string", func(t *testing.T) {
+		p, err := ParsePoint(" 55.75 , -37.5")
+		require.NoError(t, err)
+		require.Equal(t, Point{Lat: 55.75, Lon: -37.5}, p)
+	})
+
+	t.Run("must parse geohash to its cell center", func(t *testing.T) {
+		p, err := ParsePoint("s")
+		require.NoError(t, err)
+		require.Equal(t, Point{Lat: 22.5, Lon: 22.5}, p)
+	})
+
+	t.Run("must return error if point is invalid", func(t *testing.T) {
+		invalid := []interface{}{
+			nil,
+			1,
+			"",
+			"1,2,3",
+			"a,2",
+			"91,0",
+			"0,-181",
+			"ilo",
+			map[string]interface{}{"lat": 1},
+			map[string]interface{}{"lat": "1", "lon": 2},
+			map[string]interface{}{"lat": 1, "lon": 2, "z": 3},
+		}
+		for _, v := range invalid {
+			_, err := ParsePoint(v)
+			require.Error(t, err, "%#v", v)
+		}
+	})
+}
+
+func Test_ParsePoints(t *testing.T) {
+	points, err := ParsePoints("1,2")
+	require.NoError(t, err)
+	require.Equal(t, []Point{{Lat: 1, Lon: 2}}, points)
+
+	points, err = ParsePoints([]interface{}{"1,2", map[string]interface{}{"lat": 3, "lon": 4}})
+	require.NoError(t, err)
+	require.Equal(t, []Point{{Lat: 1, Lon: 2}, {Lat: 3, Lon: 4}}, points)
+
+	_, err = ParsePoints([]interface{}{"1,2", []interface{}{"3,4"}})
+	require.Error(t, err)
+	_, err = ParsePoints([]interface{}{"1,2", "3,"})
+	require.Error(t, err)
+}
+
+func Test_Centroid(t *testing.T) {
+	c, ok := Centroid([]Point{{Lat: 10, Lon: 20}})
+	require.True(t, ok)
+	require.InDelta(t, 10, c.Lat, 1e-9)
+	require.InDelta(t, 20, c.Lon, 1e-9)
+
+	c, ok = Centroid([]Point{{Lat: 0, Lon: 179}, {Lat: 0, Lon: -179}})
+	require.True(t, ok)
+	require.InDelta(t, 0, c.Lat, 1e-9)
+	require.InDelta(t, 180, math.Abs(c.Lon), 1e-9)
+
+	_, ok = Centroid(nil)
+	require.False(t, ok)
+	_, ok = Centroid([]Point{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 180}})
+	require.False(t, ok)
+}
@@ -0,0 +1,77 @@
+package geo
+
+import "github.com/cyradin/search/internal/errs"
+
+// Polygon is a closed polygon. The last point is connected to the first one. Polygons crossing the antimeridian are not supported
+type Polygon []Point
+
+func (p Polygon) Validate() error {
+	if len(p) < 3 {
+		return errs.Errorf("polygon must have at least 3 points")
+	}
+
+	return nil
+}
+
+func (p Polygon) Bounds() Rect {
+	r := Rect{MinLat: 90, MaxLat: -90, MinLon: 180, MaxLon: -180}
+	for _, v := range p {
+		if v.Lat < r.MinLat {
+			r.MinLat = v.Lat
+		}
+		if v.Lat > r.MaxLat {
+			r.MaxLat = v.Lat
+		}
+		if v.Lon < r.MinLon {
+			r.MinLon = v.Lon
+		}
+		if v.Lon > r.MaxLon {
+			r.MaxLon = v.Lon
+		}
+	}
+
+	return r
+}
+
+// Contains checks if the point is inside the polygon or on its edge (ray casting)
+func (p Polygon) Contains(point Point) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if onSegment(a, b, point) {
+			return true
+		}
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) {
+			lon := a.Lon + (point.Lat-a.Lat)*(b.Lon-a.Lon)/(b.Lat-a.Lat)
+			if point.Lon < lon {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+func onSegment(a Point, b Point, p Point) bool {
+	cross := (b.Lon-a.Lon)*(p.Lat-a.Lat) - (b.Lat-a.Lat)*(p.Lon-a.Lon)
+	if cross != 0 {
+		return false
+	}
+
+	return p.Lon >= minFloat(a.Lon, b.Lon) && p.Lon <= maxFloat(a.Lon, b.Lon) &&
+		p.Lat >= minFloat(a.Lat, b.Lat) && p.Lat <= maxFloat(a.Lat, b.Lat)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
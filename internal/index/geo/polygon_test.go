@@ -0,0 +1,31 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Polygon(t *testing.T) {
+	// L-shaped polygon
+	p := Polygon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 10},
+		{Lat: 5, Lon: 10},
+		{Lat: 5, Lon: 5},
+		{Lat: 10, Lon: 5},
+		{Lat: 10, Lon: 0},
+	}
+	require.NoError(t, p.Validate())
+	require.Error(t, p[:2].Validate())
+	require.Equal(t, Rect{MinLat: 0, MaxLat: 10, MinLon: 0, MaxLon: 10}, p.Bounds())
+
+	require.True(t, p.Contains(Point{Lat: 2, Lon: 2}))
+	require.True(t, p.Contains(Point{Lat: 2, Lon: 8}))
+	require.True(t, p.Contains(Point{Lat: 8, Lon: 2}))
+	require.True(t, p.Contains(Point{Lat: 0, Lon: 5}))
+	require.True(t, p.Contains(Point{Lat: 10, Lon: 0}))
+	require.False(t, p.Contains(Point{Lat: 8, Lon: 8}))
+	require.False(t, p.Contains(Point{Lat: -1, Lon: 5}))
+	require.False(t, p.Contains(Point{Lat: 5, Lon: 11}))
+}
@@ -0,0 +1,85 @@
+package geo
+
+import "math"
+
+// Rect is a bounding box. MinLon is greater than MaxLon if the box crosses the antimeridian
+type Rect struct {
+	MinLat float64 `json:"minLat"`
+	MaxLat float64 `json:"maxLat"`
+	MinLon float64 `json:"minLon"`
+	MaxLon float64 `json:"maxLon"`
+}
+
+// NewRect returns bounding box by its top left and bottom right corners
+func NewRect(topLeft Point, bottomRight Point) Rect {
+	return Rect{MinLat: bottomRight.Lat, MaxLat: topLeft.Lat, MinLon: topLeft.Lon, MaxLon: bottomRight.Lon}
+}
+
+// CircleRect returns bounding box of the circle. Radius is in meters
+func CircleRect(center Point, radius float64) Rect {
+	dLat := radius / EarthRadius * 180 / math.Pi
+	r := Rect{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	// the circle contains a pole
+	if r.MinLat == -90 || r.MaxLat == 90 {
+		return r
+	}
+
+	// longitude delta at the tangent latitude
+	sin := math.Sin(radius/EarthRadius) / math.Cos(center.Lat*math.Pi/180)
+	if sin >= 1 {
+		return r
+	}
+	dLon := math.Asin(sin) * 180 / math.Pi
+	r.MinLon = normalizeLon(center.Lon - dLon)
+	r.MaxLon = normalizeLon(center.Lon + dLon)
+
+	return r
+}
+
+func normalizeLon(lon float64) float64 {
+	if lon < -180 {
+		return lon + 360
+	}
+	if lon > 180 {
+		return lon - 360
+	}
+
+	return lon
+}
+
+func (r Rect) Center() Point {
+	c := Point{Lat: (r.MinLat + r.MaxLat) / 2, Lon: (r.MinLon + r.MaxLon) / 2}
+	if r.MinLon > r.MaxLon {
+		c.Lon = normalizeLon(c.Lon + 180)
+	}
+
+	return c
+}
+
+func (r Rect) Contains(p Point) bool {
+	if p.Lat < r.MinLat || p.Lat > r.MaxLat {
+		return false
+	}
+	if r.MinLon > r.MaxLon {
+		return p.Lon >= r.MinLon || p.Lon <= r.MaxLon
+	}
+
+	return p.Lon >= r.MinLon && p.Lon <= r.MaxLon
+}
+
+// Split splits the rect crossing the antimeridian into two
+func (r Rect) Split() []Rect {
+	if r.MinLon <= r.MaxLon {
+		return []Rect{r}
+	}
+
+	return []Rect{
+		{MinLat: r.MinLat, MaxLat: r.MaxLat, MinLon: r.MinLon, MaxLon: 180},
+		{MinLat: r.MinLat, MaxLat: r.MaxLat, MinLon: -180, MaxLon: r.MaxLon},
+	}
+}
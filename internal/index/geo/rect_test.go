@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Rect(t *testing.T) {
+	t.Run("must check if point is inside", func(t *testing.T) {
+		r := NewRect(Point{Lat: 10, Lon: -10}, Point{Lat: -10, Lon: 10})
+		require.True(t, r.Contains(Point{Lat: 0, Lon: 0}))
+		require.True(t, r.Contains(Point{Lat: 10, Lon: 10}))
+		require.False(t, r.Contains(Point{Lat: 11, Lon: 0}))
+		require.False(t, r.Contains(Point{Lat: 0, Lon: -11}))
+		require.Equal(t, []Rect{r}, r.Split())
+	})
+
+	t.Run("must handle rects crossing the antimeridian", func(t *testing.T) {
+		r := NewRect(Point{Lat: 10, Lon: 170}, Point{Lat: -10, Lon: -170})
+		require.True(t, r.Contains(Point{Lat: 0, Lon: 180}))
+		require.True(t, r.Contains(Point{Lat: 0, Lon: -175}))
+		require.False(t, r.Contains(Point{Lat: 0, Lon: 0}))
+		require.Equal(t, Point{Lat: 0, Lon: 180}, r.Center())
+		require.Equal(t, []Rect{
+			{MinLat: -10, MaxLat: 10, MinLon: 170, MaxLon: 180},
+			{MinLat: -10, MaxLat: 10, MinLon: -180, MaxLon: -170},
+		}, r.Split())
+	})
+}
+
+func Test_CircleRect(t *testing.T) {
+	t.Run("must contain the circle", func(t *testing.T) {
+		center := Point{Lat: 55.75, Lon: 37.61}
+		r := CircleRect(center, 10000)
+
+		require.True(t, r.Contains(center))
+		require.InDelta(t, 10000, Distance(center, Point{Lat: r.MaxLat, Lon: center.Lon}), 1)
+		require.InDelta(t, 10000, Distance(center, Point{Lat: r.MinLat, Lon: center.Lon}), 1)
+		require.Greater(t, Distance(center, Point{Lat: center.Lat, Lon: r.MaxLon}), 10000.0)
+	})
+
+	t.Run("must cross the antimeridian", func(t *testing.T) {
+		r := CircleRect(Point{Lat: 0, Lon: 179.99}, 10000)
+		require.Greater(t, r.MinLon, r.MaxLon)
+		require.True(t, r.Contains(Point{Lat: 0, Lon: -179.99}))
+	})
+
+	t.Run("must include all longitudes if circle contains a pole", func(t *testing.T) {
+		r := CircleRect(Point{Lat: 89.99, Lon: 0}, 10000)
+		require.Equal(t, Rect{MinLat: r.MinLat, MaxLat: 90, MinLon: -180, MaxLon: 180}, r)
+	})
+}
@@ -0,0 +1,241 @@
+package index
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/query"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/spf13/cast"
+)
+
+const (
+	defaultHighlightPreTag            = "<em>"
+	defaultHighlightPostTag           = "</em>"
+	defaultHighlightFragmentSize      = 100
+	defaultHighlightNumberOfFragments = 5
+)
+
+// Highlight highlight request. Query tokens found in text fields values are wrapped in tags
+type Highlight struct {
+	Fields  []string `json:"fields"`
+	PreTag  string   `json:"preTag"`
+	PostTag string   `json:"postTag"`
+	// FragmentSize approximate fragment size in characters. Whole values are highlighted if it is 0
+	FragmentSize *int `json:"fragmentSize"`
+	// NumberOfFragments max number of fragments per field
+	NumberOfFragments *int `json:"numberOfFragments"`
+}
+
+func (h Highlight) Validate() error {
+	return validation.ValidateStruct(&h,
+		validation.Field(&h.Fields, validation.Required, validation.Each(validation.Required)),
+		validation.Field(&h.FragmentSize, validation.Min(0)),
+		validation.Field(&h.NumberOfFragments, validation.Min(1)),
+	)
+}
+
+func (h Highlight) withDefaults() Highlight {
+	if h.PreTag == "" && h.PostTag == "" {
+		h.PreTag, h.PostTag = defaultHighlightPreTag, defaultHighlightPostTag
+	}
+	if h.FragmentSize == nil {
+		v := defaultHighlightFragmentSize
+		h.FragmentSize = &v
+	}
+	if h.NumberOfFragments == nil {
+		v := defaultHighlightNumberOfFragments
+		h.NumberOfFragments = &v
+	}
+
+	return h
+}
+
+// highlight adds highlighted fragments to the hits. Raw field values are re-analyzed to find query tokens offsets
+func highlight(s schema.Schema, opts schema.AnalyzerOpts, fields map[string]field.Field, h Highlight, tokens *query.Tokens, hits []SearchHit) error {
+	h = h.withDefaults()
+
+	for _, name := range h.Fields {
+		f, ok := schemaField(s, name)
+		if !ok || f.Type != schema.TypeText {
+			return validation.Errors{
+				"highlight": validation.NewError("validation_field_not_text", fmt.Sprintf("field %q is not a text field", name)),
+			}
+		}
+		fa, err := fieldAnalyzer(s, name)
+		if err != nil {
+			return err
+		}
+		analyze, err := fa.BuildTokenFunc(opts)
+		if err != nil {
+			return err
+		}
+
+		fieldTokens := tokens.Field(name)
+		if len(fieldTokens) == 0 {
+			continue
+		}
+		set := make(map[string]struct{}, len(fieldTokens))
+		for _, t := range fieldTokens {
+			set[t] = struct{}{}
+		}
+
+		for i := range hits {
+			var fragments []highlightFragment
+			for _, value := range fields[name].Data(hits[i].ID) {
+				spans := highlightSpans(analyze, cast.ToString(value), set)
+				fragments = append(fragments, highlightFragments(cast.ToString(value), spans, *h.FragmentSize)...)
+			}
+			if len(fragments) == 0 {
+				continue
+			}
+
+			if hits[i].Highlight == nil {
+				hits[i].Highlight = make(map[string][]string)
+			}
+			hits[i].Highlight[name] = renderFragments(fragments, *h.NumberOfFragments, h.PreTag, h.PostTag)
+		}
+	}
+
+	return nil
+}
+
+// highlightSpan character offsets of a matched token
+type highlightSpan struct {
+	start int
+	end   int
+	token string
+}
+
+// highlightSpans returns source text offsets of the matched tokens, so changed tokens (e.g. stems) highlight whole source words
+func highlightSpans(analyze schema.TokenFunc, value string, tokens map[string]struct{}) []highlightSpan {
+	var result []highlightSpan
+	for _, t := range analyze.Tokens(value) {
+		if t.EndOffset <= t.StartOffset {
+			continue
+		}
+		if _, ok := tokens[t.Value]; !ok {
+			continue
+		}
+		result = append(result, highlightSpan{start: t.StartOffset, end: t.EndOffset, token: t.Value})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].start < result[j].start })
+
+	return result
+}
+
+type highlightFragment struct {
+	text  []rune
+	start int
+	spans []highlightSpan
+}
+
+// score is the number of distinct matched tokens
+func (f highlightFragment) score() int {
+	tokens := make(map[string]struct{}, len(f.spans))
+	for _, s := range f.spans {
+		tokens[s.token] = struct{}{}
+	}
+
+	return len(tokens)
+}
+
+// highlightFragments splits value into fragments of approximately the given size on whitespaces.
+// Fragments are never split inside a matched token. Only fragments with matches are returned
+func highlightFragments(value string, spans []highlightSpan, size int) []highlightFragment {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	text := []rune(value)
+	if size == 0 {
+		return []highlightFragment{{text: text, start: 0, spans: spans}}
+	}
+
+	var result []highlightFragment
+	spanIdx := 0
+	for start := 0; start < len(text); {
+		for start < len(text) && unicode.IsSpace(text[start]) {
+			start++
+		}
+		if start >= len(text) {
+			break
+		}
+
+		end := start + size
+		if end > len(text) {
+			end = len(text)
+		}
+		for _, s := range spans {
+			if s.start < end && s.end > end {
+				end = s.end
+			}
+		}
+		for end < len(text) && !unicode.IsSpace(text[end]) {
+			end++
+		}
+
+		fragment := highlightFragment{text: text[start:end], start: start}
+		for ; spanIdx < len(spans) && spans[spanIdx].start < end; spanIdx++ {
+			fragment.spans = append(fragment.spans, spans[spanIdx])
+		}
+		if len(fragment.spans) > 0 {
+			result = append(result, fragment)
+		}
+
+		start = end
+	}
+
+	return result
+}
+
+// renderFragments wraps matched tokens of the best scoring fragments in tags. Fragments are ordered by score
+func renderFragments(fragments []highlightFragment, size int, preTag string, postTag string) []string {
+	sort.SliceStable(fragments, func(i, j int) bool {
+		si, sj := fragments[i].score(), fragments[j].score()
+		if si != sj {
+			return si > sj
+		}
+		return len(fragments[i].spans) > len(fragments[j].spans)
+	})
+	if len(fragments) > size {
+		fragments = fragments[:size]
+	}
+
+	result := make([]string, len(fragments))
+	for i, f := range fragments {
+		// overlapping tokens, e.g. ngrams, are merged
+		var intervals [][2]int
+		for _, s := range f.spans {
+			start, end := s.start-f.start, s.end-f.start
+			if end > len(f.text) {
+				end = len(f.text)
+			}
+			if last := len(intervals) - 1; last >= 0 && start < intervals[last][1] {
+				if end > intervals[last][1] {
+					intervals[last][1] = end
+				}
+				continue
+			}
+			intervals = append(intervals, [2]int{start, end})
+		}
+
+		var sb strings.Builder
+		pos := 0
+		for _, interval := range intervals {
+			sb.WriteString(string(f.text[pos:interval[0]]))
+			sb.WriteString(preTag)
+			sb.WriteString(string(f.text[interval[0]:interval[1]]))
+			sb.WriteString(postTag)
+			pos = interval[1]
+		}
+		sb.WriteString(string(f.text[pos:]))
+		result[i] = sb.String()
+	}
+
+	return result
+}
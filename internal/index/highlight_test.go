@@ -0,0 +1,153 @@
+package index
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Highlight_Validate(t *testing.T) {
+	size := -1
+	require.Error(t, validation.Validate(Highlight{}))
+	require.Error(t, validation.Validate(Highlight{Fields: []string{""}}))
+	require.Error(t, validation.Validate(Highlight{Fields: []string{"text"}, FragmentSize: &size}))
+	require.Error(t, validation.Validate(Highlight{Fields: []string{"text"}, NumberOfFragments: &size}))
+	require.NoError(t, validation.Validate(Highlight{Fields: []string{"text"}}))
+}
+
+func Test_highlightFragments(t *testing.T) {
+	spansOf := func(fa schema.FieldAnalyzer, text string, tokens ...string) []highlightSpan {
+		analyze, err := fa.BuildTokenFunc()
+		require.NoError(t, err)
+		set := make(map[string]struct{})
+		for _, t := range tokens {
+			set[t] = struct{}{}
+		}
+		return highlightSpans(analyze, text, set)
+	}
+	spans := func(text string, tokens ...string) []highlightSpan {
+		return spansOf(schema.DefaultTextAnalyzer(), text, tokens...)
+	}
+	stemmed := schema.FieldAnalyzer{Analyzers: []schema.Analyzer{
+		schema.NewAnalyzer(schema.TokenizerStandard, nil),
+		schema.NewAnalyzer(schema.FilterLowercase, nil),
+		schema.NewAnalyzer(schema.FilterStemmer, map[string]interface{}{"language": "english"}),
+	}}
+
+	t.Run("must highlight whole value if fragment size is 0", func(t *testing.T) {
+		text := "The quick brown Fox jumps over the lazy dog"
+		fragments := highlightFragments(text, spans(text, "fox", "dog"), 0)
+		require.Equal(t, []string{"The quick brown <em>Fox</em> jumps over the lazy <em>dog</em>"}, renderFragments(fragments, 5, "<em>", "</em>"))
+	})
+
+	t.Run("must split value into fragments on whitespaces", func(t *testing.T) {
+		text := "foo one two three four five bar six seven eight nine ten foo bar eleven"
+		fragments := highlightFragments(text, spans(text, "foo", "bar"), 20)
+		require.Equal(t, []string{
+			"nine ten <em>foo</em> <em>bar</em> eleven",
+			"<em>foo</em> one two three four",
+			"five <em>bar</em> six seven eight",
+		}, renderFragments(fragments, 5, "<em>", "</em>"))
+	})
+
+	t.Run("must limit number of fragments", func(t *testing.T) {
+		text := "foo one two three four five bar six seven eight nine ten foo bar eleven"
+		fragments := highlightFragments(text, spans(text, "foo", "bar"), 20)
+		require.Equal(t, []string{"nine ten <b>foo</b> <b>bar</b> eleven"}, renderFragments(fragments, 1, "<b>", "</b>"))
+	})
+
+	t.Run("must highlight whole words containing repeated substrings", func(t *testing.T) {
+		text := "scar car"
+		fragments := highlightFragments(text, spans(text, "car"), 0)
+		require.Equal(t, []string{"scar <em>car</em>"}, renderFragments(fragments, 1, "<em>", "</em>"))
+	})
+
+	t.Run("must highlight whole source words of stemmed tokens", func(t *testing.T) {
+		text := "Cats and a cat"
+		fragments := highlightFragments(text, spansOf(stemmed, text, "cat"), 0)
+		require.Equal(t, []string{"<em>Cats</em> and a <em>cat</em>"}, renderFragments(fragments, 1, "<em>", "</em>"))
+
+		text = "Connections connected"
+		fragments = highlightFragments(text, spansOf(stemmed, text, "connect"), 0)
+		require.Equal(t, []string{"<em>Connections</em> <em>connected</em>"}, renderFragments(fragments, 1, "<em>", "</em>"))
+	})
+
+	t.Run("must highlight source text of char filtered values", func(t *testing.T) {
+		fa := schema.DefaultTextAnalyzer()
+		fa.CharFilters = []schema.CharFilter{schema.NewCharFilter(schema.CharFilterHTMLStrip, nil)}
+		text := "<b>caf&eacute;</b> au lait"
+		fragments := highlightFragments(text, spansOf(fa, text, "café"), 0)
+		require.Equal(t, []string{"<b><em>caf&eacute;</em></b> au lait"}, renderFragments(fragments, 1, "<em>", "</em>"))
+	})
+
+	t.Run("must merge overlapping tokens", func(t *testing.T) {
+		fragments := highlightFragments("foobar", []highlightSpan{
+			{start: 0, end: 4, token: "foob"},
+			{start: 2, end: 6, token: "obar"},
+		}, 0)
+		require.Equal(t, []string{"<em>foobar</em>"}, renderFragments(fragments, 1, "<em>", "</em>"))
+	})
+
+	t.Run("must return nothing if there are no matches", func(t *testing.T) {
+		require.Empty(t, highlightFragments("foo bar", nil, 10))
+	})
+}
+
+func Test_Documents_Search_Highlight(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"text":    schema.NewField(schema.TypeText, false, ""),
+				"keyword": schema.NewField(schema.TypeKeyword, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	_, err := docs.Add(i, "", DocSource{"text": "Hello, World! Hello again.", "keyword": "hello"})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"text": "Goodbye", "keyword": "goodbye"})
+	require.NoError(t, err)
+
+	t.Run("must highlight matched tokens", func(t *testing.T) {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{
+			"query": {
+				"type": "bool",
+				"should": [
+					{"type": "match", "field": "text", "query": "hello"},
+					{"type": "term", "field": "keyword", "query": "goodbye"}
+				]
+			},
+			"highlight": {"fields": ["text"], "preTag": "[", "postTag": "]"}
+		}`), &q))
+
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		require.Len(t, result.Hits.Hits, 2)
+
+		highlights := make(map[uint32]map[string][]string)
+		for _, hit := range result.Hits.Hits {
+			highlights[hit.ID] = hit.Highlight
+		}
+		require.Equal(t, map[string][]string{"text": {"[Hello], World! [Hello] again."}}, highlights[1])
+		require.Nil(t, highlights[2])
+	})
+
+	t.Run("must return error if field is not a text field", func(t *testing.T) {
+		_, err := docs.Search(ctx, i, Search{
+			Query:     []byte(`{"type": "term", "field": "keyword", "query": "hello"}`),
+			Highlight: &Highlight{Fields: []string{"keyword"}},
+		})
+		require.Error(t, err)
+	})
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/cyradin/search/internal/errs"
 	"github.com/cyradin/search/internal/index/schema"
-	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 var ErrIndexNotFound = fmt.Errorf("index not found")
@@ -105,7 +104,7 @@ func (r *Repository) Add(ctx context.Context, index Index) error {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	if err := validation.Validate(index.Schema); err != nil {
+	if err := index.Schema.ValidateWithOpts(schema.AnalyzerOpts{DataDir: r.dataDir}); err != nil {
 		return errs.Errorf("schema validation failed: %w", err)
 	}
 
@@ -139,3 +138,26 @@ func (r *Repository) Delete(ctx context.Context, name string) error {
 
 	return nil
 }
+
+// ReloadAnalyzers rebuilds index analyzers, e.g. to apply changed synonym files
+func (r *Repository) ReloadAnalyzers(ctx context.Context, name string) error {
+	index, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.docs.ReloadAnalyzers(index)
+}
+
+// Analyze returns tokens produced by each stage of the index analyzer chain
+func (r *Repository) Analyze(ctx context.Context, name string, req Analyze) (AnalyzeResult, error) {
+	index, err := r.Get(name)
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+
+	return r.docs.Analyze(index, req)
+}
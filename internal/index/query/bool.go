@@ -3,26 +3,49 @@ package query
 import (
 	"bytes"
 	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
 	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/cast"
 )
 
 var _ Query = (*BoolQuery)(nil)
 
+// BoolQuery combines queries:
+//   - must: documents must match all the queries, scores are summed
+//   - filter: documents must match all the queries, scores are ignored
+//   - must_not: documents must not match any of the queries, scores are ignored
+//   - should: documents must match at least minimumShouldMatch queries, scores are summed.
+//     minimumShouldMatch is 0 by default if must or filter queries are provided, so should queries only affect scores.
+//     Otherwise at least one should query must match even if minimumShouldMatch is 0.
+//
+// If only must_not queries are provided, they are excluded from all documents.
 type BoolQuery struct {
-	Must     []Query `json:"must"`
-	Should   []Query `json:"should"`
-	Filter   []Query `json:"filter"`
-	Parallel bool    `json:"parallel"`
+	Must               []Query     `json:"must"`
+	Should             []Query     `json:"should"`
+	Filter             []Query     `json:"filter"`
+	MustNot            []Query     `json:"mustNot"`
+	MinimumShouldMatch interface{} `json:"minimumShouldMatch"`
+	Parallel           bool        `json:"parallel"`
+	Boost              *float64    `json:"boost"`
 }
 
 func (q *BoolQuery) UnmarshalJSON(data []byte) error {
 	d := struct {
-		Must     []jsoniter.RawMessage `json:"must"`
-		Should   []jsoniter.RawMessage `json:"should"`
-		Filter   []jsoniter.RawMessage `json:"filter"`
-		Parallel bool                  `json:"parallel"`
+		Must               []jsoniter.RawMessage `json:"must"`
+		Should             []jsoniter.RawMessage `json:"should"`
+		Filter             []jsoniter.RawMessage `json:"filter"`
+		MustNot            []jsoniter.RawMessage `json:"mustNot"`
+		MinimumShouldMatch interface{}           `json:"minimumShouldMatch"`
+		Parallel           bool                  `json:"parallel"`
+		Boost              *float64              `json:"boost"`
 	}{}
 
 	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
@@ -31,185 +54,315 @@ func (q *BoolQuery) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
+	q.MinimumShouldMatch = d.MinimumShouldMatch
 	q.Parallel = d.Parallel
+	q.Boost = d.Boost
 
-	q.Must = make([]Query, len(d.Must))
-	for i, r := range d.Must {
-		v, err := Build(QueryRequest(r))
-		if err != nil {
-			return err
-		}
-		q.Must[i] = v
+	q.Must, err = buildQueries(d.Must)
+	if err != nil {
+		return err
 	}
 
-	q.Should = make([]Query, len(d.Should))
-	for i, r := range d.Should {
-		v, err := Build(QueryRequest(r))
-		if err != nil {
-			return err
-		}
-		q.Should[i] = v
+	q.Should, err = buildQueries(d.Should)
+	if err != nil {
+		return err
 	}
 
-	q.Filter = make([]Query, len(d.Filter))
-	for i, r := range d.Filter {
+	q.Filter, err = buildQueries(d.Filter)
+	if err != nil {
+		return err
+	}
+
+	q.MustNot, err = buildQueries(d.MustNot)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func buildQueries(data []jsoniter.RawMessage) ([]Query, error) {
+	result := make([]Query, len(data))
+	for i, r := range data {
 		v, err := Build(QueryRequest(r))
 		if err != nil {
-			return err
+			return nil, err
 		}
-		q.Filter[i] = v
+		result[i] = v
 	}
 
-	return nil
+	return result, nil
 }
 
 func (q *BoolQuery) Validate() error {
-	return nil
+	return validation.ValidateStruct(q,
+		validation.Field(&q.MinimumShouldMatch, validation.By(func(value interface{}) error {
+			_, err := minimumShouldMatch(value, len(q.Should))
+			return err
+		})),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
 }
 
 func (q *BoolQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
-	if len(q.Should) == 0 && len(q.Must) == 0 && len(q.Filter) == 0 {
-		if ff, ok := fields[field.AllField]; ok {
-			return NewResult(ff.TermQuery(ctx, true)), nil
-		}
+	result, err := q.exec(ctx, fields)
+	if err != nil {
+		return result, err
+	}
+
+	return withBoost(withDescription(result, "bool"), q.Boost), nil
+}
 
-		return NewEmptyResult(), nil
+type boolClauses struct {
+	must    []Result
+	should  []Result
+	filter  []Result
+	mustNot []Result
+}
+
+func (q *BoolQuery) exec(ctx context.Context, fields Fields) (Result, error) {
+	if len(q.Should) == 0 && len(q.Must) == 0 && len(q.Filter) == 0 && len(q.MustNot) == 0 {
+		return allResult(ctx, fields), nil
 	}
 
-	var (
-		shouldResult, filterResult, mustResult Result
-		err                                    error
-	)
+	minShould, err := minimumShouldMatch(q.MinimumShouldMatch, len(q.Should))
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+	if minShould == 0 && len(q.Must) == 0 && len(q.Filter) == 0 && len(q.Should) > 0 {
+		minShould = 1
+	}
 
+	var clauses boolClauses
 	if q.Parallel {
-		shouldResult, filterResult, mustResult, err = q.runParallel(ctx, fields)
+		clauses, err = q.runParallel(ctx, fields)
 	} else {
-		shouldResult, filterResult, mustResult, err = q.runSync(ctx, fields)
+		clauses, err = q.runSync(ctx, fields)
 	}
 	if err != nil {
 		return NewEmptyResult(), err
 	}
 
-	var result Result
-	if !shouldResult.IsEmpty() {
-		result = shouldResult
+	var (
+		docs    *roaring.Bitmap
+		scorers []scorer
+	)
+	for _, r := range clauses.must {
+		docs = and(docs, r.Docs())
+		scorers = append(scorers, r.results...)
 	}
-	if !mustResult.IsEmpty() {
-		if result.IsEmpty() {
-			result = mustResult
-		} else {
-			result.And(mustResult)
-		}
+	for _, r := range clauses.filter {
+		docs = and(docs, r.Docs())
+	}
+	for _, r := range clauses.should {
+		scorers = append(scorers, r.results...)
+	}
+	if len(clauses.should) > 0 && minShould > 0 {
+		docs = and(docs, matchAtLeast(clauses.should, minShould))
 	}
-	if !filterResult.IsEmpty() {
-		if result.IsEmpty() {
-			result = filterResult
-		} else {
-			result.And(filterResult)
+
+	if docs == nil {
+		all := allResult(ctx, fields)
+		docs = all.Docs()
+		scorers = append(scorers, all.results...)
+	}
+
+	if len(clauses.mustNot) > 0 {
+		excluded := make([]*roaring.Bitmap, len(clauses.mustNot))
+		for i, r := range clauses.mustNot {
+			excluded[i] = r.Docs()
 		}
+		docs = roaring.AndNot(docs, roaring.FastOr(excluded...))
 	}
 
-	if result.IsEmpty() {
-		return NewEmptyResult(), nil
+	// clause scorers also match documents excluded by other clauses
+	for i, sc := range scorers {
+		scorers[i] = &docsScorer{docs: docs, scorer: sc}
+	}
+	// filter and must_not clauses do not score documents, but the result must not be treated as empty
+	if len(scorers) == 0 {
+		scorers = append(scorers, &constantScorer{docs: docs, score: 0})
 	}
 
-	return result, nil
+	return Result{
+		docs:    docs,
+		results: scorers,
+	}, nil
 }
 
-func (q *BoolQuery) runParallel(ctx context.Context, fields Fields) (shouldResult Result, filterResult Result, mustResult Result, err error) {
-	errors := make(chan error)
-
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+// docsScorer scores only the documents from docs
+type docsScorer struct {
+	docs   *roaring.Bitmap
+	scorer scorer
+}
 
-	chShould := q.runAsyncQueries(ctx, fields, q.Should, errors)
-	chMust := q.runAsyncQueries(ctx, fields, q.Must, errors)
-	chFilter := q.runAsyncQueries(ctx, fields, q.Filter, errors)
-
-	for i := 0; i < len(q.Should)+len(q.Must)+len(q.Filter); i++ {
-		select {
-		case r := <-chShould:
-			if shouldResult.IsEmpty() {
-				shouldResult = r
-			} else {
-				shouldResult.Or(r)
-			}
-		case r := <-chMust:
-			if mustResult.IsEmpty() {
-				mustResult = r
-			} else {
-				mustResult.And(r)
-			}
-		case r := <-chFilter:
-			if filterResult.IsEmpty() {
-				filterResult = r
-			} else {
-				filterResult.And(r)
-			}
-		case e := <-errors:
-			err = e
-			return
-		}
+func (s *docsScorer) Score(id uint32) float64 {
+	if !s.docs.Contains(id) {
+		return 0
 	}
 
-	return shouldResult, filterResult, mustResult, nil
+	return s.scorer.Score(id)
+}
+
+func (s *docsScorer) Explain(id uint32) field.Explanation {
+	if !s.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
 
+	return s.scorer.Explain(id)
 }
 
-func (q *BoolQuery) runAsyncQueries(ctx context.Context, fields Fields, queries []Query, errors chan<- error) <-chan Result {
-	ch := make(chan Result)
+func (q *BoolQuery) runParallel(ctx context.Context, fields Fields) (boolClauses, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clauses := boolClauses{
+		must:    make([]Result, len(q.Must)),
+		should:  make([]Result, len(q.Should)),
+		filter:  make([]Result, len(q.Filter)),
+		mustNot: make([]Result, len(q.MustNot)),
+	}
 
-	go func() {
-		for _, query := range queries {
-			go func(ctx context.Context, query Query) {
-				res, err := query.Exec(ctx, fields)
-				if err != nil {
-					errors <- err
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		err  error
+	)
+	run := func(ctx context.Context, queries []Query, dst []Result) {
+		for i, query := range queries {
+			wg.Add(1)
+			go func(i int, query Query) {
+				defer wg.Done()
+				res, e := Exec(ctx, query, fields)
+				if e != nil {
+					once.Do(func() {
+						err = e
+						cancel()
+					})
+					return
 				}
-				ch <- res
-			}(ctx, query)
+				dst[i] = res
+			}(i, query)
 		}
-	}()
+	}
+
+	run(ctx, q.Must, clauses.must)
+	run(ctx, q.Should, clauses.should)
+	run(field.DisableScoring(ctx), q.Filter, clauses.filter)
+	run(field.DisableScoring(ctx), q.MustNot, clauses.mustNot)
+	wg.Wait()
 
-	return ch
+	return clauses, err
 }
 
-func (q *BoolQuery) runSync(ctx context.Context, fields Fields) (shouldResult Result, filterResult Result, mustResult Result, err error) {
-	shouldResult, err = q.runSyncQueries(ctx, fields, q.Should, func(src Result, dst Result) { src.Or(dst) })
+func (q *BoolQuery) runSync(ctx context.Context, fields Fields) (clauses boolClauses, err error) {
+	clauses.must, err = q.runSyncQueries(ctx, fields, q.Must)
 	if err != nil {
 		return
 	}
 
-	filterResult, err = q.runSyncQueries(ctx, fields, q.Filter, func(src Result, dst Result) { src.And(dst) })
+	clauses.should, err = q.runSyncQueries(ctx, fields, q.Should)
 	if err != nil {
 		return
 	}
 
-	mustResult, err = q.runSyncQueries(ctx, fields, q.Must, func(src Result, dst Result) { src.And(dst) })
+	clauses.filter, err = q.runSyncQueries(field.DisableScoring(ctx), fields, q.Filter)
+	if err != nil {
+		return
+	}
+
+	clauses.mustNot, err = q.runSyncQueries(field.DisableScoring(ctx), fields, q.MustNot)
 	return
 }
 
-func (q *BoolQuery) runSyncQueries(
-	ctx context.Context,
-	fields Fields,
-	queries []Query,
-	apply func(src Result, dst Result),
-) (Result, error) {
-	var result Result
-
-	for _, query := range queries {
-		r, err := query.Exec(ctx, fields)
+func (q *BoolQuery) runSyncQueries(ctx context.Context, fields Fields, queries []Query) ([]Result, error) {
+	result := make([]Result, len(queries))
+	for i, query := range queries {
+		r, err := Exec(ctx, query, fields)
 		if err != nil {
-			return NewEmptyResult(), err
+			return nil, err
+		}
+		result[i] = r
+	}
+
+	return result, nil
+}
+
+// allResult returns all documents of the index
+func allResult(ctx context.Context, fields Fields) Result {
+	if ff, ok := fields[field.AllField]; ok {
+		return NewResult(ff.TermQuery(ctx, true))
+	}
+
+	return NewEmptyResult()
+}
+
+// and intersects bitmaps without modifying them. Nil dst is treated as "all documents"
+func and(dst *roaring.Bitmap, src *roaring.Bitmap) *roaring.Bitmap {
+	if dst == nil {
+		return src.Clone()
+	}
+
+	return roaring.And(dst, src)
+}
+
+// matchAtLeast returns documents matching at least n of the results
+func matchAtLeast(results []Result, n int) *roaring.Bitmap {
+	if n > len(results) {
+		return roaring.New()
+	}
+
+	// levels[i] contains documents matching at least i+1 results
+	levels := make([]*roaring.Bitmap, n)
+	for i := range levels {
+		levels[i] = roaring.New()
+	}
+	for _, r := range results {
+		for i := n - 1; i > 0; i-- {
+			levels[i].Or(roaring.And(levels[i-1], r.Docs()))
+		}
+		levels[0].Or(r.Docs())
+	}
+
+	return levels[n-1]
+}
+
+// minimumShouldMatch calculates min number of should queries to match.
+// Supported values are integers (2), negative integers (-1 means all but one), percentages ("75%") and negative percentages ("-25%").
+func minimumShouldMatch(value interface{}, cnt int) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+
+	str, err := cast.ToStringE(value)
+	if err != nil {
+		return 0, errs.Errorf("invalid minimumShouldMatch value %v", value)
+	}
+	str = strings.TrimSpace(str)
+
+	if strings.HasSuffix(str, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(str, "%"), 64)
+		if err != nil || v < -100 || v > 100 {
+			return 0, errs.Errorf("invalid minimumShouldMatch percentage %q", str)
 		}
 
-		if result.IsEmpty() {
-			result = r
-			continue
+		result := int(math.Floor(float64(cnt) * math.Abs(v) / 100))
+		if v < 0 {
+			result = cnt - result
 		}
+		return result, nil
+	}
 
-		apply(result, r)
+	v, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, errs.Errorf("invalid minimumShouldMatch value %q", str)
+	}
+	if v < 0 {
+		v = cnt + v
+	}
+	if v < 0 {
+		v = 0
 	}
 
-	return result, nil
+	return v, nil
 }
@@ -167,6 +167,47 @@ func Test_BoolQuery_Validate(t *testing.T) {
 		err := validation.Validate(query)
 		require.NoError(t, err)
 	})
+	t.Run("must return error if minimumShouldMatch is invalid", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"minimumShouldMatch": "foo"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if minimumShouldMatch percentage is out of range", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"minimumShouldMatch": "150%"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+}
+
+func Test_minimumShouldMatch(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected int
+	}{
+		{value: nil, expected: 0},
+		{value: 2, expected: 2},
+		{value: -1, expected: 3},
+		{value: -5, expected: 0},
+		{value: "75%", expected: 3},
+		{value: "-25%", expected: 3},
+		{value: "50%", expected: 2},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v", c.value), func(t *testing.T) {
+			v, err := minimumShouldMatch(c.value, 4)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, v)
+		})
+	}
 }
 
 func Test_BoolQuery_Exec(t *testing.T) {
@@ -368,4 +409,257 @@ func Test_BoolQuery_Exec(t *testing.T) {
 		require.NotNil(t, result)
 		require.ElementsMatch(t, []uint32{}, result.Docs().ToArray())
 	})
+	t.Run("must not score documents by should queries they do not match", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "constant_score",
+					"filter": {"type": "term", "field": "field", "query": true},
+					"boost": 5
+				},
+				{
+					"type": "constant_score",
+					"filter": {"type": "term", "field": "field", "query": false},
+					"boost": 2
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 5.0, result.Score(1))
+		require.Equal(t, 2.0, result.Score(2))
+	})
+	t.Run("must apply boost", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"must": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			],
+			"boost": 3
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+		require.Equal(t, 3.0, result.Score(1))
+	})
+	t.Run("must exclude must_not documents", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			],
+			"mustNot": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+	})
+	t.Run("must exclude must_not documents from all documents if no other clauses provided", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"mustNot": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{2, 3}, result.Docs().ToArray())
+	})
+	t.Run("should must only affect scores if must query provided", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"must": [
+				{
+					"type": "bool"
+				}
+			],
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2, 3}, result.Docs().ToArray())
+		require.Greater(t, result.Score(1), result.Score(3))
+	})
+	t.Run("filter must not affect scores", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"filter": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			],
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+		require.Equal(t, 0.0, result.Score(1))
+	})
+	t.Run("must apply minimumShouldMatch", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			],
+			"minimumShouldMatch": 2
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+	})
+	t.Run("must apply minimumShouldMatch percentage", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			],
+			"minimumShouldMatch": "100%"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{}, result.Docs().ToArray())
+	})
+	t.Run("must require one should query to match if minimumShouldMatch is 0 and there are no must or filter queries", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				}
+			],
+			"minimumShouldMatch": 0
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+	})
+	t.Run("must not score documents excluded from nested bool query", func(t *testing.T) {
+		fields := Fields{"field": f1, field.AllField: f2}
+		term := `{"type": "term", "field": "field", "query": true}`
+
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "bool",
+					"should": [`+term+`],
+					"mustNot": [`+term+`]
+				},
+				`+term+`
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+
+		termQuery := new(TermQuery)
+		mustUnmarshal(t, term, termQuery)
+		expected, err := termQuery.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.Equal(t, expected.Score(1), result.Score(1))
+		require.Equal(t, result.Score(1), result.Explain(1).Value)
+	})
+	t.Run("must return the same result in parallel mode", func(t *testing.T) {
+		query := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": true
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			],
+			"mustNot": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": false
+				}
+			],
+			"parallel": true
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f1, field.AllField: f2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+	})
 }
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var _ Query = (*BoostingQuery)(nil)
+
+// BoostingQuery matches documents by the positive query.
+// Scores of documents also matching the negative query are multiplied by negativeBoost
+type BoostingQuery struct {
+	Positive      Query    `json:"positive"`
+	Negative      Query    `json:"negative"`
+	NegativeBoost *float64 `json:"negativeBoost"`
+	Boost         *float64 `json:"boost"`
+}
+
+func (q *BoostingQuery) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Positive      jsoniter.RawMessage `json:"positive"`
+		Negative      jsoniter.RawMessage `json:"negative"`
+		NegativeBoost *float64            `json:"negativeBoost"`
+		Boost         *float64            `json:"boost"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+	q.NegativeBoost = d.NegativeBoost
+	q.Boost = d.Boost
+
+	if len(d.Positive) != 0 {
+		q.Positive, err = Build(QueryRequest(d.Positive))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(d.Negative) != 0 {
+		q.Negative, err = Build(QueryRequest(d.Negative))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *BoostingQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Positive, validation.Required, validation.NotNil),
+		validation.Field(&q.Negative, validation.Required, validation.NotNil),
+		validation.Field(&q.NegativeBoost, validation.NotNil, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *BoostingQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	positive, err := Exec(ctx, q.Positive, fields)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+	if positive.IsEmpty() {
+		return positive, nil
+	}
+
+	negative, err := Exec(field.DisableScoring(ctx), q.Negative, fields)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	result := Result{
+		docs: positive.Docs(),
+		results: []scorer{&boostingScorer{
+			positive:      positive,
+			negative:      negative.Docs(),
+			negativeBoost: *q.NegativeBoost,
+		}},
+	}
+
+	return withBoost(result, q.Boost), nil
+}
+
+type boostingScorer struct {
+	positive      Result
+	negative      *roaring.Bitmap
+	negativeBoost float64
+}
+
+func (s *boostingScorer) Score(id uint32) float64 {
+	score := s.positive.Score(id)
+	if s.negative.Contains(id) {
+		score *= s.negativeBoost
+	}
+
+	return score
+}
+
+func (s *boostingScorer) Explain(id uint32) field.Explanation {
+	positive := s.positive.Explain(id)
+	if !s.negative.Contains(id) {
+		return positive
+	}
+
+	return field.NewExplanation(
+		s.Score(id),
+		"product of positive query score and negative boost:",
+		positive,
+		field.NewExplanation(s.negativeBoost, "negative boost"),
+	)
+}
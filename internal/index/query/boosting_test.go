@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BoostingQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(BoostingQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if negative boost is not provided", func(t *testing.T) {
+		query := new(BoostingQuery)
+		mustUnmarshal(t, `{
+			"positive": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negative": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			}
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if negative boost is out of range", func(t *testing.T) {
+		query := new(BoostingQuery)
+		mustUnmarshal(t, `{
+			"positive": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negative": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negativeBoost": 2
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(BoostingQuery)
+		mustUnmarshal(t, `{
+			"positive": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negative": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negativeBoost": 0.5
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_BoostingQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "foo")
+	f.Add(2, "foo")
+	f.Add(2, "bar")
+	f.Add(3, "bar")
+
+	query := new(BoostingQuery)
+	mustUnmarshal(t, `{
+		"positive": {
+			"type": "term",
+			"field": "field",
+			"query": "foo",
+			"boost": 2
+		},
+		"negative": {
+			"type": "term",
+			"field": "field",
+			"query": "bar"
+		},
+		"negativeBoost": 0.25
+	}`, query)
+
+	result, err := query.Exec(context.Background(), Fields{"field": f})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	require.Equal(t, 2.0, result.Score(1))
+	require.Equal(t, 0.5, result.Score(2))
+	require.Equal(t, 0.0, result.Score(3))
+
+	query = new(BoostingQuery)
+	mustUnmarshal(t, `{
+		"positive": {
+			"type": "bool",
+			"filter": [{"type": "term", "field": "field", "query": "foo"}]
+		},
+		"negative": {
+			"type": "term",
+			"field": "field",
+			"query": "bar"
+		},
+		"negativeBoost": 0.25
+	}`, query)
+
+	result, err = query.Exec(context.Background(), Fields{"field": f})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	require.Equal(t, 0.0, result.Score(1))
+	require.Equal(t, 0.0, result.Score(2))
+	require.Equal(t, "product of positive query score and negative boost:", result.Explain(2).Description)
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var _ Query = (*ConstantScoreQuery)(nil)
+
+// ConstantScoreQuery matches documents by the filter and scores all of them equally to boost
+type ConstantScoreQuery struct {
+	Filter Query    `json:"filter"`
+	Boost  *float64 `json:"boost"`
+}
+
+func (q *ConstantScoreQuery) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Filter jsoniter.RawMessage `json:"filter"`
+		Boost  *float64            `json:"boost"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+	q.Boost = d.Boost
+
+	if len(d.Filter) != 0 {
+		q.Filter, err = Build(QueryRequest(d.Filter))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *ConstantScoreQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Filter, validation.Required, validation.NotNil),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *ConstantScoreQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	res, err := Exec(field.DisableScoring(ctx), q.Filter, fields)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	score := 1.0
+	if q.Boost != nil {
+		score = *q.Boost
+	}
+
+	return newConstantResult(res.Docs(), score), nil
+}
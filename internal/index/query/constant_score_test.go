@@ -0,0 +1,90 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConstantScoreQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(ConstantScoreQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if boost is negative", func(t *testing.T) {
+		query := new(ConstantScoreQuery)
+		mustUnmarshal(t, `{
+			"filter": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"boost": -1
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(ConstantScoreQuery)
+		mustUnmarshal(t, `{
+			"filter": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"boost": 1.5
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_ConstantScoreQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "value")
+	f.Add(2, "value")
+
+	t.Run("must score documents with boost", func(t *testing.T) {
+		query := new(ConstantScoreQuery)
+		mustUnmarshal(t, `{
+			"filter": {
+				"type": "term",
+				"field": "field",
+				"query": "value",
+				"boost": 10
+			},
+			"boost": 1.5
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 1.5, result.Score(1))
+		require.Equal(t, 1.5, result.Score(2))
+		require.Equal(t, 0.0, result.Score(3))
+	})
+	t.Run("must score documents with 1 if boost not provided", func(t *testing.T) {
+		query := new(ConstantScoreQuery)
+		mustUnmarshal(t, `{
+			"filter": {
+				"type": "term",
+				"field": "field",
+				"query": "value"
+			}
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.Equal(t, 1.0, result.Score(1))
+	})
+}
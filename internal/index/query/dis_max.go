@@ -0,0 +1,68 @@
+package query
+
+import (
+	"bytes"
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var _ Query = (*DisMaxQuery)(nil)
+
+// DisMaxQuery matches documents by any of the queries.
+// Documents are scored by the best matching query plus tieBreaker * scores of the others
+type DisMaxQuery struct {
+	Queries    []Query  `json:"queries"`
+	TieBreaker float64  `json:"tieBreaker"`
+	Boost      *float64 `json:"boost"`
+}
+
+func (q *DisMaxQuery) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Queries    []jsoniter.RawMessage `json:"queries"`
+		TieBreaker float64               `json:"tieBreaker"`
+		Boost      *float64              `json:"boost"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+	q.TieBreaker = d.TieBreaker
+	q.Boost = d.Boost
+
+	q.Queries = make([]Query, len(d.Queries))
+	for i, r := range d.Queries {
+		v, err := Build(QueryRequest(r))
+		if err != nil {
+			return err
+		}
+		q.Queries[i] = v
+	}
+
+	return nil
+}
+
+func (q *DisMaxQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Queries, validation.Required),
+		validation.Field(&q.TieBreaker, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *DisMaxQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	results := make([]Result, len(q.Queries))
+	for i, query := range q.Queries {
+		r, err := Exec(ctx, query, fields)
+		if err != nil {
+			return NewEmptyResult(), err
+		}
+		results[i] = r
+	}
+
+	return withBoost(newDisMaxResult(results, q.TieBreaker), q.Boost), nil
+}
@@ -0,0 +1,141 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DisMaxQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if tie breaker is out of range", func(t *testing.T) {
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{
+			"queries": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": "query"
+				}
+			],
+			"tieBreaker": 1.1
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{
+			"queries": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": "query"
+				}
+			],
+			"tieBreaker": 0.5
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_DisMaxQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "foo")
+	f.Add(1, "bar")
+	f.Add(2, "bar")
+
+	t.Run("must score documents by the best query", func(t *testing.T) {
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{
+			"queries": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": "foo",
+					"boost": 3
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": "bar",
+					"boost": 2
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 3.0, result.Score(1))
+		require.Equal(t, 2.0, result.Score(2))
+	})
+	t.Run("must add tie breaker multiplied scores of other queries", func(t *testing.T) {
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{
+			"queries": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": "foo",
+					"boost": 3
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": "bar",
+					"boost": 2
+				}
+			],
+			"tieBreaker": 0.5,
+			"boost": 2
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.Equal(t, 8.0, result.Score(1))
+		require.Equal(t, 4.0, result.Score(2))
+	})
+	t.Run("must match documents of bool queries with filter clauses only", func(t *testing.T) {
+		f, err := field.New(schema.TypeKeyword)
+		require.NoError(t, err)
+		f.Add(1, "foo")
+		f.Add(2, "bar")
+
+		query := new(DisMaxQuery)
+		mustUnmarshal(t, `{
+			"queries": [
+				{
+					"type": "bool",
+					"filter": [{"type": "term", "field": "field", "query": "foo"}]
+				},
+				{
+					"type": "term",
+					"field": "field",
+					"query": "bar",
+					"boost": 2
+				}
+			]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 0.0, result.Score(1))
+		require.Equal(t, 2.0, result.Score(2))
+	})
+}
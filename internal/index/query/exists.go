@@ -0,0 +1,34 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Query = (*ExistsQuery)(nil)
+
+// ExistsQuery matches documents having any value of the field
+type ExistsQuery struct {
+	Field string   `json:"field"`
+	Boost *float64 `json:"boost"`
+}
+
+func (q *ExistsQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *ExistsQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	field, ok := fields[q.Field]
+	if !ok {
+		return NewEmptyResult(), nil
+	}
+
+	result := NewResult(field.ExistsQuery(ctx).WithOpts(ctx, boostOpts(q.Boost)...))
+
+	return withDescription(result, fmt.Sprintf("exists %s", q.Field)), nil
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExistsQuery_Validate(t *testing.T) {
+	t.Run("must return error if field is not provided", func(t *testing.T) {
+		query := new(ExistsQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(ExistsQuery)
+		mustUnmarshal(t, `{
+			"field": "field"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_ExistsQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "foo")
+	f.Add(3, "bar")
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		query := new(ExistsQuery)
+		mustUnmarshal(t, `{"field": "field1"}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+	t.Run("must return documents having field values", func(t *testing.T) {
+		query := new(ExistsQuery)
+		mustUnmarshal(t, `{"field": "field", "boost": 2}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 3}, result.Docs().ToArray())
+		require.Equal(t, 2.0, result.Score(1))
+	})
+}
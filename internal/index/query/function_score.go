@@ -0,0 +1,648 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/cast"
+)
+
+var _ Query = (*FunctionScoreQuery)(nil)
+
+type ScoreMode string
+
+const (
+	ScoreModeMultiply ScoreMode = "multiply"
+	ScoreModeSum      ScoreMode = "sum"
+	ScoreModeAvg      ScoreMode = "avg"
+	ScoreModeFirst    ScoreMode = "first"
+	ScoreModeMax      ScoreMode = "max"
+	ScoreModeMin      ScoreMode = "min"
+)
+
+type BoostMode string
+
+const (
+	BoostModeMultiply BoostMode = "multiply"
+	BoostModeReplace  BoostMode = "replace"
+	BoostModeSum      BoostMode = "sum"
+	BoostModeAvg      BoostMode = "avg"
+	BoostModeMax      BoostMode = "max"
+	BoostModeMin      BoostMode = "min"
+)
+
+// FunctionScoreQuery modifies scores of documents matched by the query with score functions
+type FunctionScoreQuery struct {
+	Query     Query           `json:"query"`
+	Functions []ScoreFunction `json:"functions"`
+	ScoreMode ScoreMode       `json:"scoreMode"`
+	BoostMode BoostMode       `json:"boostMode"`
+	MaxBoost  *float64        `json:"maxBoost"`
+	MinScore  *float64        `json:"minScore"`
+	Boost     *float64        `json:"boost"`
+}
+
+func (q *FunctionScoreQuery) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Query     jsoniter.RawMessage `json:"query"`
+		Functions []ScoreFunction     `json:"functions"`
+		ScoreMode ScoreMode           `json:"scoreMode"`
+		BoostMode BoostMode           `json:"boostMode"`
+		MaxBoost  *float64            `json:"maxBoost"`
+		MinScore  *float64            `json:"minScore"`
+		Boost     *float64            `json:"boost"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+
+	// match all documents if query not provided
+	if len(d.Query) == 0 {
+		d.Query = []byte(`{"type": "match_all"}`)
+	}
+	q.Query, err = Build(QueryRequest(d.Query))
+	if err != nil {
+		return err
+	}
+
+	q.Functions = d.Functions
+	q.ScoreMode = d.ScoreMode
+	q.BoostMode = d.BoostMode
+	q.MaxBoost = d.MaxBoost
+	q.MinScore = d.MinScore
+	q.Boost = d.Boost
+
+	return nil
+}
+
+func (q *FunctionScoreQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Query, validation.Required, validation.NotNil),
+		validation.Field(&q.Functions, validation.Required),
+		validation.Field(&q.ScoreMode, validation.In(ScoreModeMultiply, ScoreModeSum, ScoreModeAvg, ScoreModeFirst, ScoreModeMax, ScoreModeMin)),
+		validation.Field(&q.BoostMode, validation.In(BoostModeMultiply, BoostModeReplace, BoostModeSum, BoostModeAvg, BoostModeMax, BoostModeMin)),
+		validation.Field(&q.MaxBoost, validation.Min(0.0)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *FunctionScoreQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	res, err := Exec(ctx, q.Query, fields)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+	if res.IsEmpty() {
+		return res, nil
+	}
+
+	now := time.Now()
+	functions := make([]scoreFunc, len(q.Functions))
+	for i, f := range q.Functions {
+		functions[i], err = f.build(ctx, fields, now)
+		if err != nil {
+			return NewEmptyResult(), err
+		}
+	}
+
+	s := &functionScoreScorer{
+		docs:      res.Docs(),
+		query:     res,
+		functions: functions,
+		scoreMode: q.ScoreMode,
+		boostMode: q.BoostMode,
+		maxBoost:  math.MaxFloat64,
+	}
+	if q.MaxBoost != nil {
+		s.maxBoost = *q.MaxBoost
+	}
+
+	if q.MinScore != nil {
+		docs := roaring.New()
+		for _, id := range res.Docs().ToArray() {
+			if s.Score(id) >= *q.MinScore {
+				docs.Add(id)
+			}
+		}
+		s.docs = docs
+	}
+
+	result := Result{
+		docs:    s.docs,
+		results: []scorer{s},
+	}
+
+	return withBoost(result, q.Boost), nil
+}
+
+// ScoreFunction calculates score of a document.
+// Only one of FieldValueFactor, Gauss, Exp, Linear and RandomScore can be defined.
+// If none is defined the function returns Weight.
+type ScoreFunction struct {
+	Filter           Query             `json:"filter"`
+	Weight           *float64          `json:"weight"`
+	FieldValueFactor *FieldValueFactor `json:"fieldValueFactor"`
+	Gauss            *DecayFunction    `json:"gauss"`
+	Exp              *DecayFunction    `json:"exp"`
+	Linear           *DecayFunction    `json:"linear"`
+	RandomScore      *RandomScore      `json:"randomScore"`
+}
+
+func (f *ScoreFunction) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Filter           jsoniter.RawMessage `json:"filter"`
+		Weight           *float64            `json:"weight"`
+		FieldValueFactor *FieldValueFactor   `json:"fieldValueFactor"`
+		Gauss            *DecayFunction      `json:"gauss"`
+		Exp              *DecayFunction      `json:"exp"`
+		Linear           *DecayFunction      `json:"linear"`
+		RandomScore      *RandomScore        `json:"randomScore"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+
+	if len(d.Filter) != 0 {
+		f.Filter, err = Build(QueryRequest(d.Filter))
+		if err != nil {
+			return err
+		}
+	}
+
+	f.Weight = d.Weight
+	f.FieldValueFactor = d.FieldValueFactor
+	f.Gauss = d.Gauss
+	f.Exp = d.Exp
+	f.Linear = d.Linear
+	f.RandomScore = d.RandomScore
+
+	return nil
+}
+
+func (f ScoreFunction) Validate() error {
+	cnt := 0
+	for _, defined := range []bool{f.FieldValueFactor != nil, f.Gauss != nil, f.Exp != nil, f.Linear != nil, f.RandomScore != nil} {
+		if defined {
+			cnt++
+		}
+	}
+	if cnt > 1 {
+		return errs.Errorf("only one score function can be defined")
+	}
+	if cnt == 0 && f.Weight == nil {
+		return errs.Errorf("score function or weight must be defined")
+	}
+
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Weight, validation.Min(0.0)),
+		validation.Field(&f.FieldValueFactor),
+		validation.Field(&f.Gauss),
+		validation.Field(&f.Exp),
+		validation.Field(&f.Linear),
+		validation.Field(&f.RandomScore),
+	)
+}
+
+func (f ScoreFunction) build(ctx context.Context, fields Fields, now time.Time) (scoreFunc, error) {
+	result := scoreFunc{weight: 1}
+	if f.Weight != nil {
+		result.weight = *f.Weight
+	}
+
+	if f.Filter != nil {
+		res, err := Exec(field.DisableScoring(ctx), f.Filter, fields)
+		if err != nil {
+			return result, err
+		}
+		result.filter = res.Docs()
+	}
+
+	var err error
+	switch {
+	case f.FieldValueFactor != nil:
+		result.score = f.FieldValueFactor.build(fields)
+	case f.Gauss != nil:
+		result.score, err = f.Gauss.build(fields, decayGauss, now)
+	case f.Exp != nil:
+		result.score, err = f.Exp.build(fields, decayExp, now)
+	case f.Linear != nil:
+		result.score, err = f.Linear.build(fields, decayLinear, now)
+	case f.RandomScore != nil:
+		result.score = f.RandomScore.build()
+	default:
+		result.score = func(id uint32) float64 { return 1 }
+	}
+
+	return result, err
+}
+
+type FieldValueModifier string
+
+const (
+	ModifierNone       FieldValueModifier = "none"
+	ModifierLog        FieldValueModifier = "log"
+	ModifierLog1p      FieldValueModifier = "log1p"
+	ModifierLog2p      FieldValueModifier = "log2p"
+	ModifierLn         FieldValueModifier = "ln"
+	ModifierLn1p       FieldValueModifier = "ln1p"
+	ModifierLn2p       FieldValueModifier = "ln2p"
+	ModifierSquare     FieldValueModifier = "square"
+	ModifierSqrt       FieldValueModifier = "sqrt"
+	ModifierReciprocal FieldValueModifier = "reciprocal"
+)
+
+// FieldValueFactor scores documents by numeric field value: modifier(factor * value)
+type FieldValueFactor struct {
+	Field    string             `json:"field"`
+	Factor   *float64           `json:"factor"`
+	Modifier FieldValueModifier `json:"modifier"`
+	Missing  *float64           `json:"missing"`
+}
+
+func (f FieldValueFactor) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&f.Modifier, validation.In(
+			ModifierNone, ModifierLog, ModifierLog1p, ModifierLog2p, ModifierLn, ModifierLn1p, ModifierLn2p,
+			ModifierSquare, ModifierSqrt, ModifierReciprocal,
+		)),
+	)
+}
+
+func (f FieldValueFactor) build(fields Fields) func(id uint32) float64 {
+	factor := 1.0
+	if f.Factor != nil {
+		factor = *f.Factor
+	}
+
+	src, _ := fields[f.Field].(field.NumericValues)
+
+	return func(id uint32) float64 {
+		value, ok := minFieldValue(src, id)
+		if !ok {
+			if f.Missing == nil {
+				return 1
+			}
+			value = *f.Missing
+		}
+
+		return applyModifier(f.Modifier, factor*value)
+	}
+}
+
+func applyModifier(m FieldValueModifier, v float64) float64 {
+	var result float64
+	switch m {
+	case ModifierLog:
+		result = math.Log10(v)
+	case ModifierLog1p:
+		result = math.Log10(v + 1)
+	case ModifierLog2p:
+		result = math.Log10(v + 2)
+	case ModifierLn:
+		result = math.Log(v)
+	case ModifierLn1p:
+		result = math.Log1p(v)
+	case ModifierLn2p:
+		result = math.Log(v + 2)
+	case ModifierSquare:
+		result = v * v
+	case ModifierSqrt:
+		result = math.Sqrt(v)
+	case ModifierReciprocal:
+		result = 1 / v
+	default:
+		result = v
+	}
+
+	// negative or undefined scores are not allowed
+	if math.IsNaN(result) || math.IsInf(result, 0) || result < 0 {
+		return 0
+	}
+
+	return result
+}
+
+type decayType int
+
+const (
+	decayGauss decayType = iota
+	decayExp
+	decayLinear
+)
+
+// DecayFunction scores documents by the distance between numeric field value and origin.
+// Documents at origin +/- offset get score 1, documents at origin +/- (offset + scale) get score equal to decay.
+//
+// Date values are supposed to be stored as unix timestamps in seconds.
+// Origin can be "now" then, scale and offset can be durations like "12h" or "7d".
+type DecayFunction struct {
+	Field  string      `json:"field"`
+	Origin interface{} `json:"origin"`
+	Scale  interface{} `json:"scale"`
+	Offset interface{} `json:"offset"`
+	Decay  *float64    `json:"decay"`
+}
+
+func (f DecayFunction) Validate() error {
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&f.Origin, validation.NotNil, validation.By(func(value interface{}) error {
+			_, err := parseDecayOrigin(value, time.Now())
+			return err
+		})),
+		validation.Field(&f.Scale, validation.NotNil, validation.By(func(value interface{}) error {
+			v, err := parseDecayDistance(value)
+			if err == nil && v <= 0 {
+				return errs.Errorf("must be > 0")
+			}
+			return err
+		})),
+		validation.Field(&f.Offset, validation.By(func(value interface{}) error {
+			if value == nil {
+				return nil
+			}
+			v, err := parseDecayDistance(value)
+			if err == nil && v < 0 {
+				return errs.Errorf("must be >= 0")
+			}
+			return err
+		})),
+		validation.Field(&f.Decay, validation.By(func(value interface{}) error {
+			v := value.(*float64)
+			if v != nil && (*v <= 0 || *v >= 1) {
+				return errs.Errorf("must be > 0 and < 1")
+			}
+			return nil
+		})),
+	)
+}
+
+func (f DecayFunction) build(fields Fields, t decayType, now time.Time) (func(id uint32) float64, error) {
+	origin, err := parseDecayOrigin(f.Origin, now)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := parseDecayDistance(f.Scale)
+	if err != nil {
+		return nil, err
+	}
+	var offset float64
+	if f.Offset != nil {
+		offset, err = parseDecayDistance(f.Offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	decay := 0.5
+	if f.Decay != nil {
+		decay = *f.Decay
+	}
+
+	src, _ := fields[f.Field].(field.NumericValues)
+
+	return func(id uint32) float64 {
+		distance, ok := minFieldDistance(src, id, origin)
+		if !ok {
+			return 1
+		}
+		distance = math.Max(0, distance-offset)
+
+		switch t {
+		case decayExp:
+			return math.Exp(math.Log(decay) / scale * distance)
+		case decayLinear:
+			s := scale / (1 - decay)
+			return math.Max(0, (s-distance)/s)
+		default:
+			sigma2 := -scale * scale / (2 * math.Log(decay))
+			return math.Exp(-distance * distance / (2 * sigma2))
+		}
+	}, nil
+}
+
+func parseDecayOrigin(value interface{}, now time.Time) (float64, error) {
+	if v, ok := value.(string); ok && v == "now" {
+		return float64(now.Unix()), nil
+	}
+
+	return cast.ToFloat64E(value)
+}
+
+// parseDecayDistance parses distance as number or duration in seconds
+func parseDecayDistance(value interface{}) (float64, error) {
+	v, ok := value.(string)
+	if !ok {
+		return cast.ToFloat64E(value)
+	}
+
+	if strings.HasSuffix(v, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(v, "d"), 64)
+		if err != nil {
+			return 0, errs.Errorf("invalid duration %q", v)
+		}
+		return days * 24 * 60 * 60, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return cast.ToFloat64E(v)
+	}
+
+	return d.Seconds(), nil
+}
+
+// RandomScore scores documents with random values in [0, 1) which are the same for the same seed
+type RandomScore struct {
+	Seed int64 `json:"seed"`
+}
+
+func (f RandomScore) Validate() error {
+	return nil
+}
+
+func (f RandomScore) build() func(id uint32) float64 {
+	return func(id uint32) float64 {
+		buf := make([]byte, 12)
+		binary.LittleEndian.PutUint64(buf, uint64(f.Seed))
+		binary.LittleEndian.PutUint32(buf[8:], id)
+
+		h := fnv.New64a()
+		h.Write(buf)
+
+		return float64(h.Sum64()>>11) / (1 << 53)
+	}
+}
+
+type scoreFunc struct {
+	filter *roaring.Bitmap // nil if the function is applied to all documents
+	weight float64
+	score  func(id uint32) float64
+}
+
+type functionScoreScorer struct {
+	docs      *roaring.Bitmap
+	query     Result
+	functions []scoreFunc
+	scoreMode ScoreMode
+	boostMode BoostMode
+	maxBoost  float64
+}
+
+func (s *functionScoreScorer) Score(id uint32) float64 {
+	if !s.docs.Contains(id) {
+		return 0
+	}
+
+	value, _ := s.functionsValue(id, false)
+
+	return s.combine(s.query.Score(id), value)
+}
+
+func (s *functionScoreScorer) Explain(id uint32) field.Explanation {
+	if !s.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
+
+	value, details := s.functionsValue(id, true)
+	description := fmt.Sprintf("functions value, score mode %q, of:", s.scoreMode)
+	if s.maxBoost != math.MaxFloat64 {
+		description = fmt.Sprintf("functions value, score mode %q, max boost %v, of:", s.scoreMode, s.maxBoost)
+	}
+	functions := field.NewExplanation(value, description, details...)
+
+	return field.NewExplanation(
+		s.Score(id),
+		fmt.Sprintf("function score, boost mode %q, of:", s.boostMode),
+		s.query.Explain(id),
+		functions,
+	)
+}
+
+// functionsValue combines values of the functions applied to the document. Function values are returned if explain is true
+func (s *functionScoreScorer) functionsValue(id uint32, explain bool) (float64, []field.Explanation) {
+	var (
+		value, weights float64
+		applied        bool
+		details        []field.Explanation
+	)
+
+	for i, f := range s.functions {
+		if f.filter != nil && !f.filter.Contains(id) {
+			continue
+		}
+
+		v := f.score(id) * f.weight
+		if explain {
+			details = append(details, field.NewExplanation(v, fmt.Sprintf("function #%d value multiplied by weight %v", i, f.weight)))
+		}
+		if !applied {
+			value = v
+			weights = f.weight
+			applied = true
+			if s.scoreMode == ScoreModeFirst {
+				break
+			}
+			continue
+		}
+
+		weights += f.weight
+		switch s.scoreMode {
+		case ScoreModeSum, ScoreModeAvg:
+			value += v
+		case ScoreModeMax:
+			value = math.Max(value, v)
+		case ScoreModeMin:
+			value = math.Min(value, v)
+		default:
+			value *= v
+		}
+	}
+
+	if !applied {
+		value = 1
+	} else if s.scoreMode == ScoreModeAvg && weights != 0 {
+		value /= weights
+	}
+	value = math.Min(value, s.maxBoost)
+
+	return value, details
+}
+
+// combine combines query score and functions value
+func (s *functionScoreScorer) combine(score float64, value float64) float64 {
+	switch s.boostMode {
+	case BoostModeReplace:
+		return value
+	case BoostModeSum:
+		return score + value
+	case BoostModeAvg:
+		return (score + value) / 2
+	case BoostModeMax:
+		return math.Max(score, value)
+	case BoostModeMin:
+		return math.Min(score, value)
+	default:
+		return score * value
+	}
+}
+
+// minFieldValue returns min numeric field value of the document
+func minFieldValue(f field.NumericValues, id uint32) (float64, bool) {
+	if f == nil {
+		return 0, false
+	}
+
+	var (
+		result float64
+		found  bool
+	)
+	for _, v := range f.FloatValues(id) {
+		if !found || v < result {
+			result = v
+			found = true
+		}
+	}
+
+	return result, found
+}
+
+// minFieldDistance returns min distance between numeric field values of the document and origin
+func minFieldDistance(f field.NumericValues, id uint32, origin float64) (float64, bool) {
+	if f == nil {
+		return 0, false
+	}
+
+	var (
+		result float64
+		found  bool
+	)
+	for _, v := range f.FloatValues(id) {
+		d := math.Abs(v - origin)
+		if !found || d < result {
+			result = d
+			found = true
+		}
+	}
+
+	return result, found
+}
@@ -0,0 +1,382 @@
+package query
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FunctionScoreQuery_Validate(t *testing.T) {
+	t.Run("must return error if functions not provided", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if function is empty", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [{}]
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if function defines several score functions", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{
+					"randomScore": {"seed": 1},
+					"fieldValueFactor": {"field": "field"}
+				}
+			]
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if score mode is invalid", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [{"weight": 2}],
+			"scoreMode": "invalid"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if decay scale is not provided", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{
+					"gauss": {"field": "field", "origin": 0}
+				}
+			]
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if decay is out of range", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{
+					"exp": {"field": "field", "origin": 0, "scale": 10, "decay": 1}
+				}
+			]
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"query": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"functions": [
+				{
+					"filter": {
+						"type": "term",
+						"field": "field",
+						"query": "query"
+					},
+					"weight": 2
+				},
+				{
+					"fieldValueFactor": {"field": "popularity", "factor": 1.2, "modifier": "log1p", "missing": 1}
+				},
+				{
+					"linear": {"field": "createdAt", "origin": "now", "scale": "7d", "offset": "12h"}
+				},
+				{
+					"randomScore": {"seed": 10}
+				}
+			],
+			"scoreMode": "sum",
+			"boostMode": "replace"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_FunctionScoreQuery_Exec(t *testing.T) {
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	keyword.Add(1, "foo")
+	keyword.Add(2, "foo")
+	keyword.Add(3, "bar")
+
+	popularity, err := field.New(schema.TypeInteger)
+	require.NoError(t, err)
+	popularity.Add(1, 10)
+	popularity.Add(2, 100)
+	popularity.Add(2, 1000)
+
+	all, err := field.New(schema.TypeAll)
+	require.NoError(t, err)
+	all.Add(1, true)
+	all.Add(2, true)
+	all.Add(3, true)
+
+	fields := Fields{"keyword": keyword, "popularity": popularity, field.AllField: all}
+
+	t.Run("must multiply query score by weight", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"query": {
+				"type": "term",
+				"field": "keyword",
+				"query": "foo",
+				"boost": 2
+			},
+			"functions": [{"weight": 3}]
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 6.0, result.Score(1))
+	})
+
+	t.Run("must score documents of bool queries with filter clauses only", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"query": {
+				"type": "bool",
+				"filter": [{"type": "term", "field": "keyword", "query": "foo"}]
+			},
+			"functions": [{"weight": 5}],
+			"boostMode": "replace"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 5.0, result.Score(1))
+		require.Equal(t, 0.0, result.Score(3))
+	})
+
+	t.Run("must apply functions only to filtered documents", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{
+					"filter": {"type": "term", "field": "keyword", "query": "bar"},
+					"weight": 5
+				}
+			],
+			"boostMode": "replace"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2, 3}, result.Docs().ToArray())
+		require.Equal(t, 1.0, result.Score(1))
+		require.Equal(t, 5.0, result.Score(3))
+	})
+
+	t.Run("must score by field value factor", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{
+					"fieldValueFactor": {"field": "popularity", "factor": 2, "modifier": "log1p", "missing": 4.5}
+				}
+			],
+			"boostMode": "replace"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.InDelta(t, math.Log10(21), result.Score(1), 1e-9)
+		require.InDelta(t, math.Log10(201), result.Score(2), 1e-9)
+		require.InDelta(t, 1.0, result.Score(3), 1e-9)
+	})
+
+	t.Run("must score by decay functions", func(t *testing.T) {
+		for _, decayType := range []string{"gauss", "exp", "linear"} {
+			t.Run(decayType, func(t *testing.T) {
+				query := new(FunctionScoreQuery)
+				mustUnmarshal(t, `{
+					"functions": [
+						{
+							"`+decayType+`": {"field": "popularity", "origin": 0, "offset": 10, "scale": 90, "decay": 0.5}
+						}
+					],
+					"boostMode": "replace"
+				}`, query)
+
+				result, err := query.Exec(context.Background(), fields)
+				require.NoError(t, err)
+				require.InDelta(t, 1.0, result.Score(1), 1e-9)
+				require.InDelta(t, 0.5, result.Score(2), 1e-9)
+				require.InDelta(t, 1.0, result.Score(3), 1e-9)
+			})
+		}
+	})
+
+	t.Run("must combine functions by score mode", func(t *testing.T) {
+		cases := map[string]float64{
+			"multiply": 6,
+			"sum":      5,
+			"avg":      1,
+			"first":    2,
+			"max":      3,
+			"min":      2,
+		}
+		for mode, expected := range cases {
+			t.Run(mode, func(t *testing.T) {
+				query := new(FunctionScoreQuery)
+				mustUnmarshal(t, `{
+					"functions": [{"weight": 2}, {"weight": 3}],
+					"scoreMode": "`+mode+`",
+					"boostMode": "replace"
+				}`, query)
+
+				result, err := query.Exec(context.Background(), fields)
+				require.NoError(t, err)
+				require.InDelta(t, expected, result.Score(1), 1e-9)
+			})
+		}
+	})
+
+	t.Run("must combine query score and function score by boost mode", func(t *testing.T) {
+		cases := map[string]float64{
+			"multiply": 8,
+			"replace":  4,
+			"sum":      6,
+			"avg":      3,
+			"max":      4,
+			"min":      2,
+		}
+		for mode, expected := range cases {
+			t.Run(mode, func(t *testing.T) {
+				query := new(FunctionScoreQuery)
+				mustUnmarshal(t, `{
+					"query": {"type": "term", "field": "keyword", "query": "foo", "boost": 2},
+					"functions": [{"weight": 4}],
+					"boostMode": "`+mode+`"
+				}`, query)
+
+				result, err := query.Exec(context.Background(), fields)
+				require.NoError(t, err)
+				require.InDelta(t, expected, result.Score(1), 1e-9)
+			})
+		}
+	})
+
+	t.Run("must limit function score by max boost", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [{"weight": 10}],
+			"maxBoost": 3,
+			"boostMode": "replace"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.Equal(t, 3.0, result.Score(1))
+		require.Contains(t, explanationText(result.Explain(1)), "max boost 3")
+	})
+
+	t.Run("must not explain max boost if it is not provided", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [{"weight": 10}],
+			"boostMode": "replace"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.NotContains(t, explanationText(result.Explain(1)), "max boost")
+	})
+
+	t.Run("must exclude documents with score less than min score", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [
+				{"fieldValueFactor": {"field": "popularity", "missing": 0}}
+			],
+			"boostMode": "replace",
+			"minScore": 50
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{2}, result.Docs().ToArray())
+	})
+
+	t.Run("random score must be stable for the same seed", func(t *testing.T) {
+		query := new(FunctionScoreQuery)
+		mustUnmarshal(t, `{
+			"functions": [{"randomScore": {"seed": 42}}],
+			"boostMode": "replace"
+		}`, query)
+
+		result1, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		result2, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+
+		for _, id := range []uint32{1, 2, 3} {
+			require.Equal(t, result1.Score(id), result2.Score(id))
+			require.GreaterOrEqual(t, result1.Score(id), 0.0)
+			require.Less(t, result1.Score(id), 1.0)
+		}
+		require.NotEqual(t, result1.Score(1), result1.Score(2))
+	})
+}
+
+func Test_parseDecayDistance(t *testing.T) {
+	v, err := parseDecayDistance("7d")
+	require.NoError(t, err)
+	require.Equal(t, float64(7*24*60*60), v)
+
+	v, err = parseDecayDistance("1h30m")
+	require.NoError(t, err)
+	require.Equal(t, float64(90*60), v)
+
+	v, err = parseDecayDistance("15")
+	require.NoError(t, err)
+	require.Equal(t, 15.0, v)
+
+	_, err = parseDecayDistance("foo")
+	require.Error(t, err)
+}
+
+func Test_parseDecayOrigin(t *testing.T) {
+	now := time.Now()
+	v, err := parseDecayOrigin("now", now)
+	require.NoError(t, err)
+	require.Equal(t, float64(now.Unix()), v)
+
+	v, err = parseDecayOrigin(10, now)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, v)
+}
+
+// explanationText joins descriptions of the explanation and its details
+func explanationText(e field.Explanation) string {
+	result := e.Description
+	for _, d := range e.Details {
+		result += "\n" + explanationText(d)
+	}
+
+	return result
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/geo"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Query = (*GeoDistanceQuery)(nil)
+var _ Query = (*GeoBoundingBoxQuery)(nil)
+var _ Query = (*GeoPolygonQuery)(nil)
+
+// GeoDistanceQuery matches documents having geo_point field values within the distance from the origin.
+// Points are objects {"lat": 1.5, "lon": 2}, "lat,lon" strings or geohashes. Distance is a number of meters or a string with a unit, e.g. "10km"
+type GeoDistanceQuery struct {
+	Field    string      `json:"field"`
+	Origin   interface{} `json:"origin"`
+	Distance interface{} `json:"distance"`
+	Boost    *float64    `json:"boost"`
+}
+
+func (q *GeoDistanceQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Origin, validation.NotNil, validation.By(validateGeoPoint)),
+		validation.Field(&q.Distance, validation.NotNil, validation.By(func(value interface{}) error {
+			_, err := geo.ParseDistance(value)
+			return err
+		})),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *GeoDistanceQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	f, err := field.GetGeoPoint(fields, q.Field)
+	if err != nil || f == nil {
+		return NewEmptyResult(), err
+	}
+	origin, err := geo.ParsePoint(q.Origin)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+	distance, err := geo.ParseDistance(q.Distance)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	fr := f.DistanceQuery(ctx, origin, distance).WithOpts(ctx, boostOpts(q.Boost)...)
+
+	return withDescription(NewResult(fr), fmt.Sprintf("geo_distance %s:%v within %vm", q.Field, origin, distance)), nil
+}
+
+// GeoBoundingBoxQuery matches documents having geo_point field values inside the box.
+// The box crosses the antimeridian if top left longitude is greater than bottom right one
+type GeoBoundingBoxQuery struct {
+	Field       string      `json:"field"`
+	TopLeft     interface{} `json:"topLeft"`
+	BottomRight interface{} `json:"bottomRight"`
+	Boost       *float64    `json:"boost"`
+}
+
+func (q *GeoBoundingBoxQuery) Validate() error {
+	err := validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.TopLeft, validation.NotNil, validation.By(validateGeoPoint)),
+		validation.Field(&q.BottomRight, validation.NotNil, validation.By(validateGeoPoint)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+	if err != nil {
+		return err
+	}
+
+	topLeft, _ := geo.ParsePoint(q.TopLeft)
+	bottomRight, _ := geo.ParsePoint(q.BottomRight)
+	if topLeft.Lat < bottomRight.Lat {
+		return validation.Errors{
+			"topLeft": validation.NewError("validation_geo_bounding_box", "top left latitude must be no less than bottom right latitude"),
+		}
+	}
+
+	return nil
+}
+
+func (q *GeoBoundingBoxQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	f, err := field.GetGeoPoint(fields, q.Field)
+	if err != nil || f == nil {
+		return NewEmptyResult(), err
+	}
+	topLeft, err := geo.ParsePoint(q.TopLeft)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+	bottomRight, err := geo.ParsePoint(q.BottomRight)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	fr := f.BoundingBoxQuery(ctx, geo.NewRect(topLeft, bottomRight)).WithOpts(ctx, boostOpts(q.Boost)...)
+
+	return withDescription(NewResult(fr), fmt.Sprintf("geo_bounding_box %s:%v-%v", q.Field, topLeft, bottomRight)), nil
+}
+
+// GeoPolygonQuery matches documents having geo_point field values inside the polygon. The last point is connected to the first one
+type GeoPolygonQuery struct {
+	Field  string        `json:"field"`
+	Points []interface{} `json:"points"`
+	Boost  *float64      `json:"boost"`
+}
+
+func (q *GeoPolygonQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Points,
+			validation.Required,
+			validation.Length(3, 0).Error("polygon must have at least 3 points"),
+			validation.Each(validation.NotNil, validation.By(validateGeoPoint))),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *GeoPolygonQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	f, err := field.GetGeoPoint(fields, q.Field)
+	if err != nil || f == nil {
+		return NewEmptyResult(), err
+	}
+
+	polygon := make(geo.Polygon, len(q.Points))
+	for i, v := range q.Points {
+		if polygon[i], err = geo.ParsePoint(v); err != nil {
+			return NewEmptyResult(), err
+		}
+	}
+	if err := polygon.Validate(); err != nil {
+		return NewEmptyResult(), err
+	}
+
+	fr := f.PolygonQuery(ctx, polygon).WithOpts(ctx, boostOpts(q.Boost)...)
+
+	return withDescription(NewResult(fr), fmt.Sprintf("geo_polygon %s:%v", q.Field, []geo.Point(polygon))), nil
+}
+
+func validateGeoPoint(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	_, err := geo.ParsePoint(value)
+
+	return err
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGeoFields(t *testing.T) Fields {
+	points, err := field.New(schema.TypeGeoPoint)
+	require.NoError(t, err)
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+
+	points.Add(1, "55.7558,37.6173")  // Moscow
+	points.Add(2, "59.9343,30.3351")  // Saint Petersburg
+	points.Add(3, "51.5074,-0.1278")  // London
+	points.Add(4, "-17.7134,178.065") // Fiji
+	keyword.Add(1, "a")
+
+	return Fields{"point": points, "keyword": keyword}
+}
+
+func Test_GeoDistanceQuery_Validate(t *testing.T) {
+	invalid := map[string]string{
+		"empty object":        `{}`,
+		"origin is missing":   `{"field": "point", "distance": 10}`,
+		"origin is invalid":   `{"field": "point", "origin": "91,0", "distance": 10}`,
+		"distance is missing": `{"field": "point", "origin": "1,2"}`,
+		"distance is invalid": `{"field": "point", "origin": "1,2", "distance": "10 parsecs"}`,
+		"boost is negative":   `{"field": "point", "origin": "1,2", "distance": 10, "boost": -1}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			query := new(GeoDistanceQuery)
+			mustUnmarshal(t, req, query)
+			require.Error(t, validation.Validate(query))
+		})
+	}
+
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(GeoDistanceQuery)
+		mustUnmarshal(t, `{"field": "point", "origin": {"lat": 1, "lon": 2.5}, "distance": "10km"}`, query)
+		require.NoError(t, validation.Validate(query))
+	})
+}
+
+func Test_GeoDistanceQuery_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := newTestGeoFields(t)
+
+	t.Run("must return documents within the distance", func(t *testing.T) {
+		query := new(GeoDistanceQuery)
+		mustUnmarshal(t, `{"field": "point", "origin": "55.75,37.61", "distance": "700km", "boost": 2}`, query)
+
+		result, err := query.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 2.0, result.Score(1))
+	})
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		query := &GeoDistanceQuery{Field: "unknown", Origin: "1,2", Distance: 10}
+		result, err := query.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+
+	t.Run("must return error if field is not a geo point", func(t *testing.T) {
+		query := &GeoDistanceQuery{Field: "keyword", Origin: "1,2", Distance: 10}
+		_, err := query.Exec(ctx, fields)
+		require.Error(t, err)
+	})
+}
+
+func Test_GeoBoundingBoxQuery_Validate(t *testing.T) {
+	invalid := map[string]string{
+		"empty object":             `{}`,
+		"top left is missing":      `{"field": "point", "bottomRight": "1,2"}`,
+		"bottom right is invalid":  `{"field": "point", "topLeft": "1,2", "bottomRight": "a,b"}`,
+		"top is lower than bottom": `{"field": "point", "topLeft": "1,2", "bottomRight": "3,4"}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			query := new(GeoBoundingBoxQuery)
+			mustUnmarshal(t, req, query)
+			require.Error(t, validation.Validate(query))
+		})
+	}
+
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(GeoBoundingBoxQuery)
+		mustUnmarshal(t, `{"field": "point", "topLeft": "10,170", "bottomRight": {"lat": -10, "lon": -170}}`, query)
+		require.NoError(t, validation.Validate(query))
+	})
+}
+
+func Test_GeoBoundingBoxQuery_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := newTestGeoFields(t)
+
+	query := new(GeoBoundingBoxQuery)
+	mustUnmarshal(t, `{"field": "point", "topLeft": "60,-10", "bottomRight": "50,35"}`, query)
+	result, err := query.Exec(ctx, fields)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{2, 3}, result.Docs().ToArray())
+
+	query = new(GeoBoundingBoxQuery)
+	mustUnmarshal(t, `{"field": "point", "topLeft": "0,170", "bottomRight": "-30,-170"}`, query)
+	result, err = query.Exec(ctx, fields)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{4}, result.Docs().ToArray())
+}
+
+func Test_GeoPolygonQuery_Validate(t *testing.T) {
+	invalid := map[string]string{
+		"empty object":     `{}`,
+		"too few points":   `{"field": "point", "points": ["1,2", "3,4"]}`,
+		"point is invalid": `{"field": "point", "points": ["1,2", "3,4", "a"]}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			query := new(GeoPolygonQuery)
+			mustUnmarshal(t, req, query)
+			require.Error(t, validation.Validate(query))
+		})
+	}
+
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(GeoPolygonQuery)
+		mustUnmarshal(t, `{"field": "point", "points": ["1,2", {"lat": 3, "lon": 4}, "s"]}`, query)
+		require.NoError(t, validation.Validate(query))
+	})
+}
+
+func Test_GeoPolygonQuery_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := newTestGeoFields(t)
+
+	query := new(GeoPolygonQuery)
+	mustUnmarshal(t, `{"field": "point", "points": ["54,36", "61,29", "57,45"]}`, query)
+	result, err := query.Exec(ctx, fields)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 2}, result.Docs().ToArray())
+}
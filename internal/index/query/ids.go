@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Query = (*IdsQuery)(nil)
+
+// IDs maps document GUIDs to internal document ids
+type IDs interface {
+	// ID returns 0 if the document not found
+	ID(guid string) uint32
+}
+
+func WithIDs(ctx context.Context, ids IDs) context.Context {
+	return context.WithValue(ctx, "query.ids", ids)
+}
+
+func idsFromContext(ctx context.Context) (IDs, bool) {
+	ids, ok := ctx.Value("query.ids").(IDs)
+	return ids, ok
+}
+
+// IdsQuery matches documents by their GUIDs
+type IdsQuery struct {
+	Values []string `json:"values"`
+	Boost  *float64 `json:"boost"`
+}
+
+func (q *IdsQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Values, validation.Required, validation.Each(validation.Required)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *IdsQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	ids, ok := idsFromContext(ctx)
+	if !ok {
+		return NewEmptyResult(), errs.Errorf("document ids are not provided")
+	}
+
+	docs := roaring.New()
+	for _, guid := range q.Values {
+		if id := ids.ID(guid); id != 0 {
+			docs.Add(id)
+		}
+	}
+
+	// ids are shared between indexes, so the documents of other indexes must be excluded
+	all := allResult(ctx, fields)
+	docs.And(all.Docs())
+	if docs.IsEmpty() {
+		return NewEmptyResult(), nil
+	}
+
+	return withBoost(newConstantResult(docs, 1), q.Boost), nil
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type testIDs map[string]uint32
+
+func (ids testIDs) ID(guid string) uint32 {
+	return ids[guid]
+}
+
+func Test_IdsQuery_Validate(t *testing.T) {
+	t.Run("must return error if values are not provided", func(t *testing.T) {
+		query := new(IdsQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if value is empty", func(t *testing.T) {
+		query := new(IdsQuery)
+		mustUnmarshal(t, `{"values": [""]}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(IdsQuery)
+		mustUnmarshal(t, `{"values": ["guid"]}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_IdsQuery_Exec(t *testing.T) {
+	all, err := field.New(schema.TypeAll)
+	require.NoError(t, err)
+	all.Add(1, true)
+	all.Add(2, true)
+
+	fields := Fields{field.AllField: all}
+	ids := testIDs{"guid1": 1, "guid2": 2, "guid3": 3}
+
+	t.Run("must return error if ids are not provided", func(t *testing.T) {
+		query := new(IdsQuery)
+		mustUnmarshal(t, `{"values": ["guid1"]}`, query)
+
+		_, err := query.Exec(context.Background(), fields)
+		require.Error(t, err)
+	})
+	t.Run("must return documents of the index by guids", func(t *testing.T) {
+		query := new(IdsQuery)
+		mustUnmarshal(t, `{"values": ["guid1", "guid3", "unknown"], "boost": 2}`, query)
+
+		result, err := query.Exec(WithIDs(context.Background(), ids), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+		require.Equal(t, 2.0, result.Score(1))
+	})
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var _ Query = (*KnnQuery)(nil)
+
+const (
+	KnnDefaultK             = 10
+	KnnDefaultNumCandidates = 100
+	KnnMaxNumCandidates     = 10000
+)
+
+// KnnQuery matches k documents which dense_vector field values are nearest to the query vector.
+// Approximate search checks numCandidates nearest candidates of the field HNSW graph, exact search compares the query vector with every document.
+// Only documents matched by the filter are returned if it is provided. Documents are scored by the field vector similarity
+type KnnQuery struct {
+	Field         string    `json:"field"`
+	QueryVector   []float32 `json:"queryVector"`
+	K             *int      `json:"k"`
+	NumCandidates *int      `json:"numCandidates"`
+	Filter        Query     `json:"filter"`
+	Exact         bool      `json:"exact"`
+	Boost         *float64  `json:"boost"`
+}
+
+func (q *KnnQuery) UnmarshalJSON(data []byte) error {
+	d := struct {
+		Field         string              `json:"field"`
+		QueryVector   []float32           `json:"queryVector"`
+		K             *int                `json:"k"`
+		NumCandidates *int                `json:"numCandidates"`
+		Filter        jsoniter.RawMessage `json:"filter"`
+		Exact         bool                `json:"exact"`
+		Boost         *float64            `json:"boost"`
+	}{}
+
+	dec := jsoniter.NewDecoder(bytes.NewBuffer(data))
+	dec.UseNumber()
+	err := dec.Decode(&d)
+	if err != nil {
+		return err
+	}
+	q.Field = d.Field
+	q.QueryVector = d.QueryVector
+	q.K = d.K
+	q.NumCandidates = d.NumCandidates
+	q.Exact = d.Exact
+	q.Boost = d.Boost
+
+	if len(d.Filter) != 0 {
+		q.Filter, err = Build(QueryRequest(d.Filter))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *KnnQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.QueryVector, validation.Required),
+		validation.Field(&q.K, validation.NilOrNotEmpty, validation.Min(1), validation.Max(KnnMaxNumCandidates)),
+		validation.Field(&q.NumCandidates,
+			validation.NilOrNotEmpty,
+			validation.Min(q.k()).Error(fmt.Sprintf("must be no less than k (%d)", q.k())),
+			validation.Max(KnnMaxNumCandidates)),
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *KnnQuery) k() int {
+	if q.K == nil {
+		return KnnDefaultK
+	}
+
+	return *q.K
+}
+
+func (q *KnnQuery) numCandidates() int {
+	if q.NumCandidates == nil {
+		if k := q.k(); k > KnnDefaultNumCandidates {
+			return k
+		}
+		return KnnDefaultNumCandidates
+	}
+
+	return *q.NumCandidates
+}
+
+func (q *KnnQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	f, ok := fields[q.Field]
+	if !ok {
+		return NewEmptyResult(), nil
+	}
+	vf, ok := f.(*field.DenseVector)
+	if !ok {
+		return NewEmptyResult(), validation.Errors{
+			"field": validation.NewError("validation_field_not_dense_vector", "field is not a dense_vector field"),
+		}
+	}
+	if len(q.QueryVector) != vf.Dims() {
+		return NewEmptyResult(), validation.Errors{
+			"queryVector": validation.NewError("validation_query_vector_dims", fmt.Sprintf("query vector must have %d dimensions", vf.Dims())),
+		}
+	}
+
+	var filter *roaring.Bitmap
+	if q.Filter != nil {
+		fr, err := Exec(field.DisableScoring(ctx), q.Filter, fields)
+		if err != nil {
+			return NewEmptyResult(), err
+		}
+		filter = fr.Docs()
+	}
+
+	var hits []field.VectorHit
+	if q.Exact {
+		hits = vf.ExactKNN(q.QueryVector, q.k(), filter)
+	} else {
+		hits = vf.KNN(q.QueryVector, q.k(), q.numCandidates(), filter)
+	}
+
+	ks := &knnScorer{
+		docs:            roaring.New(),
+		scores:          make(map[uint32]float64, len(hits)),
+		description:     vf.Similarity().Description(),
+		scoringDisabled: field.IsScoringDisabled(ctx),
+	}
+	for _, h := range hits {
+		ks.docs.Add(h.ID)
+		ks.scores[h.ID] = h.Score
+	}
+	result := Result{
+		docs:    ks.docs,
+		results: []scorer{ks},
+	}
+
+	return withBoost(withDescription(result, fmt.Sprintf("knn %s", q.Field)), q.Boost), nil
+}
+
+// knnScorer scores nearest neighbors by vector similarity
+type knnScorer struct {
+	docs            *roaring.Bitmap
+	scores          map[uint32]float64
+	description     string
+	scoringDisabled bool
+}
+
+func (s *knnScorer) Score(id uint32) float64 {
+	if s.scoringDisabled {
+		return 0
+	}
+
+	return s.scores[id]
+}
+
+func (s *knnScorer) Explain(id uint32) field.Explanation {
+	if s.scoringDisabled {
+		return field.NewExplanation(0, "scoring disabled")
+	}
+	if !s.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
+
+	return field.NewExplanation(s.scores[id], s.description)
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_KnnQuery_Validate(t *testing.T) {
+	invalid := map[string]string{
+		"empty object":                       `{}`,
+		"query vector is empty":              `{"field": "field", "queryVector": []}`,
+		"k is zero":                          `{"field": "field", "queryVector": [1, 2], "k": 0}`,
+		"num candidates is less than k":      `{"field": "field", "queryVector": [1, 2], "k": 20, "numCandidates": 10}`,
+		"num candidates is less than k=10":   `{"field": "field", "queryVector": [1, 2], "numCandidates": 5}`,
+		"num candidates is greater than max": `{"field": "field", "queryVector": [1, 2], "numCandidates": 10001}`,
+		"boost is negative":                  `{"field": "field", "queryVector": [1, 2], "boost": -1}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			query := new(KnnQuery)
+			mustUnmarshal(t, req, query)
+			require.Error(t, validation.Validate(query))
+		})
+	}
+
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(KnnQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"queryVector": [1, 2.5],
+			"k": 5,
+			"numCandidates": 50,
+			"filter": {"type": "term", "field": "field", "query": "value"},
+			"boost": 2
+		}`, query)
+		require.NoError(t, validation.Validate(query))
+		require.Equal(t, []float32{1, 2.5}, query.QueryVector)
+		require.IsType(t, &TermQuery{}, query.Filter)
+	})
+}
+
+func Test_KnnQuery_Exec(t *testing.T) {
+	ctx := context.Background()
+
+	vectors, err := field.New(schema.TypeDenseVector, field.FieldOpts{Dims: 2, VectorSimilarity: field.L2NormSimilarity{}})
+	require.NoError(t, err)
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+
+	vectors.Add(1, []float32{0, 0})
+	vectors.Add(2, []float32{1, 0})
+	vectors.Add(3, []float32{0, 2})
+	vectors.Add(4, []float32{3, 3})
+	keyword.Add(1, "a")
+	keyword.Add(2, "b")
+	keyword.Add(3, "b")
+	keyword.Add(4, "b")
+	fields := Fields{"vector": vectors, "keyword": keyword}
+
+	t.Run("must return k nearest documents", func(t *testing.T) {
+		for _, exact := range []bool{false, true} {
+			query := &KnnQuery{Field: "vector", QueryVector: []float32{0, 0}, Exact: exact}
+			query.K = new(int)
+			*query.K = 2
+
+			result, err := query.Exec(ctx, fields)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{1, 2}, result.Docs().ToArray())
+			require.Equal(t, 1.0, result.Score(1))
+			require.Equal(t, 0.5, result.Score(2))
+			require.Equal(t, 0.0, result.Score(3))
+			require.Equal(t, 0.5, result.Explain(2).Value)
+		}
+	})
+
+	t.Run("must return only filtered documents", func(t *testing.T) {
+		query := new(KnnQuery)
+		mustUnmarshal(t, `{
+			"field": "vector",
+			"queryVector": [0, 0],
+			"k": 2,
+			"filter": {"type": "term", "field": "keyword", "query": "b"},
+			"boost": 2
+		}`, query)
+
+		result, err := query.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, []uint32{2, 3}, result.Docs().ToArray())
+		require.Equal(t, 1.0, result.Score(2))
+		require.Equal(t, 0.4, result.Score(3))
+	})
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		query := &KnnQuery{Field: "unknown", QueryVector: []float32{0, 0}}
+
+		result, err := query.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+
+	t.Run("must return error if field is not a dense vector", func(t *testing.T) {
+		query := &KnnQuery{Field: "keyword", QueryVector: []float32{0, 0}}
+
+		_, err := query.Exec(ctx, fields)
+		require.Error(t, err)
+	})
+
+	t.Run("must return error if query vector dimensions are invalid", func(t *testing.T) {
+		query := &KnnQuery{Field: "vector", QueryVector: []float32{0, 0, 0}}
+
+		_, err := query.Exec(ctx, fields)
+		require.Error(t, err)
+	})
+
+	t.Run("must not score documents if scoring is disabled", func(t *testing.T) {
+		query := &KnnQuery{Field: "vector", QueryVector: []float32{0, 0}}
+
+		result, err := query.Exec(field.DisableScoring(ctx), fields)
+		require.NoError(t, err)
+		require.Equal(t, []uint32{1, 2, 3, 4}, result.Docs().ToArray())
+		require.Equal(t, 0.0, result.Score(1))
+	})
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/spf13/cast"
@@ -12,6 +13,7 @@ var _ Query = (*MatchQuery)(nil)
 type MatchQuery struct {
 	Field string      `json:"field"`
 	Query interface{} `json:"query"`
+	Boost *float64    `json:"boost"`
 }
 
 func (q *MatchQuery) Validate() error {
@@ -21,6 +23,7 @@ func (q *MatchQuery) Validate() error {
 			_, err := cast.ToStringE(value)
 			return err
 		})),
+		validation.Field(&q.Boost, validation.Min(0.0)),
 	)
 }
 
@@ -30,5 +33,9 @@ func (q *MatchQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
 		return NewEmptyResult(), nil
 	}
 
-	return NewResult(field.MatchQuery(ctx, q.Query)), nil
+	fr := field.MatchQuery(ctx, q.Query).WithOpts(ctx, boostOpts(q.Boost)...)
+	addTokens(ctx, q.Field, fr.Tokens())
+	result := NewResult(fr)
+
+	return withDescription(result, fmt.Sprintf("match %s:%v", q.Field, q.Query)), nil
 }
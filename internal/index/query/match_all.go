@@ -0,0 +1,32 @@
+package query
+
+import (
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Query = (*MatchAllQuery)(nil)
+var _ Query = (*MatchNoneQuery)(nil)
+
+// MatchAllQuery matches all documents of the index with the same score
+type MatchAllQuery struct {
+	Boost *float64 `json:"boost"`
+}
+
+func (q *MatchAllQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Boost, validation.Min(0.0)),
+	)
+}
+
+func (q *MatchAllQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	return withBoost(allResult(ctx, fields), q.Boost), nil
+}
+
+// MatchNoneQuery matches no documents
+type MatchNoneQuery struct{}
+
+func (q *MatchNoneQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	return NewEmptyResult(), nil
+}
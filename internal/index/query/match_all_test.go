@@ -0,0 +1,38 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MatchAllQuery_Exec(t *testing.T) {
+	all, err := field.New(schema.TypeAll)
+	require.NoError(t, err)
+	all.Add(1, true)
+	all.Add(2, true)
+
+	query := new(MatchAllQuery)
+	mustUnmarshal(t, `{"boost": 2}`, query)
+
+	result, err := query.Exec(context.Background(), Fields{field.AllField: all})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	require.Equal(t, 2.0, result.Score(1))
+}
+
+func Test_MatchNoneQuery_Exec(t *testing.T) {
+	all, err := field.New(schema.TypeAll)
+	require.NoError(t, err)
+	all.Add(1, true)
+
+	query := new(MatchNoneQuery)
+	mustUnmarshal(t, `{}`, query)
+
+	result, err := query.Exec(context.Background(), Fields{field.AllField: all})
+	require.NoError(t, err)
+	require.True(t, result.Docs().IsEmpty())
+}
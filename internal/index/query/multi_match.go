@@ -0,0 +1,225 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/spf13/cast"
+)
+
+var _ Query = (*MultiMatchQuery)(nil)
+
+type MultiMatchMode string
+
+const (
+	// MultiMatchBestFields scores documents by the best matching field
+	MultiMatchBestFields MultiMatchMode = "best_fields"
+	// MultiMatchMostFields sums scores of all matching fields
+	MultiMatchMostFields MultiMatchMode = "most_fields"
+	// MultiMatchCrossFields treats fields as one big field and scores every token by the best matching field
+	MultiMatchCrossFields MultiMatchMode = "cross_fields"
+)
+
+type MultiMatchQuery struct {
+	Fields     []string       `json:"fields"`
+	Query      interface{}    `json:"query"`
+	Mode       MultiMatchMode `json:"mode"`
+	TieBreaker float64        `json:"tieBreaker"`
+}
+
+func (q *MultiMatchQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Fields, validation.Required, validation.Each(validation.By(func(value interface{}) error {
+			_, _, err := parseFieldBoost(value.(string))
+			return err
+		}))),
+		validation.Field(&q.Query, validation.NotNil, validation.By(func(value interface{}) error {
+			_, err := cast.ToStringE(value)
+			return err
+		})),
+		validation.Field(&q.Mode, validation.In(MultiMatchBestFields, MultiMatchMostFields, MultiMatchCrossFields)),
+		validation.Field(&q.TieBreaker, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
+
+func (q *MultiMatchQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	result, err := q.exec(ctx, fields)
+	if err != nil {
+		return result, err
+	}
+
+	return withDescription(result, fmt.Sprintf("multi_match %s:%v", strings.Join(q.Fields, ","), q.Query)), nil
+}
+
+func (q *MultiMatchQuery) exec(ctx context.Context, fields Fields) (Result, error) {
+	results := make([]*field.QueryResult, 0, len(q.Fields))
+	for _, item := range q.Fields {
+		name, boost, err := parseFieldBoost(item)
+		if err != nil {
+			return NewEmptyResult(), err
+		}
+
+		f, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		fr := f.MatchQuery(ctx, q.Query).WithOpts(ctx, field.WithBoost(boost))
+		addTokens(ctx, name, fr.Tokens())
+		results = append(results, fr)
+	}
+
+	if len(results) == 0 {
+		return NewEmptyResult(), nil
+	}
+
+	switch q.Mode {
+	case MultiMatchMostFields:
+		result := NewEmptyResult()
+		for _, r := range results {
+			result.Or(NewResult(r))
+		}
+		return result, nil
+	case MultiMatchCrossFields:
+		docs := make([]*roaring.Bitmap, len(results))
+		for i, r := range results {
+			docs[i] = r.Docs()
+		}
+
+		return Result{
+			docs: roaring.FastOr(docs...),
+			results: []scorer{&crossFieldsScorer{
+				results:    results,
+				query:      cast.ToString(q.Query),
+				tieBreaker: q.TieBreaker,
+			}},
+		}, nil
+	default:
+		rr := make([]Result, len(results))
+		for i, r := range results {
+			rr[i] = NewResult(r)
+		}
+		return newDisMaxResult(rr, q.TieBreaker), nil
+	}
+}
+
+// crossFieldsScorer scores every query token by the best matching field plus tieBreaker * scores of the others.
+// Results without analyzed tokens (e.g. keyword fields) are scored as a single token equal to the whole query.
+type crossFieldsScorer struct {
+	results    []*field.QueryResult
+	query      string
+	tieBreaker float64
+}
+
+func (s *crossFieldsScorer) Score(id uint32) float64 {
+	sums := make(map[string]float64)
+	maxs := make(map[string]float64)
+	add := func(token string, score float64) {
+		sums[token] += score
+		if score > maxs[token] {
+			maxs[token] = score
+		}
+	}
+
+	for _, r := range s.results {
+		tokens := r.Tokens()
+		if len(tokens) == 0 {
+			add(s.query, r.Score(id))
+			continue
+		}
+
+		seen := make(map[string]struct{}, len(tokens))
+		for _, token := range tokens {
+			if _, ok := seen[token]; ok {
+				continue
+			}
+			seen[token] = struct{}{}
+			add(token, r.TokenScore(id, token))
+		}
+	}
+
+	var result float64
+	for token, sum := range sums {
+		result += maxs[token] + s.tieBreaker*(sum-maxs[token])
+	}
+
+	return result
+}
+
+func (s *crossFieldsScorer) Explain(id uint32) field.Explanation {
+	var tokens []string
+	details := make(map[string][]field.Explanation)
+	add := func(token string, e field.Explanation) {
+		if _, ok := details[token]; !ok {
+			tokens = append(tokens, token)
+		}
+		details[token] = append(details[token], e)
+	}
+
+	for _, r := range s.results {
+		rTokens := r.Tokens()
+		if len(rTokens) == 0 {
+			add(s.query, r.Explain(id))
+			continue
+		}
+
+		seen := make(map[string]struct{}, len(rTokens))
+		for _, token := range rTokens {
+			if _, ok := seen[token]; ok {
+				continue
+			}
+			seen[token] = struct{}{}
+			add(token, r.ExplainToken(id, token))
+		}
+	}
+
+	result := make([]field.Explanation, len(tokens))
+	for i, token := range tokens {
+		var sum, max float64
+		for _, e := range details[token] {
+			sum += e.Value
+			if e.Value > max {
+				max = e.Value
+			}
+		}
+		result[i] = field.NewExplanation(
+			max+s.tieBreaker*(sum-max),
+			fmt.Sprintf("token %q, max plus %v times others of:", token, s.tieBreaker),
+			details[token]...,
+		)
+	}
+
+	return field.SumExplanation("cross fields, sum of:", result...)
+}
+
+// parseFieldBoost parses "field^boost" notation
+func parseFieldBoost(value string) (string, float64, error) {
+	i := strings.LastIndex(value, "^")
+	if i < 0 {
+		if value == "" {
+			return "", 0, errs.Errorf("field name cannot be empty")
+		}
+		return value, 1.0, nil
+	}
+
+	name := value[:i]
+	if name == "" {
+		return "", 0, errs.Errorf("field name cannot be empty")
+	}
+
+	boost, err := strconv.ParseFloat(value[i+1:], 64)
+	if err != nil {
+		return "", 0, errs.Errorf("invalid boost %q of field %q", value[i+1:], name)
+	}
+	if boost < 0 {
+		return "", 0, errs.Errorf("boost of field %q must be >= 0", name)
+	}
+
+	return name, boost, nil
+}
@@ -0,0 +1,210 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseFieldBoost(t *testing.T) {
+	t.Run("must return default boost if not provided", func(t *testing.T) {
+		name, boost, err := parseFieldBoost("title")
+		require.NoError(t, err)
+		require.Equal(t, "title", name)
+		require.Equal(t, 1.0, boost)
+	})
+	t.Run("must parse boost", func(t *testing.T) {
+		name, boost, err := parseFieldBoost("title^2.5")
+		require.NoError(t, err)
+		require.Equal(t, "title", name)
+		require.Equal(t, 2.5, boost)
+	})
+	t.Run("must return error if boost is invalid", func(t *testing.T) {
+		_, _, err := parseFieldBoost("title^foo")
+		require.Error(t, err)
+	})
+	t.Run("must return error if boost is negative", func(t *testing.T) {
+		_, _, err := parseFieldBoost("title^-1")
+		require.Error(t, err)
+	})
+	t.Run("must return error if field name is empty", func(t *testing.T) {
+		_, _, err := parseFieldBoost("^2")
+		require.Error(t, err)
+	})
+}
+
+func Test_MultiMatchQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if field boost is invalid", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title^a"],
+			"query": "query"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if mode is invalid", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title"],
+			"query": "query",
+			"mode": "invalid"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if tie breaker is out of range", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title"],
+			"query": "query",
+			"tieBreaker": 2
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title^3", "description"],
+			"query": "query",
+			"mode": "cross_fields",
+			"tieBreaker": 0.3
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_MultiMatchQuery_Exec(t *testing.T) {
+	analyzer := func(s []string) []string {
+		var result []string
+		for _, str := range s {
+			result = append(result, strings.Fields(str)...)
+		}
+		return result
+	}
+
+	title, err := field.New(schema.TypeText, field.FieldOpts{Analyzer: analyzer, Scoring: field.NewScoring()})
+	require.NoError(t, err)
+	title.Add(1, "foo bar")
+	title.Add(2, "baz")
+
+	description, err := field.New(schema.TypeText, field.FieldOpts{Analyzer: analyzer, Scoring: field.NewScoring()})
+	require.NoError(t, err)
+	description.Add(1, "baz")
+	description.Add(2, "foo")
+	description.Add(3, "bar")
+
+	tags, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	tags.Add(3, "foo")
+
+	fields := Fields{"title": title, "description": description, "tags": tags}
+	single := func(t *testing.T, name string, q string, id uint32) float64 {
+		query := new(MatchQuery)
+		mustUnmarshal(t, `{"field": "`+name+`", "query": "`+q+`"}`, query)
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		return result.Score(id)
+	}
+
+	t.Run("must return empty result if no fields found", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["field1"],
+			"query": "foo"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+
+	t.Run("best_fields must score documents by the best field", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title", "description", "tags"],
+			"query": "foo"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2, 3}, result.Docs().ToArray())
+		require.Equal(t, single(t, "title", "foo", 1), result.Score(1))
+		require.Equal(t, single(t, "description", "foo", 2), result.Score(2))
+		require.Equal(t, 1.0, result.Score(3))
+	})
+
+	t.Run("best_fields must match documents by any field", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title", "description"],
+			"query": "baz",
+			"tieBreaker": 0.5
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, single(t, "description", "baz", 1), result.Score(1))
+		require.Equal(t, single(t, "title", "baz", 2), result.Score(2))
+	})
+
+	t.Run("must apply field boosts", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title^3", "description"],
+			"query": "foo"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.InDelta(t, 3*single(t, "title", "foo", 1), result.Score(1), 1e-9)
+	})
+
+	t.Run("most_fields must sum scores of all fields", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title", "description"],
+			"query": "foo baz",
+			"mode": "most_fields"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.InDelta(t, single(t, "title", "foo baz", 1)+single(t, "description", "foo baz", 1), result.Score(1), 1e-9)
+	})
+
+	t.Run("cross_fields must score every token by the best field", func(t *testing.T) {
+		query := new(MultiMatchQuery)
+		mustUnmarshal(t, `{
+			"fields": ["title", "description"],
+			"query": "foo baz",
+			"mode": "cross_fields"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), fields)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.InDelta(t, single(t, "title", "foo", 1)+single(t, "description", "baz", 1), result.Score(1), 1e-9)
+		require.InDelta(t, single(t, "description", "foo", 2)+single(t, "title", "baz", 2), result.Score(2), 1e-9)
+	})
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// PatternDefaultMaxExpansions max number of terms a pattern query is expanded to if not provided
+const PatternDefaultMaxExpansions = 50
+
+var _ Query = (*PrefixQuery)(nil)
+var _ Query = (*WildcardQuery)(nil)
+var _ Query = (*RegexpQuery)(nil)
+
+type PrefixQuery struct {
+	Field           string `json:"field"`
+	Query           string `json:"query"`
+	CaseInsensitive bool   `json:"caseInsensitive"`
+	MaxExpansions   int    `json:"maxExpansions"`
+}
+
+func (q *PrefixQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Query, validation.Required),
+		validation.Field(&q.MaxExpansions, validation.Min(0)),
+	)
+}
+
+func (q *PrefixQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	pattern, err := field.NewPrefixPattern(q.Query, q.CaseInsensitive)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	return execPattern(ctx, fields, q.Field, pattern, q.MaxExpansions), nil
+}
+
+type WildcardQuery struct {
+	Field           string `json:"field"`
+	Query           string `json:"query"`
+	CaseInsensitive bool   `json:"caseInsensitive"`
+	MaxExpansions   int    `json:"maxExpansions"`
+}
+
+func (q *WildcardQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Query, validation.Required, validation.By(func(value interface{}) error {
+			_, err := field.NewWildcardPattern(value.(string), q.CaseInsensitive)
+			return err
+		})),
+		validation.Field(&q.MaxExpansions, validation.Min(0)),
+	)
+}
+
+func (q *WildcardQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	pattern, err := field.NewWildcardPattern(q.Query, q.CaseInsensitive)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	return execPattern(ctx, fields, q.Field, pattern, q.MaxExpansions), nil
+}
+
+type RegexpQuery struct {
+	Field           string `json:"field"`
+	Query           string `json:"query"`
+	CaseInsensitive bool   `json:"caseInsensitive"`
+	MaxExpansions   int    `json:"maxExpansions"`
+}
+
+func (q *RegexpQuery) Validate() error {
+	return validation.ValidateStruct(q,
+		validation.Field(&q.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&q.Query, validation.Required, validation.By(func(value interface{}) error {
+			_, err := field.NewRegexpPattern(value.(string), q.CaseInsensitive)
+			return err
+		})),
+		validation.Field(&q.MaxExpansions, validation.Min(0)),
+	)
+}
+
+func (q *RegexpQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
+	pattern, err := field.NewRegexpPattern(q.Query, q.CaseInsensitive)
+	if err != nil {
+		return NewEmptyResult(), err
+	}
+
+	return execPattern(ctx, fields, q.Field, pattern, q.MaxExpansions), nil
+}
+
+func execPattern(ctx context.Context, fields Fields, name string, pattern field.Pattern, maxExpansions int) Result {
+	f, ok := fields[name]
+	if !ok {
+		return NewEmptyResult()
+	}
+
+	if maxExpansions == 0 {
+		maxExpansions = PatternDefaultMaxExpansions
+	}
+
+	return withDescription(NewResult(f.PatternQuery(ctx, pattern, maxExpansions)), fmt.Sprintf("pattern %s", name))
+}
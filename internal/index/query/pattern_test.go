@@ -0,0 +1,210 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PrefixQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if max expansions is negative", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "query",
+			"maxExpansions": -1
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "query"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_PrefixQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "SKU-100")
+	f.Add(2, "sku-200")
+	f.Add(3, "item-300")
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field1",
+			"query": "sku"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+	t.Run("must return documents with values starting with prefix", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "sku"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{2}, result.Docs().ToArray())
+	})
+	t.Run("must ignore case if case insensitive", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "sku",
+			"caseInsensitive": true
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	})
+	t.Run("must limit matched values by max expansions", func(t *testing.T) {
+		query := new(PrefixQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "sku",
+			"caseInsensitive": true,
+			"maxExpansions": 1
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
+	})
+}
+
+func Test_WildcardQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(WildcardQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if query is not a valid wildcard", func(t *testing.T) {
+		query := new(WildcardQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "query\\"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(WildcardQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "qu?r*"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_WildcardQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "SKU-100")
+	f.Add(2, "sku-200")
+	f.Add(3, "item-300")
+
+	t.Run("must return documents with values matching wildcard", func(t *testing.T) {
+		query := new(WildcardQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "*-?00"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2, 3}, result.Docs().ToArray())
+	})
+	t.Run("must return empty result if nothing matches", func(t *testing.T) {
+		query := new(WildcardQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "sku-?"
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.True(t, result.Docs().IsEmpty())
+	})
+}
+
+func Test_RegexpQuery_Validate(t *testing.T) {
+	t.Run("must return error if request is an empty object", func(t *testing.T) {
+		query := new(RegexpQuery)
+		mustUnmarshal(t, `{}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must return error if query is not a valid regexp", func(t *testing.T) {
+		query := new(RegexpQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "("
+		}`, query)
+
+		err := validation.Validate(query)
+		require.Error(t, err)
+	})
+	t.Run("must not return error if request is valid", func(t *testing.T) {
+		query := new(RegexpQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "[a-z]+"
+		}`, query)
+
+		err := validation.Validate(query)
+		require.NoError(t, err)
+	})
+}
+
+func Test_RegexpQuery_Exec(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "SKU-100")
+	f.Add(2, "sku-200")
+	f.Add(3, "item-300")
+
+	t.Run("must return documents with values matching regexp", func(t *testing.T) {
+		query := new(RegexpQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "sku-[0-9]+",
+			"caseInsensitive": true
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+	})
+}
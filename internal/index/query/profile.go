@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Profile contains query execution time and the number of matched documents.
+// Time includes execution time of the child queries
+type Profile struct {
+	Type      string     `json:"type"`
+	TimeNanos int64      `json:"timeNanos"`
+	Docs      uint64     `json:"docs"`
+	Children  []*Profile `json:"children,omitempty"`
+
+	mtx sync.Mutex
+}
+
+type profileKey struct{}
+
+// WithProfile enables query profiling. Profiles of queries executed with the returned context are added to the returned profile children
+func WithProfile(ctx context.Context) (context.Context, *Profile) {
+	p := new(Profile)
+	return context.WithValue(ctx, profileKey{}, p), p
+}
+
+func profileFromCtx(ctx context.Context) *Profile {
+	p, _ := ctx.Value(profileKey{}).(*Profile)
+	return p
+}
+
+// add child profile. Child queries may be executed in parallel
+func (p *Profile) add(queryType string) *Profile {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	child := &Profile{Type: queryType}
+	p.Children = append(p.Children, child)
+
+	return child
+}
+
+// Exec executes query and profiles it if profiling is enabled
+func Exec(ctx context.Context, q Query, fields Fields) (Result, error) {
+	parent := profileFromCtx(ctx)
+	if parent == nil {
+		return q.Exec(ctx, fields)
+	}
+
+	p := parent.add(queryTypeName(q))
+	t := time.Now()
+	result, err := q.Exec(context.WithValue(ctx, profileKey{}, p), fields)
+	p.TimeNanos = time.Since(t).Nanoseconds()
+	if err != nil {
+		return result, err
+	}
+	p.Docs = result.Docs().GetCardinality()
+
+	return result, nil
+}
+
+func queryTypeName(q Query) string {
+	t := reflect.TypeOf(q)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
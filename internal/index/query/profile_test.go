@@ -0,0 +1,61 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Exec_Profile(t *testing.T) {
+	f, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	f.Add(1, "foo")
+	f.Add(2, "foo")
+	f.Add(2, "bar")
+	fields := Fields{"field": f}
+
+	for _, parallel := range []bool{false, true} {
+		q := new(BoolQuery)
+		mustUnmarshal(t, `{
+			"should": [
+				{"type": "term", "field": "field", "query": "foo"},
+				{"type": "term", "field": "field", "query": "bar"}
+			]
+		}`, q)
+		q.Parallel = parallel
+
+		t.Run("must build profile tree", func(t *testing.T) {
+			ctx, p := WithProfile(context.Background())
+			result, err := Exec(ctx, q, fields)
+			require.NoError(t, err)
+			require.Equal(t, uint64(2), result.Docs().GetCardinality())
+
+			require.Len(t, p.Children, 1)
+			root := p.Children[0]
+			require.Equal(t, "BoolQuery", root.Type)
+			require.Equal(t, uint64(2), root.Docs)
+			require.Greater(t, root.TimeNanos, int64(0))
+
+			require.Len(t, root.Children, 2)
+			docs := make(map[uint64]struct{})
+			for _, child := range root.Children {
+				require.Equal(t, "TermQuery", child.Type)
+				require.LessOrEqual(t, child.TimeNanos, root.TimeNanos)
+				docs[child.Docs] = struct{}{}
+			}
+			require.Equal(t, map[uint64]struct{}{1: {}, 2: {}}, docs)
+		})
+	}
+
+	t.Run("must not profile if profiling is disabled", func(t *testing.T) {
+		q := new(TermQuery)
+		mustUnmarshal(t, `{"field": "field", "query": "foo"}`, q)
+
+		result, err := Exec(context.Background(), q, fields)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), result.Docs().GetCardinality())
+	})
+}
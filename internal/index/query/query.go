@@ -23,9 +23,16 @@ type Total struct {
 type QueryRequest jsoniter.RawMessage
 type Fields map[string]field.Field
 
+// scorer calculates document score. It must return 0 for documents it does not match.
+// Explain describes how the score is calculated, its value must be equal to the score
+type scorer interface {
+	Score(id uint32) float64
+	Explain(id uint32) field.Explanation
+}
+
 type Result struct {
 	docs    *roaring.Bitmap
-	results []*field.QueryResult
+	results []scorer
 }
 
 func (r Result) IsEmpty() bool {
@@ -41,17 +48,83 @@ func NewEmptyResult() Result {
 func NewResult(res *field.QueryResult) Result {
 	return Result{
 		docs:    res.Docs(),
-		results: []*field.QueryResult{res},
+		results: []scorer{res},
+	}
+}
+
+// newDisMaxResult combines results into one: documents matching any of them,
+// scored by the best matching result plus tieBreaker * scores of the others
+func newDisMaxResult(results []Result, tieBreaker float64) Result {
+	dm := &disMaxScorer{tieBreaker: tieBreaker}
+	docs := make([]*roaring.Bitmap, 0, len(results))
+	for _, r := range results {
+		if r.IsEmpty() {
+			continue
+		}
+		dm.results = append(dm.results, r)
+		docs = append(docs, r.Docs())
+	}
+
+	if len(dm.results) == 0 {
+		return NewEmptyResult()
+	}
+
+	return Result{
+		docs:    roaring.FastOr(docs...),
+		results: []scorer{dm},
+	}
+}
+
+// newConstantResult matches docs with the same score
+func newConstantResult(docs *roaring.Bitmap, score float64) Result {
+	return Result{
+		docs:    docs,
+		results: []scorer{&constantScorer{docs: docs, score: score}},
+	}
+}
+
+// withDescription groups result scorers to describe them in explanations, e.g. by the query which produced them
+func withDescription(r Result, description string) Result {
+	if r.IsEmpty() {
+		return r
+	}
+
+	return Result{
+		docs:    r.docs,
+		results: []scorer{&describedScorer{results: r.results, description: description}},
 	}
 }
 
+// withBoost multiplies result scores by boost
+func withBoost(r Result, boost *float64) Result {
+	if boost == nil || *boost == 1 || r.IsEmpty() {
+		return r
+	}
+
+	return Result{
+		docs:    r.docs,
+		results: []scorer{&boostScorer{result: r, boost: *boost}},
+	}
+}
+
+// boostOpts converts query boost to field result options
+func boostOpts(boost *float64) []field.ResultOpt {
+	if boost == nil {
+		return nil
+	}
+
+	return []field.ResultOpt{field.WithBoost(*boost)}
+}
+
+// And intersects result documents. Source bitmaps are not modified as they are shared with the scorers
 func (r *Result) And(res Result) {
-	r.docs.And(res.Docs())
+	r.docs = roaring.And(r.docs, res.Docs())
 	r.results = append(r.results, res.results...)
 }
 
+// Or unites result documents. Source bitmaps are not modified as they are shared with the scorers
 func (r *Result) Or(res Result) {
-	r.docs.Or(res.Docs())
+	r.docs = roaring.Or(r.docs, res.Docs())
 	r.results = append(r.results, res.results...)
 }
 
@@ -72,6 +145,117 @@ func (r *Result) Score(id uint32) float64 {
 	return result
 }
 
+// Explain describes how the document score is calculated
+func (r *Result) Explain(id uint32) field.Explanation {
+	if !r.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
+
+	if len(r.results) == 1 {
+		return r.results[0].Explain(id)
+	}
+
+	details := make([]field.Explanation, len(r.results))
+	for i, res := range r.results {
+		details[i] = res.Explain(id)
+	}
+
+	return field.SumExplanation("sum of:", details...)
+}
+
+type describedScorer struct {
+	results     []scorer
+	description string
+}
+
+func (s *describedScorer) Score(id uint32) float64 {
+	result := 0.0
+	for _, res := range s.results {
+		result += res.Score(id)
+	}
+
+	return result
+}
+
+func (s *describedScorer) Explain(id uint32) field.Explanation {
+	details := make([]field.Explanation, len(s.results))
+	for i, res := range s.results {
+		details[i] = res.Explain(id)
+	}
+
+	return field.SumExplanation(s.description+", sum of:", details...)
+}
+
+type disMaxScorer struct {
+	results    []Result
+	tieBreaker float64
+}
+
+func (s *disMaxScorer) Score(id uint32) float64 {
+	var sum, max float64
+	for _, r := range s.results {
+		score := r.Score(id)
+		sum += score
+		if score > max {
+			max = score
+		}
+	}
+
+	return max + s.tieBreaker*(sum-max)
+}
+
+func (s *disMaxScorer) Explain(id uint32) field.Explanation {
+	details := make([]field.Explanation, len(s.results))
+	for i, r := range s.results {
+		details[i] = r.Explain(id)
+	}
+
+	return field.NewExplanation(
+		s.Score(id),
+		fmt.Sprintf("max plus %v times others of:", s.tieBreaker),
+		details...,
+	)
+}
+
+type boostScorer struct {
+	result Result
+	boost  float64
+}
+
+func (s *boostScorer) Score(id uint32) float64 {
+	return s.result.Score(id) * s.boost
+}
+
+func (s *boostScorer) Explain(id uint32) field.Explanation {
+	return field.NewExplanation(
+		s.Score(id),
+		"product of:",
+		s.result.Explain(id),
+		field.NewExplanation(s.boost, "boost"),
+	)
+}
+
+type constantScorer struct {
+	docs  *roaring.Bitmap
+	score float64
+}
+
+func (s *constantScorer) Score(id uint32) float64 {
+	if !s.docs.Contains(id) {
+		return 0
+	}
+
+	return s.score
+}
+
+func (s *constantScorer) Explain(id uint32) field.Explanation {
+	if !s.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
+
+	return field.NewExplanation(s.score, "constant score")
+}
+
 type QueryType struct {
 	Type string `json:"type"`
 }
@@ -96,8 +280,40 @@ func Build(req QueryRequest) (Query, error) {
 		query = new(BoolQuery)
 	case "match":
 		query = new(MatchQuery)
+	case "multi_match":
+		query = new(MultiMatchQuery)
 	case "range":
 		query = new(RangeQuery)
+	case "constant_score":
+		query = new(ConstantScoreQuery)
+	case "dis_max":
+		query = new(DisMaxQuery)
+	case "boosting":
+		query = new(BoostingQuery)
+	case "function_score":
+		query = new(FunctionScoreQuery)
+	case "prefix":
+		query = new(PrefixQuery)
+	case "wildcard":
+		query = new(WildcardQuery)
+	case "regexp":
+		query = new(RegexpQuery)
+	case "exists":
+		query = new(ExistsQuery)
+	case "ids":
+		query = new(IdsQuery)
+	case "match_all":
+		query = new(MatchAllQuery)
+	case "match_none":
+		query = new(MatchNoneQuery)
+	case "knn":
+		query = new(KnnQuery)
+	case "geo_distance":
+		query = new(GeoDistanceQuery)
+	case "geo_bounding_box":
+		query = new(GeoBoundingBoxQuery)
+	case "geo_polygon":
+		query = new(GeoPolygonQuery)
 	default:
 		return nil, fmt.Errorf("unknown query type %q", queryType.Type)
 	}
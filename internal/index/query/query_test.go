@@ -2,8 +2,12 @@ package query
 
 import (
 	"bytes"
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 )
@@ -102,4 +106,251 @@ func Test_Build(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, result)
 	})
+
+	t.Run("must not return error if query is a valid prefix query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "prefix",
+			"field": "field",
+			"query": "query"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid wildcard query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "wildcard",
+			"field": "field",
+			"query": "qu*"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid regexp query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "regexp",
+			"field": "field",
+			"query": "qu.*"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid exists query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "exists",
+			"field": "field"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid ids query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "ids",
+			"values": ["guid"]
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid match_all query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "match_all"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid match_none query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "match_none"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid multi_match query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "multi_match",
+			"fields": ["title^3", "description"],
+			"query": "query"
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid constant_score query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "constant_score",
+			"filter": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			}
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid dis_max query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "dis_max",
+			"queries": [
+				{
+					"type": "term",
+					"field": "field",
+					"query": "query"
+				}
+			]
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("must not return error if query is a valid boosting query", func(t *testing.T) {
+		query := []byte(`{
+			"type": "boosting",
+			"positive": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negative": {
+				"type": "term",
+				"field": "field",
+				"query": "query"
+			},
+			"negativeBoost": 0.5
+		}`)
+
+		result, err := Build(query)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	})
+}
+
+func Test_Result_Explain(t *testing.T) {
+	ctx := context.Background()
+
+	text, err := field.New(schema.TypeText, field.FieldOpts{
+		Analyzer: func(s []string) []string {
+			var result []string
+			for _, str := range s {
+				result = append(result, strings.Fields(str)...)
+			}
+			return result
+		},
+		Scoring: field.NewScoring(),
+	})
+	require.NoError(t, err)
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	all, err := field.New(schema.TypeAll)
+	require.NoError(t, err)
+
+	text.Add(1, "foo bar")
+	text.Add(2, "foo foo baz")
+	text.Add(3, "baz")
+	keyword.Add(1, "a")
+	keyword.Add(2, "b")
+	for _, id := range []uint32{1, 2, 3} {
+		all.Add(id, true)
+	}
+	fields := Fields{"text": text, "keyword": keyword, field.AllField: all}
+
+	queries := map[string]string{
+		"term":  `{"type": "term", "field": "keyword", "query": "a", "boost": 2}`,
+		"match": `{"type": "match", "field": "text", "query": "foo baz"}`,
+		"bool": `{
+			"type": "bool",
+			"must": [{"type": "match", "field": "text", "query": "foo"}],
+			"should": [
+				{"type": "match", "field": "text", "query": "bar"},
+				{"type": "term", "field": "keyword", "query": "b"}
+			],
+			"boost": 1.5
+		}`,
+		"dis_max": `{
+			"type": "dis_max",
+			"queries": [
+				{"type": "match", "field": "text", "query": "foo"},
+				{"type": "match", "field": "text", "query": "baz"}
+			],
+			"tieBreaker": 0.3
+		}`,
+		"boosting": `{
+			"type": "boosting",
+			"positive": {"type": "match", "field": "text", "query": "foo baz"},
+			"negative": {"type": "term", "field": "keyword", "query": "b"},
+			"negativeBoost": 0.5
+		}`,
+		"constant_score": `{"type": "constant_score", "filter": {"type": "term", "field": "keyword", "query": "a"}, "boost": 3}`,
+		"function_score": `{
+			"type": "function_score",
+			"query": {"type": "match", "field": "text", "query": "foo"},
+			"functions": [{"filter": {"type": "term", "field": "keyword", "query": "b"}, "weight": 2}]
+		}`,
+		"multi_match": `{"type": "multi_match", "fields": ["text^2", "keyword"], "query": "foo a", "mode": "cross_fields"}`,
+	}
+
+	for name, src := range queries {
+		t.Run(name+" explanation value must be equal to score", func(t *testing.T) {
+			q, err := Build(QueryRequest(src))
+			require.NoError(t, err)
+
+			result, err := q.Exec(ctx, fields)
+			require.NoError(t, err)
+			require.False(t, result.Docs().IsEmpty())
+
+			for _, id := range []uint32{1, 2, 3} {
+				e := result.Explain(id)
+				require.InDelta(t, result.Score(id), e.Value, 1e-9, "document %d", id)
+				if !result.Docs().Contains(id) {
+					require.Equal(t, field.NoMatchExplanation(), e)
+				}
+			}
+		})
+	}
+
+	t.Run("explanation must follow query tree", func(t *testing.T) {
+		q, err := Build(QueryRequest(queries["bool"]))
+		require.NoError(t, err)
+
+		result, err := q.Exec(ctx, fields)
+		require.NoError(t, err)
+
+		e := result.Explain(1)
+		require.Equal(t, "product of:", e.Description)
+		require.Equal(t, 1.5, e.Details[1].Value)
+
+		b := e.Details[0]
+		require.Equal(t, "bool, sum of:", b.Description)
+		require.Len(t, b.Details, 3)
+		require.Equal(t, "match text:foo, sum of:", b.Details[0].Description)
+		require.Equal(t, "match text:bar, sum of:", b.Details[1].Description)
+		require.Equal(t, "term keyword:b, sum of:", b.Details[2].Description)
+		require.Equal(t, field.NoMatchExplanation(), b.Details[2].Details[0])
+	})
 }
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/spf13/cast"
@@ -15,6 +16,7 @@ type RangeQuery struct {
 	To          interface{} `json:"to"`
 	IncludeFrom bool        `json:"includeFrom"`
 	IncludeTo   bool        `json:"includeTo"`
+	Boost       *float64    `json:"boost"`
 }
 
 func (q *RangeQuery) Validate() error {
@@ -28,6 +30,7 @@ func (q *RangeQuery) Validate() error {
 			_, err := cast.ToFloat64E(value)
 			return err
 		})),
+		validation.Field(&q.Boost, validation.Min(0.0)),
 	)
 }
 
@@ -37,5 +40,7 @@ func (q *RangeQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
 		return NewEmptyResult(), nil
 	}
 
-	return NewResult(field.RangeQuery(ctx, q.From, q.To, q.IncludeTo, q.IncludeFrom)), nil
+	result := NewResult(field.RangeQuery(ctx, q.From, q.To, q.IncludeTo, q.IncludeFrom).WithOpts(ctx, boostOpts(q.Boost)...))
+
+	return withDescription(result, fmt.Sprintf("range %s", q.Field)), nil
 }
@@ -0,0 +1,183 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/field"
+)
+
+// ScoreNormalizer brings scores of different results to the same scale before linear combination
+type ScoreNormalizer string
+
+const (
+	// NormalizerMinMax maps scores to [0, 1]: (score - min) / (max - min)
+	NormalizerMinMax ScoreNormalizer = "minmax"
+	// NormalizerL2Norm divides scores by the square root of their squares sum
+	NormalizerL2Norm ScoreNormalizer = "l2_norm"
+)
+
+// rankedDoc is a document of a ranked list. Ranks start with 1
+type rankedDoc struct {
+	id    uint32
+	score float64
+	rank  int
+}
+
+// rankDocs returns up to size top scored documents of the result. Documents with equal scores are ordered by id
+func rankDocs(r Result, size int) []rankedDoc {
+	docs := make([]rankedDoc, 0, r.Docs().GetCardinality())
+	r.Docs().Iterate(func(id uint32) bool {
+		docs = append(docs, rankedDoc{id: id, score: r.Score(id)})
+		return true
+	})
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].score > docs[j].score
+	})
+
+	if len(docs) > size {
+		docs = docs[:size]
+	}
+	for i := range docs {
+		docs[i].rank = i + 1
+	}
+
+	return docs
+}
+
+// FuseRRF combines results with reciprocal rank fusion. Top windowSize documents of every result are taken,
+// document score is the sum of 1 / (rankConstant + rank) over the results the document is ranked in
+func FuseRRF(results []Result, windowSize int, rankConstant int) Result {
+	fs := newFusedScorer("reciprocal rank fusion, sum of:")
+	for i, r := range results {
+		for _, d := range rankDocs(r, windowSize) {
+			i, r, d := i, r, d
+			score := 1 / float64(rankConstant+d.rank)
+			fs.add(d.id, score, func() field.Explanation {
+				return field.NewExplanation(
+					score,
+					fmt.Sprintf("1 / (%d + rank %d) in result %d, rank by score of:", rankConstant, d.rank, i),
+					r.Explain(d.id),
+				)
+			})
+		}
+	}
+
+	return fs.result()
+}
+
+// FuseLinear combines results by weighted sum of their normalized scores. Top windowSize documents of every result are taken,
+// scores are normalized by these documents. Every result has weight 1 if weights are not provided
+func FuseLinear(results []Result, weights []float64, normalizer ScoreNormalizer, windowSize int) Result {
+	fs := newFusedScorer("weighted sum of normalized scores:")
+	for i, r := range results {
+		weight := 1.0
+		if len(weights) > i {
+			weight = weights[i]
+		}
+
+		docs := rankDocs(r, windowSize)
+		normalize := scoreNormalizer(normalizer, docs)
+		for _, d := range docs {
+			i, r, d := i, r, d
+			score := normalize(d.score)
+			fs.add(d.id, weight*score, func() field.Explanation {
+				return field.NewExplanation(
+					weight*score,
+					fmt.Sprintf("product of weight and %s normalized score in result %d:", normalizer, i),
+					field.NewExplanation(weight, "weight"),
+					field.NewExplanation(score, fmt.Sprintf("%s normalized score of:", normalizer), r.Explain(d.id)),
+				)
+			})
+		}
+	}
+
+	return fs.result()
+}
+
+// scoreNormalizer returns the function normalizing scores of the ranked documents
+func scoreNormalizer(normalizer ScoreNormalizer, docs []rankedDoc) func(score float64) float64 {
+	if len(docs) == 0 {
+		return func(score float64) float64 { return score }
+	}
+
+	switch normalizer {
+	case NormalizerL2Norm:
+		var sum float64
+		for _, d := range docs {
+			sum += d.score * d.score
+		}
+		norm := math.Sqrt(sum)
+		return func(score float64) float64 {
+			if norm == 0 {
+				return 0
+			}
+			return score / norm
+		}
+	default:
+		max, min := docs[0].score, docs[len(docs)-1].score
+		return func(score float64) float64 {
+			if max == min {
+				return 1
+			}
+			return (score - min) / (max - min)
+		}
+	}
+}
+
+// fusedScorer scores documents by the sum of their scores in the combined results.
+// Explanations are built on demand as they require source results to explain documents
+type fusedScorer struct {
+	docs        *roaring.Bitmap
+	parts       map[uint32][]fusedPart
+	description string
+}
+
+type fusedPart struct {
+	score   float64
+	explain func() field.Explanation
+}
+
+func newFusedScorer(description string) *fusedScorer {
+	return &fusedScorer{
+		docs:        roaring.New(),
+		parts:       make(map[uint32][]fusedPart),
+		description: description,
+	}
+}
+
+func (s *fusedScorer) add(id uint32, score float64, explain func() field.Explanation) {
+	s.docs.Add(id)
+	s.parts[id] = append(s.parts[id], fusedPart{score: score, explain: explain})
+}
+
+func (s *fusedScorer) result() Result {
+	return Result{
+		docs:    s.docs,
+		results: []scorer{s},
+	}
+}
+
+func (s *fusedScorer) Score(id uint32) float64 {
+	var result float64
+	for _, p := range s.parts[id] {
+		result += p.score
+	}
+
+	return result
+}
+
+func (s *fusedScorer) Explain(id uint32) field.Explanation {
+	if !s.docs.Contains(id) {
+		return field.NoMatchExplanation()
+	}
+
+	details := make([]field.Explanation, len(s.parts[id]))
+	for i, p := range s.parts[id] {
+		details[i] = p.explain()
+	}
+
+	return field.SumExplanation(s.description, details...)
+}
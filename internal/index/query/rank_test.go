@@ -0,0 +1,107 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/stretchr/testify/require"
+)
+
+// newScoredResult returns result scoring documents by the given scores
+func newScoredResult(scores map[uint32]float64) Result {
+	result := NewEmptyResult()
+	for id, score := range scores {
+		result.Or(newConstantResult(roaring.BitmapOf(id), score))
+	}
+
+	return result
+}
+
+func Test_rankDocs(t *testing.T) {
+	r := newScoredResult(map[uint32]float64{1: 1, 2: 3, 3: 2, 4: 3})
+
+	require.Equal(t, []rankedDoc{
+		{id: 2, score: 3, rank: 1},
+		{id: 4, score: 3, rank: 2},
+		{id: 3, score: 2, rank: 3},
+	}, rankDocs(r, 3))
+	require.Len(t, rankDocs(r, 10), 4)
+}
+
+func Test_FuseRRF(t *testing.T) {
+	lexical := newScoredResult(map[uint32]float64{1: 10, 2: 5, 3: 1})
+	vector := newScoredResult(map[uint32]float64{3: 0.9, 4: 0.8, 1: 0.1})
+
+	t.Run("must sum reciprocal ranks", func(t *testing.T) {
+		result := FuseRRF([]Result{lexical, vector}, 10, 60)
+
+		require.Equal(t, []uint32{1, 2, 3, 4}, result.Docs().ToArray())
+		require.InDelta(t, 1.0/61+1.0/63, result.Score(1), 1e-12)
+		require.InDelta(t, 1.0/62, result.Score(2), 1e-12)
+		require.InDelta(t, 1.0/63+1.0/61, result.Score(3), 1e-12)
+		require.InDelta(t, 1.0/62, result.Score(4), 1e-12)
+		require.Equal(t, 0.0, result.Score(5))
+
+		for _, id := range result.Docs().ToArray() {
+			e := result.Explain(id)
+			require.Equal(t, result.Score(id), e.Value)
+		}
+		require.Len(t, result.Explain(1).Details, 2)
+		require.Len(t, result.Explain(2).Details, 1)
+	})
+
+	t.Run("must take only top documents of every result", func(t *testing.T) {
+		result := FuseRRF([]Result{lexical, vector}, 1, 1)
+
+		require.Equal(t, []uint32{1, 3}, result.Docs().ToArray())
+		require.Equal(t, 0.5, result.Score(1))
+		require.Equal(t, 0.5, result.Score(3))
+	})
+
+	t.Run("must handle empty results", func(t *testing.T) {
+		result := FuseRRF([]Result{NewEmptyResult(), vector}, 10, 60)
+		require.Equal(t, []uint32{1, 3, 4}, result.Docs().ToArray())
+		require.Equal(t, field.NoMatchExplanation(), result.Explain(2))
+	})
+}
+
+func Test_FuseLinear(t *testing.T) {
+	lexical := newScoredResult(map[uint32]float64{1: 10, 2: 5, 3: 0})
+	vector := newScoredResult(map[uint32]float64{3: 0.9, 4: 0.5})
+
+	t.Run("must sum min-max normalized scores", func(t *testing.T) {
+		result := FuseLinear([]Result{lexical, vector}, nil, NormalizerMinMax, 10)
+
+		require.Equal(t, []uint32{1, 2, 3, 4}, result.Docs().ToArray())
+		require.InDelta(t, 1.0, result.Score(1), 1e-12)
+		require.InDelta(t, 0.5, result.Score(2), 1e-12)
+		require.InDelta(t, 1.0, result.Score(3), 1e-12)
+		require.InDelta(t, 0.0, result.Score(4), 1e-12)
+
+		for _, id := range result.Docs().ToArray() {
+			require.Equal(t, result.Score(id), result.Explain(id).Value)
+		}
+	})
+
+	t.Run("must apply weights", func(t *testing.T) {
+		result := FuseLinear([]Result{lexical, vector}, []float64{0.2, 2}, NormalizerMinMax, 10)
+
+		require.InDelta(t, 0.2, result.Score(1), 1e-12)
+		require.InDelta(t, 2.0, result.Score(3), 1e-12)
+	})
+
+	t.Run("must sum l2 normalized scores", func(t *testing.T) {
+		result := FuseLinear([]Result{newScoredResult(map[uint32]float64{1: 3, 2: 4})}, nil, NormalizerL2Norm, 10)
+
+		require.InDelta(t, 0.6, result.Score(1), 1e-12)
+		require.InDelta(t, 0.8, result.Score(2), 1e-12)
+	})
+
+	t.Run("must score all documents with 1 if their scores are equal", func(t *testing.T) {
+		result := FuseLinear([]Result{newScoredResult(map[uint32]float64{1: 3, 2: 3})}, nil, NormalizerMinMax, 10)
+
+		require.Equal(t, 1.0, result.Score(1))
+		require.Equal(t, 1.0, result.Score(2))
+	})
+}
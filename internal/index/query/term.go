@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/spf13/cast"
@@ -13,6 +14,7 @@ var _ Query = (*TermsQuery)(nil)
 type TermQuery struct {
 	Field string      `json:"field"`
 	Query interface{} `json:"query"`
+	Boost *float64    `json:"boost"`
 }
 
 func (q *TermQuery) Validate() error {
@@ -22,6 +24,7 @@ func (q *TermQuery) Validate() error {
 			_, err := cast.ToStringE(value)
 			return err
 		})),
+		validation.Field(&q.Boost, validation.Min(0.0)),
 	)
 }
 
@@ -31,12 +34,17 @@ func (q *TermQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
 		return NewEmptyResult(), nil
 	}
 
-	return NewResult(field.TermQuery(ctx, q.Query)), nil
+	fr := field.TermQuery(ctx, q.Query).WithOpts(ctx, boostOpts(q.Boost)...)
+	addTokens(ctx, q.Field, fr.Tokens())
+	result := NewResult(fr)
+
+	return withDescription(result, fmt.Sprintf("term %s:%v", q.Field, q.Query)), nil
 }
 
 type TermsQuery struct {
 	Field string        `json:"field"`
 	Query []interface{} `json:"query"`
+	Boost *float64      `json:"boost"`
 }
 
 func (q *TermsQuery) Validate() error {
@@ -46,6 +54,7 @@ func (q *TermsQuery) Validate() error {
 			_, err := cast.ToStringE(value)
 			return err
 		}))),
+		validation.Field(&q.Boost, validation.Min(0.0)),
 	)
 }
 
@@ -55,15 +64,12 @@ func (q *TermsQuery) Exec(ctx context.Context, fields Fields) (Result, error) {
 		return NewEmptyResult(), nil
 	}
 
-	var result Result
+	result := NewEmptyResult()
 	for _, v := range q.Query {
-		r := NewResult(field.TermQuery(ctx, v))
-		if result.IsEmpty() {
-			result = r
-			continue
-		}
-		result.Or(result)
+		fr := field.TermQuery(ctx, v).WithOpts(ctx, boostOpts(q.Boost)...)
+		addTokens(ctx, q.Field, fr.Tokens())
+		result.Or(NewResult(fr))
 	}
 
-	return result, nil
+	return withDescription(result, fmt.Sprintf("terms %s:%v", q.Field, q.Query)), nil
 }
@@ -99,6 +99,19 @@ func Test_TermQuery_Exec(t *testing.T) {
 		require.False(t, result.Docs().IsEmpty())
 		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
 	})
+
+	t.Run("must apply boost", func(t *testing.T) {
+		query := new(TermQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": "value",
+			"boost": 2.5
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.Equal(t, 2.5, result.Score(1))
+	})
 }
 
 func Test_TermsQuery_Validate(t *testing.T) {
@@ -200,4 +213,24 @@ func Test_TermsQuery_Exec(t *testing.T) {
 		require.False(t, result.Docs().IsEmpty())
 		require.ElementsMatch(t, []uint32{1}, result.Docs().ToArray())
 	})
+
+	t.Run("must return documents matching any value", func(t *testing.T) {
+		f, err := field.New(schema.TypeKeyword)
+		require.NoError(t, err)
+		f.Add(1, "value1")
+		f.Add(2, "value2")
+		f.Add(3, "value3")
+
+		query := new(TermsQuery)
+		mustUnmarshal(t, `{
+			"field": "field",
+			"query": ["value1", "value2"],
+			"boost": 2
+		}`, query)
+
+		result, err := query.Exec(context.Background(), Fields{"field": f})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint32{1, 2}, result.Docs().ToArray())
+		require.Equal(t, 2.0, result.Score(1))
+	})
 }
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Tokens contains tokens of the executed queries by field names, e.g. to highlight them
+type Tokens struct {
+	mtx    sync.Mutex
+	fields map[string]map[string]struct{}
+}
+
+type tokensKey struct{}
+
+// CollectTokens enables tokens collection. Tokens of queries executed with the returned context are added to the returned value
+func CollectTokens(ctx context.Context) (context.Context, *Tokens) {
+	t := &Tokens{fields: make(map[string]map[string]struct{})}
+	return context.WithValue(ctx, tokensKey{}, t), t
+}
+
+func addTokens(ctx context.Context, field string, tokens []string) {
+	t, ok := ctx.Value(tokensKey{}).(*Tokens)
+	if !ok || len(tokens) == 0 {
+		return
+	}
+
+	// child queries may be executed in parallel
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.fields[field] == nil {
+		t.fields[field] = make(map[string]struct{})
+	}
+	for _, token := range tokens {
+		t.fields[field][token] = struct{}{}
+	}
+}
+
+// Field returns sorted field tokens
+func (t *Tokens) Field(name string) []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	result := make([]string, 0, len(t.fields[name]))
+	for token := range t.fields[name] {
+		result = append(result, token)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,38 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CollectTokens(t *testing.T) {
+	f, err := field.New(schema.TypeText, field.FieldOpts{
+		Analyzer: func(s []string) []string { return strings.Fields(strings.ToLower(s[0])) },
+		Scoring:  field.NewScoring(),
+	})
+	require.NoError(t, err)
+	f.Add(1, "foo bar")
+	fields := Fields{"text": f}
+
+	q := new(BoolQuery)
+	mustUnmarshal(t, `{
+		"should": [
+			{"type": "match", "field": "text", "query": "Foo Baz"},
+			{"type": "term", "field": "text", "query": "bar"},
+			{"type": "multi_match", "fields": ["text", "unknown"], "query": "qux"}
+		],
+		"parallel": true
+	}`, q)
+
+	ctx, tokens := CollectTokens(context.Background())
+	_, err = Exec(ctx, q, fields)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"bar", "baz", "foo", "qux"}, tokens.Field("text"))
+	require.Empty(t, tokens.Field("unknown"))
+}
@@ -0,0 +1,110 @@
+package index
+
+import (
+	"bytes"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/query"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	defaultRankWindowSize   = 100
+	defaultRankRankConstant = 60
+)
+
+// Rank combines ranked lists of the query, sub-searches and kNN searches into one. Exactly one mode must be provided
+type Rank struct {
+	RRF    *RRFRank    `json:"rrf"`
+	Linear *LinearRank `json:"linear"`
+}
+
+func (r Rank) Validate() error {
+	if (r.RRF == nil) == (r.Linear == nil) {
+		return errs.Errorf("exactly one of rrf or linear must be provided")
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RRF),
+		validation.Field(&r.Linear),
+	)
+}
+
+// RRFRank reciprocal rank fusion. Top windowSize documents of every list are scored by sum of 1 / (rankConstant + rank)
+type RRFRank struct {
+	WindowSize   *int `json:"windowSize"`
+	RankConstant *int `json:"rankConstant"`
+}
+
+func (r RRFRank) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.WindowSize, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&r.RankConstant, validation.NilOrNotEmpty, validation.Min(1)),
+	)
+}
+
+// LinearRank scores top windowSize documents of every list by weighted sum of their normalized scores.
+// Weights are given in lists order: the query, sub-searches, kNN searches. They are equal to 1 if not provided
+type LinearRank struct {
+	WindowSize *int                  `json:"windowSize"`
+	Weights    []float64             `json:"weights"`
+	Normalizer query.ScoreNormalizer `json:"normalizer"`
+}
+
+func (r LinearRank) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.WindowSize, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&r.Weights, validation.Each(validation.Min(0.0))),
+		validation.Field(&r.Normalizer, validation.In(query.NormalizerMinMax, query.NormalizerL2Norm)),
+	)
+}
+
+// fuse combines the results by the rank mode
+func (r Rank) fuse(results []query.Result) query.Result {
+	if r.RRF != nil {
+		return query.FuseRRF(results, intOrDefault(r.RRF.WindowSize, defaultRankWindowSize), intOrDefault(r.RRF.RankConstant, defaultRankRankConstant))
+	}
+
+	normalizer := r.Linear.Normalizer
+	if normalizer == "" {
+		normalizer = query.NormalizerMinMax
+	}
+
+	return query.FuseLinear(results, r.Linear.Weights, normalizer, intOrDefault(r.Linear.WindowSize, defaultRankWindowSize))
+}
+
+func intOrDefault(v *int, def int) int {
+	if v == nil {
+		return def
+	}
+
+	return *v
+}
+
+// KnnSearches are kNN searches. A single search object or a list of them is accepted
+type KnnSearches []*query.KnnQuery
+
+func (k *KnnSearches) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*k = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var result []*query.KnnQuery
+		if err := jsoniter.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		*k = result
+		return nil
+	}
+
+	q := new(query.KnnQuery)
+	if err := jsoniter.Unmarshal(data, q); err != nil {
+		return err
+	}
+	*k = KnnSearches{q}
+
+	return nil
+}
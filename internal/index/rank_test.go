@@ -0,0 +1,151 @@
+package index
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Search_Validate_Rank(t *testing.T) {
+	invalid := map[string]string{
+		"rank mode is not provided":          `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {}}`,
+		"both rank modes are provided":       `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"rrf": {}, "linear": {}}}`,
+		"window size is invalid":             `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"rrf": {"windowSize": 0}}}`,
+		"rank constant is invalid":           `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"rrf": {"rankConstant": -1}}}`,
+		"normalizer is invalid":              `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"linear": {"normalizer": "invalid"}}}`,
+		"weight is negative":                 `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"linear": {"weights": [1, -1]}}}`,
+		"number of weights is invalid":       `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"linear": {"weights": [1]}}}`,
+		"there is only one list to rank":     `{"query": {"type": "match_all"}, "rank": {"rrf": {}}}`,
+		"sub-searches are used without rank": `{"query": {"type": "match_all"}, "subSearches": [{"type": "match_all"}]}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			var s Search
+			require.NoError(t, jsoniter.Unmarshal([]byte(req), &s))
+			require.Error(t, s.Validate())
+		})
+	}
+
+	valid := map[string]string{
+		"rrf":              `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"rrf": {"windowSize": 10, "rankConstant": 1}}}`,
+		"linear":           `{"query": {"type": "match_all"}, "knn": {"field": "v", "queryVector": [1]}, "rank": {"linear": {"weights": [1, 0.5], "normalizer": "l2_norm"}}}`,
+		"knn list":         `{"knn": [{"field": "v", "queryVector": [1]}, {"field": "v2", "queryVector": [2]}], "rank": {"rrf": {}}}`,
+		"sub-searches":     `{"subSearches": [{"type": "match_all"}, {"type": "match_none"}], "rank": {"rrf": {}}}`,
+		"knn without rank": `{"knn": [{"field": "v", "queryVector": [1]}, {"field": "v2", "queryVector": [2]}]}`,
+	}
+	for name, req := range valid {
+		t.Run("must not return error for "+name, func(t *testing.T) {
+			var s Search
+			require.NoError(t, jsoniter.Unmarshal([]byte(req), &s))
+			require.NoError(t, s.Validate())
+		})
+	}
+}
+
+func Test_KnnSearches_UnmarshalJSON(t *testing.T) {
+	var s Search
+	require.NoError(t, jsoniter.Unmarshal([]byte(`{"knn": {"field": "v", "queryVector": [1]}}`), &s))
+	require.Len(t, s.Knn, 1)
+	require.Equal(t, "v", s.Knn[0].Field)
+
+	require.NoError(t, jsoniter.Unmarshal([]byte(`{"knn": [{"field": "v1", "queryVector": [1]}, {"field": "v2", "queryVector": [2]}]}`), &s))
+	require.Len(t, s.Knn, 2)
+	require.Equal(t, "v2", s.Knn[1].Field)
+
+	s = Search{}
+	require.NoError(t, jsoniter.Unmarshal([]byte(`{"knn": null}`), &s))
+	require.Nil(t, s.Knn)
+}
+
+func Test_Documents_Search_Rank(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"vector": {Type: schema.TypeDenseVector, Dims: 2, Similarity: schema.Similarity{Type: schema.SimilarityL2Norm}},
+				"text":   schema.NewField(schema.TypeText, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	sources := []string{
+		`{"text": "red apple", "vector": [5, 5]}`,
+		`{"text": "red red apple", "vector": [1, 0]}`,
+		`{"text": "green pear", "vector": [0, 0]}`,
+		`{"text": "red car", "vector": [9, 9]}`,
+	}
+	guids := make(map[uint32]string)
+	for _, src := range sources {
+		var source DocSource
+		dec := jsoniter.NewDecoder(strings.NewReader(src))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&source))
+
+		guid, err := docs.Add(i, "", source)
+		require.NoError(t, err)
+		guids[docs.ids.ID(guid)] = guid
+	}
+
+	search := func(t *testing.T, req string) SearchResult {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(req), &q))
+		require.NoError(t, q.Validate())
+
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		return result
+	}
+	scores := func(result SearchResult) map[string]float64 {
+		scores := make(map[string]float64)
+		for _, h := range result.Hits.Hits {
+			scores[guids[h.ID]] = h.Score
+		}
+		return scores
+	}
+
+	t.Run("must fuse lexical and knn results with rrf", func(t *testing.T) {
+		result := search(t, `{
+			"query": {"type": "match", "field": "text", "query": "red"},
+			"knn": {"field": "vector", "queryVector": [0, 0], "k": 2},
+			"rank": {"rrf": {"windowSize": 10, "rankConstant": 1}},
+			"explain": true
+		}`)
+
+		require.Equal(t, 4, result.Hits.Total.Value)
+		s := scores(result)
+		// lexical ranks: doc2, doc1 or doc4; knn ranks: doc3, doc2
+		require.InDelta(t, 1.0/2+1.0/3, s[guids[2]], 1e-9)
+		require.InDelta(t, 1.0/2, s[guids[3]], 1e-9)
+		require.InDelta(t, 1.0/3+1.0/4, s[guids[1]]+s[guids[4]], 1e-9)
+
+		for _, h := range result.Hits.Hits {
+			require.InDelta(t, h.Score, h.Explanation.Value, 1e-12)
+		}
+	})
+
+	t.Run("must fuse sub-searches with linear combination", func(t *testing.T) {
+		result := search(t, `{
+			"subSearches": [
+				{"type": "match", "field": "text", "query": "apple"},
+				{"type": "match", "field": "text", "query": "pear"}
+			],
+			"knn": {"field": "vector", "queryVector": [0, 0], "k": 4},
+			"rank": {"linear": {"weights": [1, 1, 2]}}
+		}`)
+
+		require.Equal(t, 4, result.Hits.Total.Value)
+		s := scores(result)
+		// pear is the only document of the second list, doc3 is the nearest neighbor
+		require.InDelta(t, 3.0, s[guids[3]], 1e-9)
+		require.InDelta(t, 0.0, s[guids[4]], 1e-9)
+	})
+}
@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"unicode/utf8"
+
+	"github.com/cyradin/search/internal/errs"
+)
+
+// AnalyzeStage contains tokens produced by a single char filter or analyzer of the chain
+type AnalyzeStage struct {
+	Type   string         `json:"type"`
+	Tokens []AnalyzeToken `json:"tokens"`
+}
+
+type AnalyzeToken struct {
+	Token       string `json:"token"`
+	Position    int    `json:"position"`
+	StartOffset int    `json:"startOffset"`
+	EndOffset   int    `json:"endOffset"`
+}
+
+// Analyze applies char filters and analyzers to the text one by one and returns tokens produced by each of them.
+// Offsets are character offsets of tokens in the source text. Tokenizers set positions and offsets of tokens and filters keep them,
+// so removed tokens (e.g. stop words) leave position gaps and changed tokens (e.g. stems) keep offsets of the source words.
+func (fa FieldAnalyzer) Analyze(text string, opts ...AnalyzerOpts) ([]AnalyzeStage, error) {
+	if len(fa.Analyzers) == 0 {
+		return nil, errs.Errorf("chain cannot be empty")
+	}
+
+	type stage struct {
+		t string
+		f TokenFunc
+	}
+	stages := make([]stage, 0, len(fa.CharFilters)+len(fa.Analyzers))
+	for _, c := range fa.CharFilters {
+		f, err := c.getTokenFunc()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage{t: string(c.Type), f: f})
+	}
+	for _, a := range fa.Analyzers {
+		f, err := a.getTokenFunc(opts...)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage{t: string(a.Type), f: f})
+	}
+
+	tokens := []Token{{Value: text, EndOffset: utf8.RuneCountInString(text)}}
+	result := make([]AnalyzeStage, 0, len(stages))
+	for _, s := range stages {
+		tokens = s.f(tokens)
+		result = append(result, AnalyzeStage{Type: s.t, Tokens: analyzeTokens(tokens)})
+	}
+
+	return result, nil
+}
+
+func analyzeTokens(tokens []Token) []AnalyzeToken {
+	result := make([]AnalyzeToken, len(tokens))
+	for i, t := range tokens {
+		result[i] = AnalyzeToken{
+			Token:       t.Value,
+			Position:    t.Position,
+			StartOffset: t.StartOffset,
+			EndOffset:   t.EndOffset,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FieldAnalyzer_Analyze(t *testing.T) {
+	t.Run("must fail if chain is empty", func(t *testing.T) {
+		result, err := FieldAnalyzer{}.Analyze("text")
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("must fail if analyzer is invalid", func(t *testing.T) {
+		result, err := FieldAnalyzer{Analyzers: []Analyzer{{Type: "invalid"}}}.Analyze("text")
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("must return tokens of each stage", func(t *testing.T) {
+		fa := FieldAnalyzer{
+			CharFilters: []CharFilter{NewCharFilter(CharFilterMapping, map[string]interface{}{"mappings": []string{"& => and"}})},
+			Analyzers: []Analyzer{
+				NewAnalyzer(TokenizerWhitespace, nil),
+				NewAnalyzer(FilterUppercase, nil),
+				NewAnalyzer(FilterStop, map[string]interface{}{"stopwords": []string{"AND"}}),
+			},
+		}
+		result, err := fa.Analyze("Сыр & сыр")
+		require.NoError(t, err)
+
+		require.Equal(t, []AnalyzeStage{
+			{
+				Type:   "mapping",
+				Tokens: []AnalyzeToken{{Token: "Сыр and сыр", Position: 0, StartOffset: 0, EndOffset: 9}},
+			},
+			{
+				Type: "whitespace",
+				Tokens: []AnalyzeToken{
+					{Token: "Сыр", Position: 0, StartOffset: 0, EndOffset: 3},
+					{Token: "and", Position: 1, StartOffset: 4, EndOffset: 5},
+					{Token: "сыр", Position: 2, StartOffset: 6, EndOffset: 9},
+				},
+			},
+			{
+				Type: "uppercase",
+				Tokens: []AnalyzeToken{
+					{Token: "СЫР", Position: 0, StartOffset: 0, EndOffset: 3},
+					{Token: "AND", Position: 1, StartOffset: 4, EndOffset: 5},
+					{Token: "СЫР", Position: 2, StartOffset: 6, EndOffset: 9},
+				},
+			},
+			{
+				Type: "stop",
+				Tokens: []AnalyzeToken{
+					{Token: "СЫР", Position: 0, StartOffset: 0, EndOffset: 3},
+					{Token: "СЫР", Position: 2, StartOffset: 6, EndOffset: 9},
+				},
+			},
+		}, result)
+	})
+
+	t.Run("must find offsets of overlapping and repeated tokens", func(t *testing.T) {
+		fa := FieldAnalyzer{Analyzers: []Analyzer{
+			NewAnalyzer(TokenizerNgram, map[string]interface{}{"minGram": 1, "maxGram": 2}),
+		}}
+		result, err := fa.Analyze("aab")
+		require.NoError(t, err)
+
+		offsets := make([][2]int, 0)
+		for _, token := range result[0].Tokens {
+			offsets = append(offsets, [2]int{token.StartOffset, token.EndOffset})
+		}
+		require.Equal(t, [][2]int{{0, 1}, {0, 2}, {1, 2}, {1, 3}, {2, 3}}, offsets)
+	})
+
+	t.Run("must keep offsets of repeated substrings and changed tokens", func(t *testing.T) {
+		fa := FieldAnalyzer{Analyzers: []Analyzer{
+			NewAnalyzer(TokenizerStandard, nil),
+			NewAnalyzer(FilterLowercase, nil),
+			NewAnalyzer(FilterStemmer, map[string]interface{}{"language": "english"}),
+			NewAnalyzer(FilterSynonym, map[string]interface{}{"synonyms": []string{"new york => ny"}}),
+		}}
+		result, err := fa.Analyze("scar car Cats in New York")
+		require.NoError(t, err)
+
+		require.Equal(t, []AnalyzeToken{
+			{Token: "scar", Position: 0, StartOffset: 0, EndOffset: 4},
+			{Token: "car", Position: 1, StartOffset: 5, EndOffset: 8},
+			{Token: "cat", Position: 2, StartOffset: 9, EndOffset: 13},
+			{Token: "in", Position: 3, StartOffset: 14, EndOffset: 16},
+			{Token: "ny", Position: 4, StartOffset: 17, EndOffset: 25},
+		}, result[len(result)-1].Tokens)
+	})
+
+	t.Run("must map offsets of char filtered text to the source text", func(t *testing.T) {
+		fa := FieldAnalyzer{
+			CharFilters: []CharFilter{
+				NewCharFilter(CharFilterHTMLStrip, nil),
+				NewCharFilter(CharFilterPatternReplace, map[string]interface{}{"pattern": "-", "replacement": ""}),
+			},
+			Analyzers: []Analyzer{
+				NewAnalyzer(TokenizerWhitespace, nil),
+				NewAnalyzer(FilterStop, nil),
+			},
+		}
+		result, err := fa.Analyze("<p>the <b>caf&eacute;</b> e-mail</p>")
+		require.NoError(t, err)
+
+		require.Equal(t, []AnalyzeToken{
+			{Token: "café", Position: 1, StartOffset: 10, EndOffset: 21},
+			{Token: "email", Position: 2, StartOffset: 26, EndOffset: 32},
+		}, result[len(result)-1].Tokens)
+	})
+}
@@ -1,11 +1,20 @@
 package schema
 
-import "github.com/cyradin/search/internal/errs"
+import (
+	"context"
+
+	"github.com/cyradin/search/internal/errs"
+)
 
 type AnalyzerFunc func([]string) []string
 type AnalyzerHandler func(next AnalyzerFunc) AnalyzerFunc
 type AnalyzerType string
 
+type AnalyzerOpts struct {
+	// DataDir is a directory to load analyzer files (e.g. synonyms) from
+	DataDir string
+}
+
 type Analyzer struct {
 	Type     AnalyzerType           `json:"type"`
 	Settings map[string]interface{} `json:"settings"`
@@ -23,8 +32,20 @@ func (a Analyzer) Validate() error {
 	return err
 }
 
+// ValidateWithContext validates the analyzer with options from the context, e.g. checks that its files exist in the data dir
+func (a Analyzer) ValidateWithContext(ctx context.Context) error {
+	opts, _ := ctx.Value("analyzerOpts").(AnalyzerOpts)
+	_, err := a.GetFunc(opts)
+	return err
+}
+
 // GetFunc get analyzer func by name
-func (a Analyzer) GetFunc() (AnalyzerFunc, error) {
+func (a Analyzer) GetFunc(opts ...AnalyzerOpts) (AnalyzerFunc, error) {
+	var o AnalyzerOpts
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
 	switch a.Type {
 	case Nop:
 		return NopFunc(), nil
@@ -34,27 +55,103 @@ func (a Analyzer) GetFunc() (AnalyzerFunc, error) {
 		return TokenizerWhitespaceFunc(), nil
 	case TokenizerRegexp:
 		return TokenizerRegexpFunc(a.Settings)
+	case TokenizerStandard:
+		return TokenizerStandardFunc(a.Settings)
+	case TokenizerUAXURLEmail:
+		return TokenizerUAXURLEmailFunc(a.Settings)
+	case TokenizerNgram:
+		return TokenizerNgramFunc(a.Settings)
+	case TokenizerEdgeNgram:
+		return TokenizerEdgeNgramFunc(a.Settings)
+	case FilterLowercase:
+		return FilterLowercaseFunc(), nil
+	case FilterUppercase:
+		return FilterUppercaseFunc(), nil
+	case FilterASCIIFolding:
+		return FilterASCIIFoldingFunc(), nil
+	case FilterTrim:
+		return FilterTrimFunc(), nil
+	case FilterLength:
+		return FilterLengthFunc(a.Settings)
+	case FilterStop:
+		return FilterStopFunc(a.Settings)
+	case FilterStemmer:
+		return FilterStemmerFunc(a.Settings)
+	case FilterSynonym:
+		return FilterSynonymFunc(a.Settings, o)
 	}
 
 	return nil, errs.Errorf("unknown type %q", a.Type)
 }
 
+// getTokenFunc get analyzer token func by name. Tokenizers set positions and offsets of the tokens they produce, filters keep them
+func (a Analyzer) getTokenFunc(opts ...AnalyzerOpts) (TokenFunc, error) {
+	switch a.Type {
+	case Nop:
+		return func(tokens []Token) []Token { return tokens }, nil
+	case Dedup:
+		return dedupTokens(), nil
+	case TokenizerWhitespace:
+		return tokenize(splitWhitespace), nil
+	case TokenizerRegexp:
+		return tokenizer(regexpSplitter(a.Settings))
+	case TokenizerStandard:
+		return tokenizer(standardSplitter(a.Settings, false))
+	case TokenizerUAXURLEmail:
+		return tokenizer(standardSplitter(a.Settings, true))
+	case TokenizerNgram:
+		return tokenizer(ngramSplitter(a.Settings, false))
+	case TokenizerEdgeNgram:
+		return tokenizer(ngramSplitter(a.Settings, true))
+	case FilterSynonym:
+		var o AnalyzerOpts
+		if len(opts) > 0 {
+			o = opts[0]
+		}
+
+		f, err := newSynonymFilter(a.Settings, o)
+		if err != nil {
+			return nil, err
+		}
+		return f.tokenFunc(), nil
+	}
+
+	f, err := a.GetFunc(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return perToken(f), nil
+}
+
 const (
-	Nop                 AnalyzerType = "nop"
-	Dedup               AnalyzerType = "dedup"
-	TokenizerWhitespace AnalyzerType = "whitespace"
-	TokenizerRegexp     AnalyzerType = "regexp"
+	Nop                  AnalyzerType = "nop"
+	Dedup                AnalyzerType = "dedup"
+	TokenizerWhitespace  AnalyzerType = "whitespace"
+	TokenizerRegexp      AnalyzerType = "regexp"
+	TokenizerStandard    AnalyzerType = "standard"
+	TokenizerUAXURLEmail AnalyzerType = "uax_url_email"
+	TokenizerNgram       AnalyzerType = "ngram"
+	TokenizerEdgeNgram   AnalyzerType = "edge_ngram"
+	FilterLowercase      AnalyzerType = "lowercase"
+	FilterUppercase      AnalyzerType = "uppercase"
+	FilterASCIIFolding   AnalyzerType = "asciifolding"
+	FilterTrim           AnalyzerType = "trim"
+	FilterLength         AnalyzerType = "length"
+	FilterStop           AnalyzerType = "stop"
+	FilterStemmer        AnalyzerType = "stemmer"
+	FilterSynonym        AnalyzerType = "synonym"
 )
 
 // Chain build analyzer chain by their names
-func Chain(items []Analyzer) (AnalyzerFunc, error) {
+func Chain(items []Analyzer, opts ...AnalyzerOpts) (AnalyzerFunc, error) {
 	if len(items) == 0 {
 		return nil, errs.Errorf("chain cannot be empty")
 	}
 
 	var h AnalyzerFunc
 	for i := len(items) - 1; i >= 0; i-- {
-		f, err := items[i].GetFunc()
+		f, err := items[i].GetFunc(opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -42,4 +42,20 @@ func Test_Chain(t *testing.T) {
 		result := f([]string{"hello world", "hello", "world"})
 		require.Equal(t, []string{"hello", "world"}, result)
 	})
+
+	t.Run("can build chain of token filters", func(t *testing.T) {
+		f, err := Chain([]Analyzer{
+			{Type: TokenizerWhitespace},
+			{Type: FilterLowercase},
+			{Type: FilterASCIIFolding},
+			{Type: FilterStop},
+			{Type: FilterLength, Settings: map[string]interface{}{"min": 2}},
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		result := f([]string{"The Café is a Nice place"})
+		require.Equal(t, []string{"cafe", "nice", "place"}, result)
+	})
 }
@@ -0,0 +1,356 @@
+package schema
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cyradin/search/internal/errs"
+)
+
+type CharFilterType string
+
+const (
+	CharFilterHTMLStrip      CharFilterType = "html_strip"
+	CharFilterPatternReplace CharFilterType = "pattern_replace"
+	CharFilterMapping        CharFilterType = "mapping"
+)
+
+// CharFilter preprocesses values before they are passed to tokenizer
+type CharFilter struct {
+	Type     CharFilterType         `json:"type"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+func NewCharFilter(t CharFilterType, settings map[string]interface{}) CharFilter {
+	return CharFilter{
+		Type:     t,
+		Settings: settings,
+	}
+}
+
+func (c CharFilter) Validate() error {
+	_, err := c.GetFunc()
+	return err
+}
+
+// GetFunc get char filter func by name
+func (c CharFilter) GetFunc() (AnalyzerFunc, error) {
+	switch c.Type {
+	case CharFilterHTMLStrip:
+		return CharFilterHTMLStripFunc(c.Settings)
+	case CharFilterPatternReplace:
+		return CharFilterPatternReplaceFunc(c.Settings)
+	case CharFilterMapping:
+		return CharFilterMappingFunc(c.Settings)
+	}
+
+	return nil, errs.Errorf("unknown type %q", c.Type)
+}
+
+// getTokenFunc get char filter token func by name. Characters of the filtered tokens keep offsets of the source characters they are produced from
+func (c CharFilter) getTokenFunc() (TokenFunc, error) {
+	f, err := c.replacementsFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(tokens []Token) []Token {
+		result := make([]Token, len(tokens))
+		for i, t := range tokens {
+			result[i] = replaceTokenChars(t, f(t.Value))
+		}
+
+		return result
+	}, nil
+}
+
+func (c CharFilter) replacementsFunc() (charReplacementsFunc, error) {
+	switch c.Type {
+	case CharFilterHTMLStrip:
+		return htmlStripReplacements(c.Settings)
+	case CharFilterPatternReplace:
+		return patternReplacements(c.Settings)
+	case CharFilterMapping:
+		return mappingReplacements(c.Settings)
+	}
+
+	return nil, errs.Errorf("unknown type %q", c.Type)
+}
+
+// charReplacement replaces bytes start:end of the string with the value
+type charReplacement struct {
+	start int
+	end   int
+	value string
+}
+
+// charReplacementsFunc returns non-overlapping replacements of the string ordered by start
+type charReplacementsFunc func(s string) []charReplacement
+
+func (f charReplacementsFunc) analyzerFunc() AnalyzerFunc {
+	return func(s []string) []string {
+		result := make([]string, len(s))
+		for i, str := range s {
+			result[i] = replaceString(str, f(str))
+		}
+
+		return result
+	}
+}
+
+func replaceString(s string, replacements []charReplacement) string {
+	if len(replacements) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	pos := 0
+	for _, r := range replacements {
+		b.WriteString(s[pos:r.start])
+		b.WriteString(r.value)
+		pos = r.end
+	}
+	b.WriteString(s[pos:])
+
+	return b.String()
+}
+
+// replaceTokenChars replaces parts of the token value. Every replacement character gets offsets of the replaced source characters
+func replaceTokenChars(t Token, replacements []charReplacement) Token {
+	if len(replacements) == 0 {
+		return t
+	}
+
+	offsets := t.charOffsets()
+	chars := make([]charOffsets, 0, len(offsets))
+	pos, idx := 0, 0
+	for _, r := range replacements {
+		n := utf8.RuneCountInString(t.Value[pos:r.start])
+		chars = append(chars, offsets[idx:idx+n]...)
+		idx += n
+
+		// inserted characters get an empty span at the current position
+		replaced := utf8.RuneCountInString(t.Value[r.start:r.end])
+		span := charOffsets{start: t.EndOffset, end: t.EndOffset}
+		if idx < len(offsets) {
+			span = offsets[idx]
+		}
+		if replaced > 0 {
+			span.end = offsets[idx+replaced-1].end
+		} else {
+			span.end = span.start
+		}
+		for range r.value {
+			chars = append(chars, span)
+		}
+		idx += replaced
+		pos = r.end
+	}
+	chars = append(chars, offsets[idx:]...)
+
+	t.Value = replaceString(t.Value, replacements)
+	t.chars = chars
+
+	return t
+}
+
+// htmlTagRegexp matches comments, script and style elements with their contents (up to the end of the text if they are not closed),
+// tags and entities (see html.UnescapeString)
+var htmlTagRegexp = regexp.MustCompile(`(?is)<!--.*?-->|<script\b[^>]*>.*?(?:</script\s*>|$)|<style\b[^>]*>.*?(?:</style\s*>|$)|<(/?)([a-z][a-z0-9]*)\b[^>]*>|&(?:#[x]?[0-9a-f]*|[a-z][a-z0-9]*);?`)
+
+// html block elements are replaced with line breaks to keep words of adjacent blocks apart
+var htmlBlockTags = map[string]struct{}{
+	"address": {}, "article": {}, "aside": {}, "blockquote": {}, "br": {}, "dd": {}, "div": {}, "dl": {}, "dt": {},
+	"fieldset": {}, "figcaption": {}, "figure": {}, "footer": {}, "form": {}, "h1": {}, "h2": {}, "h3": {}, "h4": {},
+	"h5": {}, "h6": {}, "header": {}, "hr": {}, "li": {}, "main": {}, "nav": {}, "ol": {}, "p": {}, "pre": {},
+	"section": {}, "table": {}, "td": {}, "th": {}, "tr": {}, "ul": {},
+}
+
+// CharFilterHTMLStripFunc removes html tags and decodes html entities ("<b>caf&eacute;</b>" => "café").
+// Script and style elements are removed with their contents. Tags listed in "escapedTags" setting are kept.
+func CharFilterHTMLStripFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	f, err := htmlStripReplacements(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.analyzerFunc(), nil
+}
+
+func htmlStripReplacements(settings map[string]interface{}) (charReplacementsFunc, error) {
+	escaped := make(map[string]struct{})
+	for k, v := range settings {
+		if k != "escapedTags" {
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		tags, ok := toStringSlice(v)
+		if !ok {
+			return nil, errs.Errorf("%q must be a list of strings", k)
+		}
+		for _, tag := range tags {
+			escaped[strings.ToLower(tag)] = struct{}{}
+		}
+	}
+
+	// replace returns the replacement of the matched comment, element, tag or entity
+	replace := func(match string, name string) string {
+		switch {
+		case strings.HasPrefix(match, "&"):
+			return html.UnescapeString(match)
+		case name == "":
+			lower := strings.ToLower(match)
+			for _, element := range []string{"script", "style"} {
+				if _, ok := escaped[element]; ok && strings.HasPrefix(lower, "<"+element) {
+					return html.UnescapeString(match)
+				}
+			}
+			return ""
+		}
+
+		if _, ok := escaped[name]; ok {
+			return html.UnescapeString(match)
+		}
+		if _, ok := htmlBlockTags[name]; ok {
+			return "\n"
+		}
+
+		return ""
+	}
+
+	return func(s string) []charReplacement {
+		if !strings.ContainsAny(s, "<&") {
+			return nil
+		}
+
+		var result []charReplacement
+		for _, m := range htmlTagRegexp.FindAllStringSubmatchIndex(s, -1) {
+			match, name := s[m[0]:m[1]], ""
+			if m[4] >= 0 {
+				name = strings.ToLower(s[m[4]:m[5]])
+			}
+			if value := replace(match, name); value != match {
+				result = append(result, charReplacement{start: m[0], end: m[1], value: value})
+			}
+		}
+
+		return result
+	}, nil
+}
+
+// CharFilterPatternReplaceFunc replaces "pattern" regexp matches with "replacement" which may reference groups ("$1")
+func CharFilterPatternReplaceFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	f, err := patternReplacements(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.analyzerFunc(), nil
+}
+
+func patternReplacements(settings map[string]interface{}) (charReplacementsFunc, error) {
+	var pattern, replacement string
+	for k, v := range settings {
+		var ok bool
+		switch k {
+		case "pattern":
+			pattern, ok = v.(string)
+		case "replacement":
+			replacement, ok = v.(string)
+		default:
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		if !ok {
+			return nil, errs.Errorf("%q must be a string value", k)
+		}
+	}
+	if pattern == "" {
+		return nil, errs.Errorf("%q key must be provided", "pattern")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errs.Errorf("%q must be a valid regexp: %w", "pattern", err)
+	}
+
+	// matches are the same as replaced by regexp.ReplaceAllString
+	return func(s string) []charReplacement {
+		matches := re.FindAllStringSubmatchIndex(s, -1)
+		result := make([]charReplacement, len(matches))
+		for i, m := range matches {
+			value := re.ExpandString(nil, replacement, s, m)
+			result[i] = charReplacement{start: m[0], end: m[1], value: string(value)}
+		}
+
+		return result
+	}, nil
+}
+
+// CharFilterMappingFunc replaces substrings according to "mappings" setting ("ph => f").
+// The longest key is used if several keys match at the same position.
+func CharFilterMappingFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	f, err := mappingReplacements(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.analyzerFunc(), nil
+}
+
+func mappingReplacements(settings map[string]interface{}) (charReplacementsFunc, error) {
+	var mappings []string
+	for k, v := range settings {
+		if k != "mappings" {
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		var ok bool
+		mappings, ok = toStringSlice(v)
+		if !ok {
+			return nil, errs.Errorf("%q must be a list of strings", k)
+		}
+	}
+	if len(mappings) == 0 {
+		return nil, errs.Errorf("%q key must be provided", "mappings")
+	}
+
+	type mapping struct {
+		from string
+		to   string
+	}
+	items := make([]mapping, 0, len(mappings))
+	for _, m := range mappings {
+		from, to, ok := strings.Cut(m, "=>")
+		from = strings.TrimSpace(from)
+		if !ok || from == "" {
+			return nil, errs.Errorf("invalid mapping %q", m)
+		}
+		items = append(items, mapping{from: from, to: strings.TrimSpace(to)})
+	}
+
+	// the first matching key is used, so longer keys go first
+	sort.SliceStable(items, func(i, j int) bool { return len(items[i].from) > len(items[j].from) })
+
+	return func(s string) []charReplacement {
+		var result []charReplacement
+		for i := 0; i < len(s); {
+			matched := false
+			for _, item := range items {
+				if strings.HasPrefix(s[i:], item.from) {
+					result = append(result, charReplacement{start: i, end: i + len(item.from), value: item.to})
+					i += len(item.from)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				_, size := utf8.DecodeRuneInString(s[i:])
+				i += size
+			}
+		}
+
+		return result
+	}, nil
+}
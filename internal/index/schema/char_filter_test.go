@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CharFilter_GetFunc(t *testing.T) {
+	t.Run("cannot get func by invalid char filter type", func(t *testing.T) {
+		f, err := NewCharFilter("invalid", nil).GetFunc()
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must apply filter to every value", func(t *testing.T) {
+		f, err := NewCharFilter(CharFilterHTMLStrip, nil).GetFunc()
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, f([]string{"<i>a</i>", "<i>b</i>"}))
+	})
+}
+
+func Test_CharFilterHTMLStripFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := CharFilterHTMLStripFunc(map[string]interface{}{"extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must remove tags and decode entities", func(t *testing.T) {
+		f, err := CharFilterHTMLStripFunc(nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"\nI'm so happy &lt;3!\n"}, f([]string{`<p>I&apos;m <!-- comment -->so <b class="x">happy</b> &amp;lt;3!</p>`}))
+		require.Equal(t, []string{"one\ntwo"}, f([]string{"one<br/>two"}))
+		require.Equal(t, []string{"café"}, f([]string{"caf&eacute;"}))
+		require.Equal(t, []string{"a < b"}, f([]string{"a < b"}))
+	})
+
+	t.Run("must remove script and style elements with their contents", func(t *testing.T) {
+		f, err := CharFilterHTMLStripFunc(nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"before  after"}, f([]string{`before <script type="text/javascript">var x = "<b>";</script> after`}))
+		require.Equal(t, []string{"before  after"}, f([]string{"before <STYLE>\nbody { color: red }\n</Style > after"}))
+		require.Equal(t, []string{"before "}, f([]string{"before <script>alert(1)"}))
+		require.Equal(t, []string{"scripts"}, f([]string{"<scripts>scripts</scripts>"}))
+	})
+
+	t.Run("must keep escaped tags", func(t *testing.T) {
+		f, err := CharFilterHTMLStripFunc(map[string]interface{}{"escapedTags": []interface{}{"b"}})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"<b>bold</b> italic"}, f([]string{"<b>bold</b> <i>italic</i>"}))
+
+		f, err = CharFilterHTMLStripFunc(map[string]interface{}{"escapedTags": []interface{}{"script"}})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"<script>x</script>"}, f([]string{"<style>y</style><script>x</script>"}))
+	})
+}
+
+func Test_CharFilterPatternReplaceFunc(t *testing.T) {
+	t.Run("must return error if pattern is not provided", func(t *testing.T) {
+		f, err := CharFilterPatternReplaceFunc(map[string]interface{}{"replacement": "x"})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if pattern is invalid", func(t *testing.T) {
+		f, err := CharFilterPatternReplaceFunc(map[string]interface{}{"pattern": "("})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := CharFilterPatternReplaceFunc(map[string]interface{}{"pattern": "a", "extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must replace matches", func(t *testing.T) {
+		f, err := CharFilterPatternReplaceFunc(map[string]interface{}{"pattern": `(\d+)-(\d+)`, "replacement": "${1}_$2"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"123_456 and 7_8"}, f([]string{"123-456 and 7-8"}))
+	})
+}
+
+func Test_CharFilterMappingFunc(t *testing.T) {
+	t.Run("must return error if mappings are not provided", func(t *testing.T) {
+		f, err := CharFilterMappingFunc(nil)
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if mapping is invalid", func(t *testing.T) {
+		f, err := CharFilterMappingFunc(map[string]interface{}{"mappings": []string{"a -> b"}})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must replace the longest keys", func(t *testing.T) {
+		f, err := CharFilterMappingFunc(map[string]interface{}{"mappings": []interface{}{"p => b", "ph => f", ":) => _happy_"}})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"fone bet _happy_"}, f([]string{"phone pet :)"}))
+	})
+}
+
+func Test_FieldAnalyzer_Build(t *testing.T) {
+	t.Run("must apply char filters before analyzers", func(t *testing.T) {
+		fa := FieldAnalyzer{
+			CharFilters: []CharFilter{
+				NewCharFilter(CharFilterHTMLStrip, nil),
+				NewCharFilter(CharFilterMapping, map[string]interface{}{"mappings": []string{"& => and"}}),
+			},
+			Analyzers: []Analyzer{
+				NewAnalyzer(TokenizerWhitespace, nil),
+			},
+		}
+		f, err := fa.Build()
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"salt", "and", "pepper"}, f([]string{"<b>salt</b> &amp; pepper"}))
+	})
+
+	t.Run("must fail if char filter is invalid", func(t *testing.T) {
+		fa := FieldAnalyzer{
+			CharFilters: []CharFilter{NewCharFilter(CharFilterPatternReplace, nil)},
+			Analyzers:   []Analyzer{NewAnalyzer(TokenizerWhitespace, nil)},
+		}
+		f, err := fa.Build()
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+}
@@ -17,6 +17,15 @@ const (
 	TypeKeyword Type = "keyword"
 	TypeText    Type = "text"
 
+	// TypeCompletion contains weighted inputs used by completion suggester
+	TypeCompletion Type = "completion"
+
+	// TypeDenseVector contains fixed size float vectors used by kNN search
+	TypeDenseVector Type = "dense_vector"
+
+	// TypeGeoPoint contains latitude-longitude pairs used by geo queries and distance sorting
+	TypeGeoPoint Type = "geo_point"
+
 	TypeSlice Type = "slice"
 	TypeMap   Type = "map"
 
@@ -32,10 +41,16 @@ const (
 	TypeFloat  Type = "float"  // float32
 )
 
+// MaxVectorDims max number of dense vector dimensions
+const MaxVectorDims = 4096
+
 func (t Type) Valid() bool {
 	return t == TypeBool ||
 		t == TypeKeyword ||
 		t == TypeText ||
+		t == TypeCompletion ||
+		t == TypeDenseVector ||
+		t == TypeGeoPoint ||
 		t == TypeSlice ||
 		t == TypeMap ||
 		t == TypeUnsignedLong ||
@@ -52,6 +67,26 @@ type Field struct {
 	Required bool             `json:"required"`
 	Children map[string]Field `json:"children"`
 	Analyzer string           `json:"analyzer"`
+
+	// SearchAnalyzer is used to analyze query values. Analyzer is used if not provided
+	SearchAnalyzer string `json:"searchAnalyzer"`
+
+	// Normalizer is applied to keyword and completion values and query terms. It must produce a single token
+	Normalizer string `json:"normalizer"`
+
+	// Similarity defines how text field documents are scored or how dense vectors are compared
+	Similarity Similarity `json:"similarity"`
+
+	// Dims is the number of dense vector dimensions
+	Dims int `json:"dims"`
+
+	// Fields are sub-fields (multi-fields) indexed from the same source value, e.g. "title.raw"
+	Fields map[string]Field `json:"fields"`
+}
+
+// SubFieldName returns full sub-field name
+func SubFieldName(field string, subField string) string {
+	return field + "." + subField
 }
 
 func NewField(fieldType Type, required bool, analyzer string) Field {
@@ -77,14 +112,32 @@ func (f Field) ValidateWithContext(ctx context.Context) error {
 			return err
 		}
 	}
+	if f.Fields != nil {
+		if err := validateKeys("fields", f.Fields); err != nil {
+			return err
+		}
+	}
 
 	return validation.ValidateStructWithContext(ctx, &f,
 		validation.Field(&f.Type, validation.Required, validation.By(validateFieldType())),
 		validation.Field(
 			&f.Analyzer,
-			validation.When(f.Type == TypeText, validation.Required),
 			validation.WithContext(validateFieldAnalyzers(f.Type))),
+		validation.Field(
+			&f.SearchAnalyzer,
+			validation.When(f.Analyzer == "" && f.Type != TypeText, validation.Empty.Error("search analyzer requires analyzer to be defined")),
+			validation.WithContext(validateFieldAnalyzers(f.Type))),
+		validation.Field(
+			&f.Normalizer,
+			validation.When(f.Type != TypeKeyword && f.Type != TypeCompletion, validation.Empty.Error("normalizer is allowed for keyword and completion fields only")),
+			validation.WithContext(validateFieldNormalizer())),
 		validation.Field(&f.Children, validation.By(validateFieldChildren(f.Type))),
+		validation.Field(&f.Similarity, validation.By(validateFieldSimilarity(f.Type))),
+		validation.Field(
+			&f.Dims,
+			validation.When(f.Type == TypeDenseVector, validation.Required, validation.Min(1), validation.Max(MaxVectorDims)),
+			validation.When(f.Type != TypeDenseVector, validation.Empty.Error("dims is allowed for dense_vector fields only"))),
+		validation.Field(&f.Fields, validation.By(validateFieldSubFields(f.Type))),
 	)
 }
 
@@ -114,6 +167,37 @@ func validateFieldAnalyzers(t Type) validation.RuleWithContextFunc {
 	}
 }
 
+// normalizerTypes are analyzers which do not split or remove non-empty tokens
+var normalizerTypes = map[AnalyzerType]struct{}{
+	Nop:                {},
+	FilterLowercase:    {},
+	FilterUppercase:    {},
+	FilterASCIIFolding: {},
+	FilterTrim:         {},
+}
+
+func validateFieldNormalizer() validation.RuleWithContextFunc {
+	return func(ctx context.Context, value interface{}) error {
+		v := value.(string)
+		if v == "" {
+			return nil
+		}
+
+		s := ctx.Value("schema").(Schema)
+		fa, ok := s.Analyzers[v]
+		if !ok {
+			return errs.Errorf("unknown analyzer %q", v)
+		}
+		for _, a := range fa.Analyzers {
+			if _, ok := normalizerTypes[a.Type]; !ok {
+				return errs.Errorf("analyzer type %q cannot be used in normalizer", a.Type)
+			}
+		}
+
+		return nil
+	}
+}
+
 func validateFieldChildren(t Type) validation.RuleFunc {
 	return func(value interface{}) error {
 		if value == nil {
@@ -137,3 +221,51 @@ func validateFieldChildren(t Type) validation.RuleFunc {
 		return nil
 	}
 }
+
+func validateFieldSubFields(t Type) validation.RuleFunc {
+	return func(value interface{}) error {
+		v, _ := value.(map[string]Field)
+		if len(v) == 0 {
+			return nil
+		}
+
+		if t == TypeSlice || t == TypeMap || t == TypeDenseVector || t == TypeGeoPoint {
+			return errs.Errorf("type %q cannot have sub-fields", t)
+		}
+		for name, f := range v {
+			if f.Type == TypeSlice || f.Type == TypeMap || f.Type == TypeDenseVector || f.Type == TypeGeoPoint {
+				return errs.Errorf("sub-field %q cannot be of type %q", name, f.Type)
+			}
+			if len(f.Fields) != 0 {
+				return errs.Errorf("sub-field %q cannot have own sub-fields", name)
+			}
+		}
+
+		return nil
+	}
+}
+
+func validateFieldSimilarity(t Type) validation.RuleFunc {
+	return func(value interface{}) error {
+		v := value.(Similarity)
+		switch t {
+		case TypeText:
+			if v.Type.Vector() {
+				return errs.Errorf("similarity %q is allowed for dense_vector fields only", v.Type)
+			}
+		case TypeDenseVector:
+			if v.Type != "" && !v.Type.Vector() {
+				return errs.Errorf("similarity %q is allowed for text fields only", v.Type)
+			}
+			if len(v.Settings) != 0 {
+				return errs.Errorf("dense_vector similarity settings are not supported")
+			}
+		default:
+			if v.Type != "" || len(v.Settings) != 0 {
+				return errs.Errorf("similarity is allowed for text and dense_vector fields only")
+			}
+		}
+
+		return nil
+	}
+}
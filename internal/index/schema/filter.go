@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/spf13/cast"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FilterLowercaseFunc converts tokens to lower case
+func FilterLowercaseFunc() AnalyzerFunc {
+	return func(s []string) []string {
+		result := make([]string, len(s))
+		for i, str := range s {
+			result[i] = strings.ToLower(str)
+		}
+
+		return result
+	}
+}
+
+// FilterUppercaseFunc converts tokens to upper case
+func FilterUppercaseFunc() AnalyzerFunc {
+	return func(s []string) []string {
+		result := make([]string, len(s))
+		for i, str := range s {
+			result[i] = strings.ToUpper(str)
+		}
+
+		return result
+	}
+}
+
+// FilterASCIIFoldingFunc removes diacritics from tokens ("café" => "cafe").
+// Tokens are decomposed (NFKD) and combining marks are dropped.
+func FilterASCIIFoldingFunc() AnalyzerFunc {
+	return func(s []string) []string {
+		result := make([]string, len(s))
+		for i, str := range s {
+			result[i] = foldASCII(str)
+		}
+
+		return result
+	}
+}
+
+func foldASCII(str string) string {
+	if isASCII(str) {
+		return str
+	}
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for _, r := range norm.NFKD.String(str) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return norm.NFC.String(b.String())
+}
+
+func isASCII(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterTrimFunc removes leading and trailing whitespace from tokens. Empty tokens are dropped
+func FilterTrimFunc() AnalyzerFunc {
+	return func(s []string) []string {
+		result := make([]string, 0, len(s))
+		for _, str := range s {
+			str = strings.TrimSpace(str)
+			if str == "" {
+				continue
+			}
+			result = append(result, str)
+		}
+
+		return result
+	}
+}
+
+// FilterLengthFunc removes tokens shorter than "min" or longer than "max" characters
+func FilterLengthFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	min, max := 0, math.MaxInt
+	for k, v := range settings {
+		var err error
+		switch k {
+		case "min":
+			min, err = cast.ToIntE(v)
+		case "max":
+			max, err = cast.ToIntE(v)
+		default:
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		if err != nil {
+			return nil, errs.Errorf("%q must be an integer value", k)
+		}
+	}
+	if min < 0 {
+		return nil, errs.Errorf("%q must be >= 0", "min")
+	}
+	if max < min {
+		return nil, errs.Errorf("%q must be >= %q", "max", "min")
+	}
+
+	return func(s []string) []string {
+		result := make([]string, 0, len(s))
+		for _, str := range s {
+			l := utf8.RuneCountInString(str)
+			if l < min || l > max {
+				continue
+			}
+			result = append(result, str)
+		}
+
+		return result
+	}, nil
+}
+
+// FilterStopFunc removes stop words from tokens.
+// "stopwords" setting is either a predefined list name ("_english_", "_russian_", "_none_") or a list of words. Default is "_english_".
+// Words are compared case-insensitively if "ignoreCase" is true.
+func FilterStopFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	words := stopWords["_english_"]
+	ignoreCase := false
+	for k, v := range settings {
+		switch k {
+		case "stopwords":
+			if name, ok := v.(string); ok {
+				list, ok := stopWords[name]
+				if !ok {
+					return nil, errs.Errorf("unknown stopwords list %q", name)
+				}
+				words = list
+				continue
+			}
+
+			list, ok := toStringSlice(v)
+			if !ok {
+				return nil, errs.Errorf("%q must be a list name or a list of strings", k)
+			}
+			words = list
+		case "ignoreCase":
+			var err error
+			ignoreCase, err = cast.ToBoolE(v)
+			if err != nil {
+				return nil, errs.Errorf("%q must be a boolean value", k)
+			}
+		default:
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+	}
+
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		if ignoreCase {
+			w = strings.ToLower(w)
+		}
+		set[w] = struct{}{}
+	}
+
+	return func(s []string) []string {
+		result := make([]string, 0, len(s))
+		for _, str := range s {
+			key := str
+			if ignoreCase {
+				key = strings.ToLower(key)
+			}
+			if _, ok := set[key]; ok {
+				continue
+			}
+			result = append(result, str)
+		}
+
+		return result
+	}, nil
+}
+
+func toStringSlice(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, len(v))
+		for i, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result[i] = str
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+var stopWords = map[string][]string{
+	"_none_": {},
+	"_english_": {
+		"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in", "into", "is", "it",
+		"no", "not", "of", "on", "or", "such", "that", "the", "their", "then", "there", "these",
+		"they", "this", "to", "was", "will", "with",
+	},
+	"_russian_": {
+		"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас", "весь", "во",
+		"вот", "все", "всего", "всех", "вы", "где", "да", "даже", "для", "до", "его", "ее", "ей", "ею",
+		"если", "есть", "еще", "же", "за", "здесь", "и", "из", "или", "им", "их", "к", "как", "ко",
+		"когда", "кто", "ли", "либо", "мне", "может", "мы", "на", "надо", "наш", "не", "него", "нее",
+		"нет", "ни", "них", "но", "ну", "о", "об", "однако", "он", "она", "они", "оно", "от", "очень",
+		"по", "под", "при", "с", "со", "так", "также", "такой", "там", "те", "тем", "то", "того", "тоже",
+		"той", "только", "том", "ты", "у", "уже", "хотя", "чего", "чей", "чем", "что", "чтобы", "чье",
+		"чья", "эта", "эти", "это", "я",
+	},
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilterLowercaseFunc(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		result := FilterLowercaseFunc()(nil)
+		require.Empty(t, result)
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		result := FilterLowercaseFunc()([]string{"Hello", "WORLD", "Привет"})
+		require.Equal(t, []string{"hello", "world", "привет"}, result)
+	})
+}
+
+func Test_FilterUppercaseFunc(t *testing.T) {
+	result := FilterUppercaseFunc()([]string{"Hello", "world"})
+	require.Equal(t, []string{"HELLO", "WORLD"}, result)
+}
+
+func Test_FilterASCIIFoldingFunc(t *testing.T) {
+	result := FilterASCIIFoldingFunc()([]string{"café", "naïve", "Ångström", "plain", "ﬁ"})
+	require.Equal(t, []string{"cafe", "naive", "Angstrom", "plain", "fi"}, result)
+}
+
+func Test_FilterTrimFunc(t *testing.T) {
+	result := FilterTrimFunc()([]string{" hello ", "\tworld", "  "})
+	require.Equal(t, []string{"hello", "world"}, result)
+}
+
+func Test_FilterLengthFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := FilterLengthFunc(map[string]interface{}{"extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if min is not an integer", func(t *testing.T) {
+		f, err := FilterLengthFunc(map[string]interface{}{"min": "foo"})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if min is negative", func(t *testing.T) {
+		f, err := FilterLengthFunc(map[string]interface{}{"min": -1})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if max is less than min", func(t *testing.T) {
+		f, err := FilterLengthFunc(map[string]interface{}{"min": 3, "max": 2})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must remove too short and too long tokens", func(t *testing.T) {
+		f, err := FilterLengthFunc(map[string]interface{}{"min": 2, "max": 4})
+		require.NoError(t, err)
+
+		result := f([]string{"a", "ab", "abcd", "abcde", "ёжик"})
+		require.Equal(t, []string{"ab", "abcd", "ёжик"}, result)
+	})
+}
+
+func Test_FilterStopFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := FilterStopFunc(map[string]interface{}{"extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if stopwords list is unknown", func(t *testing.T) {
+		f, err := FilterStopFunc(map[string]interface{}{"stopwords": "_unknown_"})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if stopwords is not a list of strings", func(t *testing.T) {
+		f, err := FilterStopFunc(map[string]interface{}{"stopwords": 1})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must use english stopwords by default", func(t *testing.T) {
+		f, err := FilterStopFunc(nil)
+		require.NoError(t, err)
+
+		result := f([]string{"the", "quick", "fox", "The"})
+		require.Equal(t, []string{"quick", "fox", "The"}, result)
+	})
+
+	t.Run("must use predefined stopwords list", func(t *testing.T) {
+		f, err := FilterStopFunc(map[string]interface{}{"stopwords": "_russian_"})
+		require.NoError(t, err)
+
+		result := f([]string{"я", "и", "ты"})
+		require.Empty(t, result)
+	})
+
+	t.Run("must use custom stopwords list", func(t *testing.T) {
+		f, err := FilterStopFunc(map[string]interface{}{"stopwords": []interface{}{"foo"}, "ignoreCase": true})
+		require.NoError(t, err)
+
+		result := f([]string{"foo", "FOO", "bar"})
+		require.Equal(t, []string{"bar"}, result)
+	})
+}
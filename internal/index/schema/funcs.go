@@ -27,3 +27,18 @@ func DedupFunc() AnalyzerFunc {
 		return result
 	}
 }
+
+// dedupTokens is the token version of DedupFunc
+func dedupTokens() TokenFunc {
+	return func(tokens []Token) []Token {
+		m := make(map[string]struct{}, len(tokens))
+
+		return filterTokens(func(str string) bool {
+			if _, ok := m[str]; ok {
+				return false
+			}
+			m[str] = struct{}{}
+			return true
+		})(tokens)
+	}
+}
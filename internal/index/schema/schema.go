@@ -5,19 +5,82 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/cyradin/search/internal/errs"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type FieldAnalyzer struct {
-	Analyzers []Analyzer `json:"analyzers"`
+	// CharFilters are applied to values before tokenization
+	CharFilters []CharFilter `json:"charFilters"`
+	Analyzers   []Analyzer   `json:"analyzers"`
 }
 
-func (fa FieldAnalyzer) Build() (AnalyzerFunc, error) {
-	return Chain(fa.Analyzers)
+// DefaultTextAnalyzer is used by text fields without analyzer
+func DefaultTextAnalyzer() FieldAnalyzer {
+	return FieldAnalyzer{
+		Analyzers: []Analyzer{
+			{Type: TokenizerStandard},
+			{Type: FilterLowercase},
+		},
+	}
+}
+
+func (fa FieldAnalyzer) Build(opts ...AnalyzerOpts) (AnalyzerFunc, error) {
+	h, err := Chain(fa.Analyzers, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(fa.CharFilters) - 1; i >= 0; i-- {
+		f, err := fa.CharFilters[i].GetFunc()
+		if err != nil {
+			return nil, err
+		}
+
+		h = handler(f, h)
+	}
+
+	return h, nil
+}
+
+// BuildTokenFunc works the same way as Build, but the func keeps token positions and offsets in the source text
+func (fa FieldAnalyzer) BuildTokenFunc(opts ...AnalyzerOpts) (TokenFunc, error) {
+	if len(fa.Analyzers) == 0 {
+		return nil, errs.Errorf("chain cannot be empty")
+	}
+
+	funcs := make([]TokenFunc, 0, len(fa.CharFilters)+len(fa.Analyzers))
+	for _, c := range fa.CharFilters {
+		f, err := c.getTokenFunc()
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, f)
+	}
+	for _, a := range fa.Analyzers {
+		f, err := a.getTokenFunc(opts...)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, f)
+	}
+
+	return func(tokens []Token) []Token {
+		for _, f := range funcs {
+			tokens = f(tokens)
+		}
+
+		return tokens
+	}, nil
 }
 
 func (a FieldAnalyzer) Validate() error {
-	return validation.ValidateStruct(&a,
+	return a.ValidateWithContext(context.Background())
+}
+
+func (a FieldAnalyzer) ValidateWithContext(ctx context.Context) error {
+	return validation.ValidateStructWithContext(ctx, &a,
+		validation.Field(&a.CharFilters),
 		validation.Field(&a.Analyzers, validation.Required, validation.Length(1, 0)),
 	)
 }
@@ -38,9 +101,16 @@ func (s Schema) ValidateDoc(doc map[string]interface{}) error {
 	return ValidateDoc(s, doc)
 }
 
+// Validate validates the schema. Analyzer files (e.g. synonyms) are not checked as the data dir is unknown, use ValidateWithOpts to check them
 func (s Schema) Validate() error {
+	return s.ValidateWithOpts(AnalyzerOpts{})
+}
+
+// ValidateWithOpts validates the schema. Analyzer files are checked if opts.DataDir is provided
+func (s Schema) ValidateWithOpts(opts AnalyzerOpts) error {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "schema", s)
+	ctx = context.WithValue(ctx, "analyzerOpts", opts)
 
 	if err := validateKeys("fields", s.Fields); err != nil {
 		return err
@@ -50,6 +120,14 @@ func (s Schema) Validate() error {
 		return err
 	}
 
+	for name, f := range s.Fields {
+		for subName := range f.Fields {
+			if _, ok := s.Fields[SubFieldName(name, subName)]; ok {
+				return validation.Errors{"fields": validation.NewError(name, fmt.Sprintf("sub-field %q conflicts with field of the same name", SubFieldName(name, subName)))}
+			}
+		}
+	}
+
 	return validation.ValidateStructWithContext(ctx, &s,
 		validation.Field(&s.Fields, validation.Required),
 		validation.Field(&s.Analyzers),
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -68,7 +70,7 @@ func Test_Schema_Validate(t *testing.T) {
 		require.Error(t, err)
 	})
 
-	t.Run("must fail if text field has no analyzers", func(t *testing.T) {
+	t.Run("must not fail if text field has no analyzers", func(t *testing.T) {
 		s := New(
 			map[string]Field{
 				"name": {Type: TypeText},
@@ -76,7 +78,7 @@ func Test_Schema_Validate(t *testing.T) {
 			nil,
 		)
 		err := validation.Validate(s)
-		require.Error(t, err)
+		require.NoError(t, err)
 	})
 
 	t.Run("must fail if text field has unknown analyzer", func(t *testing.T) {
@@ -124,6 +126,270 @@ func Test_Schema_Validate(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("must fail if char filter has invalid settings", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeText, Analyzer: "analyzer"},
+			},
+			map[string]FieldAnalyzer{
+				"analyzer": {
+					CharFilters: []CharFilter{
+						{Type: CharFilterPatternReplace, Settings: nil},
+					},
+					Analyzers: []Analyzer{
+						{Type: TokenizerWhitespace, Settings: nil},
+					},
+				},
+			},
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if normalizer is set for non-keyword field", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeText, Normalizer: "normalizer"},
+			},
+			map[string]FieldAnalyzer{
+				"normalizer": {Analyzers: []Analyzer{{Type: FilterLowercase}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if keyword field has unknown normalizer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, Normalizer: "normalizer"},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if normalizer contains tokenizer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, Normalizer: "normalizer"},
+			},
+			map[string]FieldAnalyzer{
+				"normalizer": {Analyzers: []Analyzer{{Type: TokenizerWhitespace}, {Type: FilterLowercase}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must not fail if keyword field has valid normalizer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, Normalizer: "normalizer"},
+			},
+			map[string]FieldAnalyzer{
+				"normalizer": {Analyzers: []Analyzer{{Type: FilterTrim}, {Type: FilterLowercase}, {Type: FilterASCIIFolding}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.NoError(t, err)
+	})
+
+	t.Run("must not fail if completion field has valid normalizer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeCompletion, Normalizer: "normalizer"},
+			},
+			map[string]FieldAnalyzer{
+				"normalizer": {Analyzers: []Analyzer{{Type: FilterLowercase}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.NoError(t, err)
+	})
+
+	t.Run("must fail if text field has unknown search analyzer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeText, Analyzer: "analyzer", SearchAnalyzer: "invalid"},
+			},
+			map[string]FieldAnalyzer{
+				"analyzer": {Analyzers: []Analyzer{{Type: TokenizerWhitespace}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if search analyzer provided without analyzer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, SearchAnalyzer: "analyzer"},
+			},
+			map[string]FieldAnalyzer{
+				"analyzer": {Analyzers: []Analyzer{{Type: TokenizerWhitespace}}},
+			},
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if sub-field is invalid", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeText, Fields: map[string]Field{
+					"raw": {Type: "invalid"},
+				}},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if sub-field has own sub-fields", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeText, Fields: map[string]Field{
+					"raw": {Type: TypeKeyword, Fields: map[string]Field{"raw": {Type: TypeKeyword}}},
+				}},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if slice field has sub-fields", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {
+					Type:     TypeSlice,
+					Children: map[string]Field{"name": {Type: TypeKeyword}},
+					Fields:   map[string]Field{"raw": {Type: TypeKeyword}},
+				},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if sub-field name conflicts with field", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name":     {Type: TypeText, Fields: map[string]Field{"raw": {Type: TypeKeyword}}},
+				"name.raw": {Type: TypeKeyword},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if sub-field has unknown analyzer", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, Fields: map[string]Field{"text": {Type: TypeText, Analyzer: "unknown"}}},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if similarity is set for non-text field", func(t *testing.T) {
+		s := New(
+			map[string]Field{
+				"name": {Type: TypeKeyword, Similarity: Similarity{Type: SimilarityBoolean}},
+			},
+			nil,
+		)
+		err := validation.Validate(s)
+		require.Error(t, err)
+	})
+
+	t.Run("must fail if similarity is invalid", func(t *testing.T) {
+		for _, sim := range []Similarity{
+			{Type: "invalid"},
+			{Type: SimilarityBM25, Settings: map[string]interface{}{"k1": -1}},
+			{Type: SimilarityBM25, Settings: map[string]interface{}{"b": "foo"}},
+			{Type: SimilarityBM25, Settings: map[string]interface{}{"extra": 1}},
+			{Type: SimilarityClassic, Settings: map[string]interface{}{"k1": 1}},
+		} {
+			s := New(
+				map[string]Field{
+					"name": {Type: TypeText, Similarity: sim},
+				},
+				nil,
+			)
+			err := validation.Validate(s)
+			require.Error(t, err, sim)
+		}
+	})
+
+	t.Run("must validate dense vector fields", func(t *testing.T) {
+		invalid := []Field{
+			{Type: TypeDenseVector},
+			{Type: TypeDenseVector, Dims: MaxVectorDims + 1},
+			{Type: TypeDenseVector, Dims: 3, Similarity: Similarity{Type: SimilarityBM25}},
+			{Type: TypeDenseVector, Dims: 3, Similarity: Similarity{Settings: map[string]interface{}{"k1": 1}}},
+			{Type: TypeDenseVector, Dims: 3, Fields: map[string]Field{"raw": {Type: TypeKeyword}}},
+			{Type: TypeText, Similarity: Similarity{Type: SimilarityCosine}},
+			{Type: TypeKeyword, Dims: 3},
+		}
+		for _, f := range invalid {
+			err := validation.Validate(New(map[string]Field{"name": f}, nil))
+			require.Error(t, err, f)
+		}
+
+		valid := []Field{
+			{Type: TypeDenseVector, Dims: 3},
+			{Type: TypeDenseVector, Dims: 3, Similarity: Similarity{Type: SimilarityCosine}},
+			{Type: TypeDenseVector, Dims: 3, Similarity: Similarity{Type: SimilarityDotProduct}},
+			{Type: TypeDenseVector, Dims: 3, Similarity: Similarity{Type: SimilarityL2Norm}},
+		}
+		for _, f := range valid {
+			err := validation.Validate(New(map[string]Field{"name": f}, nil))
+			require.NoError(t, err, f)
+		}
+	})
+
+	t.Run("must check analyzer files in the data dir if it is provided", func(t *testing.T) {
+		s := New(
+			map[string]Field{"name": {Type: TypeText, Analyzer: "analyzer"}},
+			map[string]FieldAnalyzer{
+				"analyzer": {Analyzers: []Analyzer{
+					{Type: TokenizerWhitespace},
+					{Type: FilterSynonym, Settings: map[string]interface{}{"synonymsPath": "synonyms.txt"}},
+				}},
+			},
+		)
+		dir := t.TempDir()
+
+		require.NoError(t, validation.Validate(s))
+		require.Error(t, s.ValidateWithOpts(AnalyzerOpts{DataDir: dir}))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "synonyms.txt"), []byte("tv, television\n"), 0644))
+		require.NoError(t, s.ValidateWithOpts(AnalyzerOpts{DataDir: dir}))
+	})
+
+	t.Run("must validate geo point fields", func(t *testing.T) {
+		invalid := []Field{
+			{Type: TypeGeoPoint, Fields: map[string]Field{"raw": {Type: TypeKeyword}}},
+			{Type: TypeKeyword, Fields: map[string]Field{"point": {Type: TypeGeoPoint}}},
+			{Type: TypeGeoPoint, Similarity: Similarity{Type: SimilarityCosine}},
+		}
+		for _, f := range invalid {
+			err := validation.Validate(New(map[string]Field{"name": f}, nil))
+			require.Error(t, err, f)
+		}
+
+		err := validation.Validate(New(map[string]Field{"name": {Type: TypeGeoPoint}}, nil))
+		require.NoError(t, err)
+	})
+
 	t.Run("must not fail for vaild fields", func(t *testing.T) {
 		s := New(
 			map[string]Field{
@@ -132,6 +398,11 @@ func Test_Schema_Validate(t *testing.T) {
 				"name3": {Type: TypeSlice, Children: map[string]Field{
 					"name": {Type: TypeKeyword},
 				}},
+				"name5": {Type: TypeText, Similarity: Similarity{Type: SimilarityBM25, Settings: map[string]interface{}{"k1": 2.0, "b": 0}}},
+				"name4": {Type: TypeText, Fields: map[string]Field{
+					"raw":   {Type: TypeKeyword},
+					"ngram": {Type: TypeText, Analyzer: "analyzer"},
+				}},
 			},
 			map[string]FieldAnalyzer{
 				"analyzer": {Analyzers: []Analyzer{
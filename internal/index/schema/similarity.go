@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"github.com/cyradin/search/internal/errs"
+	"github.com/spf13/cast"
+)
+
+type SimilarityType string
+
+const (
+	SimilarityBM25    SimilarityType = "BM25"
+	SimilarityClassic SimilarityType = "classic"
+	SimilarityBoolean SimilarityType = "boolean"
+
+	// Dense vector similarities
+	SimilarityCosine     SimilarityType = "cosine"
+	SimilarityDotProduct SimilarityType = "dot_product"
+	SimilarityL2Norm     SimilarityType = "l2_norm"
+)
+
+// Vector returns true if the similarity compares dense vectors
+func (t SimilarityType) Vector() bool {
+	return t == SimilarityCosine || t == SimilarityDotProduct || t == SimilarityL2Norm
+}
+
+const (
+	DefaultBM25K1 = 1.2
+	DefaultBM25B  = 0.75
+)
+
+// Similarity defines how text field documents are scored (BM25 is used if type is not provided)
+// or how dense vectors are compared (cosine is used if type is not provided)
+type Similarity struct {
+	Type     SimilarityType         `json:"type"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+func (s Similarity) Validate() error {
+	switch s.Type {
+	case "", SimilarityBM25:
+		_, _, err := s.BM25Params()
+		return err
+	case SimilarityClassic, SimilarityBoolean, SimilarityCosine, SimilarityDotProduct, SimilarityL2Norm:
+		for k := range s.Settings {
+			return errs.Errorf("key %q is not allowed", k)
+		}
+		return nil
+	}
+
+	return errs.Errorf("unknown similarity type %q", s.Type)
+}
+
+// BM25Params returns "k1" (term frequency saturation, >= 0) and "b" (length normalization, 0..1) BM25 settings
+func (s Similarity) BM25Params() (float64, float64, error) {
+	k1, b := DefaultBM25K1, DefaultBM25B
+	for k, v := range s.Settings {
+		var err error
+		switch k {
+		case "k1":
+			k1, err = cast.ToFloat64E(v)
+		case "b":
+			b, err = cast.ToFloat64E(v)
+		default:
+			return 0, 0, errs.Errorf("key %q is not allowed", k)
+		}
+		if err != nil {
+			return 0, 0, errs.Errorf("%q must be a number", k)
+		}
+	}
+	if k1 < 0 {
+		return 0, 0, errs.Errorf("%q must be >= 0", "k1")
+	}
+	if b < 0 || b > 1 {
+		return 0, 0, errs.Errorf("%q must be between 0 and 1", "b")
+	}
+
+	return k1, b, nil
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"github.com/cyradin/search/internal/errs"
+)
+
+const (
+	StemmerEnglish = "english"
+	StemmerRussian = "russian"
+)
+
+var stemmers = map[string]func(string) string{
+	StemmerEnglish: stemEnglish,
+	StemmerRussian: stemRussian,
+}
+
+// FilterStemmerFunc reduces tokens to their stems ("running" => "run").
+// "language" setting selects the Snowball stemmer: "english" (Porter2) or "russian".
+// Tokens are expected to be in lower case.
+func FilterStemmerFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	var (
+		language string
+		ok       bool
+	)
+	for k, v := range settings {
+		if k != "language" {
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		language, ok = v.(string)
+		if !ok {
+			return nil, errs.Errorf("%q must be a string value", k)
+		}
+	}
+	if language == "" {
+		return nil, errs.Errorf("%q key must be provided", "language")
+	}
+
+	stem, ok := stemmers[language]
+	if !ok {
+		return nil, errs.Errorf("unsupported stemmer language %q", language)
+	}
+
+	return func(s []string) []string {
+		result := make([]string, len(s))
+		for i, str := range s {
+			result[i] = stem(str)
+		}
+
+		return result
+	}, nil
+}
+
+// suffixRule replaces suffix with replacement if cond returns true.
+// cond receives the word and the position where the suffix starts
+type suffixRule struct {
+	suffix      []rune
+	replacement []rune
+	cond        func(w []rune, pos int) bool
+}
+
+func newSuffixRule(suffix string, replacement string, cond func(w []rune, pos int) bool) suffixRule {
+	return suffixRule{suffix: []rune(suffix), replacement: []rune(replacement), cond: cond}
+}
+
+// applyLongestSuffix finds the longest matching suffix among the rules and applies it if its condition is met.
+// Shorter suffixes are not tried if the longest one does not meet the condition.
+func applyLongestSuffix(w []rune, rules []suffixRule) ([]rune, bool) {
+	var found *suffixRule
+	for i := range rules {
+		if !hasRuneSuffix(w, rules[i].suffix) {
+			continue
+		}
+		if found == nil || len(rules[i].suffix) > len(found.suffix) {
+			found = &rules[i]
+		}
+	}
+	if found == nil {
+		return w, false
+	}
+
+	pos := len(w) - len(found.suffix)
+	if found.cond != nil && !found.cond(w, pos) {
+		return w, false
+	}
+
+	return append(w[:pos:pos], found.replacement...), true
+}
+
+func hasRuneSuffix(w []rune, suffix []rune) bool {
+	if len(w) < len(suffix) {
+		return false
+	}
+
+	offset := len(w) - len(suffix)
+	for i, r := range suffix {
+		if w[offset+i] != r {
+			return false
+		}
+	}
+
+	return true
+}
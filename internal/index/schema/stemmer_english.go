@@ -0,0 +1,321 @@
+package schema
+
+import (
+	"strings"
+)
+
+// English (Porter2) stemmer, see https://snowballstem.org/algorithms/english/stemmer.html
+
+var enExceptions1 = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+var enExceptions2 = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+func stemEnglish(word string) string {
+	if len([]rune(word)) <= 2 {
+		return word
+	}
+	if v, ok := enExceptions1[word]; ok {
+		return v
+	}
+
+	w := []rune(strings.TrimPrefix(word, "'"))
+	for i := range w {
+		if w[i] == 'y' && (i == 0 || enIsVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1, r2 := enRegions(w)
+	inR1 := func(w []rune, pos int) bool { return pos >= r1 }
+	inR2 := func(w []rune, pos int) bool { return pos >= r2 }
+
+	w, _ = applyLongestSuffix(w, enStep0)
+	w = enStep1a(w)
+	if _, ok := enExceptions2[string(w)]; ok {
+		return enRestoreY(w)
+	}
+
+	w = enStep1b(w, r1)
+	w = enStep1c(w)
+	w, _ = applyLongestSuffix(w, enStep2(inR1))
+	w, _ = applyLongestSuffix(w, enStep3(inR1, inR2))
+	w, _ = applyLongestSuffix(w, enStep4(inR2))
+	w = enStep5(w, r1, r2)
+
+	return enRestoreY(w)
+}
+
+func enIsVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func enIsDouble(w []rune) bool {
+	if len(w) < 2 || w[len(w)-1] != w[len(w)-2] {
+		return false
+	}
+
+	switch w[len(w)-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func enIsLiEnding(r rune) bool {
+	switch r {
+	case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func enContainsVowel(w []rune) bool {
+	for _, r := range w {
+		if enIsVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// enRegions returns R1 and R2 start positions.
+// R1 is the region after the first non-vowel following a vowel, R2 is the same region inside R1
+func enRegions(w []rune) (int, int) {
+	r1 := len(w)
+	prefixFound := false
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			r1 = len([]rune(prefix))
+			prefixFound = true
+			break
+		}
+	}
+	if !prefixFound {
+		r1 = enNextRegion(w, 0)
+	}
+
+	return r1, enNextRegion(w, r1)
+}
+
+func enNextRegion(w []rune, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !enIsVowel(w[i]) && enIsVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// enEndsWithShortSyllable checks if word ends with a non-vowel followed by a vowel followed by a non-vowel other than w, x or Y,
+// or the word is a vowel followed by a non-vowel
+func enEndsWithShortSyllable(w []rune) bool {
+	n := len(w)
+	if n == 2 {
+		return enIsVowel(w[0]) && !enIsVowel(w[1])
+	}
+	if n < 3 {
+		return false
+	}
+
+	last := w[n-1]
+	return !enIsVowel(w[n-3]) && enIsVowel(w[n-2]) && !enIsVowel(last) && last != 'w' && last != 'x' && last != 'Y'
+}
+
+func enRestoreY(w []rune) string {
+	for i := range w {
+		if w[i] == 'Y' {
+			w[i] = 'y'
+		}
+	}
+	return string(w)
+}
+
+var enStep0 = []suffixRule{
+	newSuffixRule("'s'", "", nil),
+	newSuffixRule("'s", "", nil),
+	newSuffixRule("'", "", nil),
+}
+
+func enStep1a(w []rune) []rune {
+	s := string(w)
+	switch {
+	case strings.HasSuffix(s, "sses"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(s, "ied"), strings.HasSuffix(s, "ies"):
+		if len(w) > 4 {
+			return w[:len(w)-2]
+		}
+		return w[:len(w)-1]
+	case strings.HasSuffix(s, "us"), strings.HasSuffix(s, "ss"):
+		return w
+	case strings.HasSuffix(s, "s"):
+		if len(w) >= 2 && enContainsVowel(w[:len(w)-2]) {
+			return w[:len(w)-1]
+		}
+	}
+
+	return w
+}
+
+func enStep1b(w []rune, r1 int) []rune {
+	s := string(w)
+	for _, suffix := range []string{"eedly", "eed"} {
+		if strings.HasSuffix(s, suffix) {
+			pos := len(w) - len(suffix)
+			if pos >= r1 {
+				return append(w[:pos:pos], 'e', 'e')
+			}
+			return w
+		}
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed"} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+
+		pos := len(w) - len(suffix)
+		if !enContainsVowel(w[:pos]) {
+			return w
+		}
+		w = w[:pos:pos]
+
+		s = string(w)
+		switch {
+		case strings.HasSuffix(s, "at"), strings.HasSuffix(s, "bl"), strings.HasSuffix(s, "iz"):
+			return append(w, 'e')
+		case enIsDouble(w):
+			return w[:len(w)-1]
+		case r1 >= len(w) && enEndsWithShortSyllable(w):
+			return append(w, 'e')
+		}
+		return w
+	}
+
+	return w
+}
+
+func enStep1c(w []rune) []rune {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !enIsVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+	return w
+}
+
+func enStep2(inR1 func(w []rune, pos int) bool) []suffixRule {
+	precededBy := func(cond func(r rune) bool) func(w []rune, pos int) bool {
+		return func(w []rune, pos int) bool {
+			return inR1(w, pos) && pos > 0 && cond(w[pos-1])
+		}
+	}
+
+	return []suffixRule{
+		newSuffixRule("tional", "tion", inR1),
+		newSuffixRule("enci", "ence", inR1),
+		newSuffixRule("anci", "ance", inR1),
+		newSuffixRule("abli", "able", inR1),
+		newSuffixRule("entli", "ent", inR1),
+		newSuffixRule("izer", "ize", inR1),
+		newSuffixRule("ization", "ize", inR1),
+		newSuffixRule("ational", "ate", inR1),
+		newSuffixRule("ation", "ate", inR1),
+		newSuffixRule("ator", "ate", inR1),
+		newSuffixRule("alism", "al", inR1),
+		newSuffixRule("aliti", "al", inR1),
+		newSuffixRule("alli", "al", inR1),
+		newSuffixRule("fulness", "ful", inR1),
+		newSuffixRule("ousli", "ous", inR1),
+		newSuffixRule("ousness", "ous", inR1),
+		newSuffixRule("iveness", "ive", inR1),
+		newSuffixRule("iviti", "ive", inR1),
+		newSuffixRule("biliti", "ble", inR1),
+		newSuffixRule("bli", "ble", inR1),
+		newSuffixRule("ogi", "og", precededBy(func(r rune) bool { return r == 'l' })),
+		newSuffixRule("fulli", "ful", inR1),
+		newSuffixRule("lessli", "less", inR1),
+		newSuffixRule("li", "", precededBy(enIsLiEnding)),
+	}
+}
+
+func enStep3(inR1, inR2 func(w []rune, pos int) bool) []suffixRule {
+	return []suffixRule{
+		newSuffixRule("tional", "tion", inR1),
+		newSuffixRule("ational", "ate", inR1),
+		newSuffixRule("alize", "al", inR1),
+		newSuffixRule("icate", "ic", inR1),
+		newSuffixRule("iciti", "ic", inR1),
+		newSuffixRule("ical", "ic", inR1),
+		newSuffixRule("ful", "", inR1),
+		newSuffixRule("ness", "", inR1),
+		newSuffixRule("ative", "", inR2),
+	}
+}
+
+func enStep4(inR2 func(w []rune, pos int) bool) []suffixRule {
+	rules := []suffixRule{
+		newSuffixRule("ion", "", func(w []rune, pos int) bool {
+			return inR2(w, pos) && pos > 0 && (w[pos-1] == 's' || w[pos-1] == 't')
+		}),
+	}
+	for _, suffix := range []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize",
+	} {
+		rules = append(rules, newSuffixRule(suffix, "", inR2))
+	}
+
+	return rules
+}
+
+func enStep5(w []rune, r1, r2 int) []rune {
+	n := len(w)
+	if n == 0 {
+		return w
+	}
+
+	pos := n - 1
+	switch w[pos] {
+	case 'e':
+		if pos >= r2 || (pos >= r1 && !enEndsWithShortSyllable(w[:pos])) {
+			return w[:pos]
+		}
+	case 'l':
+		if pos >= r2 && pos > 0 && w[pos-1] == 'l' {
+			return w[:pos]
+		}
+	}
+
+	return w
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"strings"
+)
+
+// Russian Snowball stemmer, see https://snowballstem.org/algorithms/russian/stemmer.html
+
+// endings of the first groups must be preceded by "а" or "я"
+var (
+	ruPerfectiveGerund = ruRules(
+		[]string{"в", "вши", "вшись"},
+		[]string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"},
+	)
+	ruAdjective = ruRules(nil, []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	})
+	ruParticiple = ruRules(
+		[]string{"ем", "нн", "вш", "ющ", "щ"},
+		[]string{"ивш", "ывш", "ующ"},
+	)
+	ruReflexive = ruRules(nil, []string{"ся", "сь"})
+	ruVerb      = ruRules(
+		[]string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"},
+		[]string{
+			"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+			"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+		},
+	)
+	ruNoun = ruRules(nil, []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+		"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я",
+	})
+	ruSuperlative  = ruRules(nil, []string{"ейше", "ейш"})
+	ruDerivational = []string{"ость", "ост"}
+)
+
+func ruRules(group1 []string, group2 []string) []suffixRule {
+	result := make([]suffixRule, 0, len(group1)+len(group2))
+	for _, suffix := range group1 {
+		result = append(result, newSuffixRule(suffix, "", func(w []rune, pos int) bool {
+			return pos > 0 && (w[pos-1] == 'а' || w[pos-1] == 'я')
+		}))
+	}
+	for _, suffix := range group2 {
+		result = append(result, newSuffixRule(suffix, "", nil))
+	}
+
+	return result
+}
+
+func stemRussian(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ё", "е"))
+	rv, r2 := ruRegions(w)
+	if rv >= len(w) {
+		return string(w)
+	}
+
+	// all the steps are applied to RV region only
+	prefix, w := w[:rv], w[rv:]
+	r2 -= rv
+
+	// step 1
+	var ok bool
+	if w, ok = applyLongestSuffix(w, ruPerfectiveGerund); !ok {
+		w, _ = applyLongestSuffix(w, ruReflexive)
+		if w, ok = applyLongestSuffix(w, ruAdjective); ok {
+			w, _ = applyLongestSuffix(w, ruParticiple)
+		} else if w, ok = applyLongestSuffix(w, ruVerb); !ok {
+			w, _ = applyLongestSuffix(w, ruNoun)
+		}
+	}
+
+	// step 2
+	if hasRuneSuffix(w, []rune("и")) {
+		w = w[:len(w)-1]
+	}
+
+	// step 3
+	for _, suffix := range ruDerivational {
+		if hasRuneSuffix(w, []rune(suffix)) {
+			if pos := len(w) - len([]rune(suffix)); pos >= r2 {
+				w = w[:pos]
+			}
+			break
+		}
+	}
+
+	// step 4
+	w, superlative := applyLongestSuffix(w, ruSuperlative)
+	switch {
+	case hasRuneSuffix(w, []rune("нн")):
+		w = w[:len(w)-1]
+	case !superlative && hasRuneSuffix(w, []rune("ь")):
+		w = w[:len(w)-1]
+	}
+
+	return string(prefix) + string(w)
+}
+
+func ruIsVowel(r rune) bool {
+	switch r {
+	case 'а', 'е', 'и', 'о', 'у', 'ы', 'э', 'ю', 'я':
+		return true
+	}
+	return false
+}
+
+// ruRegions returns RV and R2 start positions.
+// RV is the region after the first vowel, R2 is the region after the second non-vowel following a vowel
+func ruRegions(w []rune) (int, int) {
+	rv, r2 := len(w), len(w)
+	for i, r := range w {
+		if ruIsVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+
+	r1 := len(w)
+	for i := rv; i < len(w); i++ {
+		if !ruIsVowel(w[i]) && ruIsVowel(w[i-1]) {
+			r1 = i + 1
+			break
+		}
+	}
+	for i := r1 + 1; i < len(w); i++ {
+		if !ruIsVowel(w[i]) && ruIsVowel(w[i-1]) {
+			r2 = i + 1
+			break
+		}
+	}
+
+	return rv, r2
+}
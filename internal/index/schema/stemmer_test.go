@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilterStemmerFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := FilterStemmerFunc(map[string]interface{}{"language": "english", "extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if language not provided", func(t *testing.T) {
+		f, err := FilterStemmerFunc(nil)
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if language is not supported", func(t *testing.T) {
+		f, err := FilterStemmerFunc(map[string]interface{}{"language": "klingon"})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		f, err := FilterStemmerFunc(map[string]interface{}{"language": "english"})
+		require.NoError(t, err)
+
+		result := f([]string{"running", "runs", "run"})
+		require.Equal(t, []string{"run", "run", "run"}, result)
+	})
+}
+
+func Test_stemEnglish(t *testing.T) {
+	cases := map[string]string{
+		"a":              "a",
+		"running":        "run",
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"ties":           "tie",
+		"cats":           "cat",
+		"agreed":         "agre",
+		"connection":     "connect",
+		"generalization": "general",
+		"generously":     "generous",
+		"communication":  "communic",
+		"happiness":      "happi",
+		"relational":     "relat",
+		"hopeful":        "hope",
+		"knightly":       "knight",
+		"hoping":         "hope",
+		"sized":          "size",
+		"troubled":       "troubl",
+		"crying":         "cri",
+		"controlling":    "control",
+		"sensitivity":    "sensit",
+		"electrical":     "electr",
+		"adoption":       "adopt",
+		"probate":        "probat",
+		"rate":           "rate",
+		"consolingly":    "consol",
+		"skies":          "sky",
+		"succeeding":     "succeed",
+		"youth":          "youth",
+		"dog's":          "dog",
+	}
+
+	for word, expected := range cases {
+		t.Run(word, func(t *testing.T) {
+			require.Equal(t, expected, stemEnglish(word))
+		})
+	}
+}
+
+func Test_stemRussian(t *testing.T) {
+	cases := map[string]string{
+		"я":                "я",
+		"красивая":         "красив",
+		"бегать":           "бега",
+		"книгами":          "книг",
+		"ёлки":             "елк",
+		"вечерний":         "вечерн",
+		"делающий":         "дела",
+		"важнейший":        "важн",
+		"длинный":          "длин",
+		"кошки":            "кошк",
+		"собакой":          "собак",
+		"читала":           "чита",
+		"говорили":         "говор",
+		"купившись":        "куп",
+		"радость":          "радост",
+		"красивейшие":      "красив",
+		"программирование": "программирован",
+		"безопасность":     "безопасн",
+	}
+
+	for word, expected := range cases {
+		t.Run(word, func(t *testing.T) {
+			require.Equal(t, expected, stemRussian(word))
+		})
+	}
+}
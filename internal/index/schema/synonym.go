@@ -0,0 +1,277 @@
+package schema
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/spf13/cast"
+	"golang.org/x/exp/slices"
+)
+
+type synonymRule struct {
+	match  []string
+	output []string
+}
+
+type synonymFilter struct {
+	rules      map[string][]*synonymRule
+	ignoreCase bool
+}
+
+// FilterSynonymFunc replaces tokens with their synonyms.
+// Rules are provided in Solr format either inline ("synonyms" setting) or in a file inside the data dir ("synonymsPath" setting):
+//   - "tv, television" - equivalent synonyms, every term is replaced with all of them ("expand" is true, by default)
+//     or with the first one ("expand" is false)
+//   - "ny, nyc => new york" - explicit mapping, left terms are replaced with right ones
+//
+// Matched terms may consist of several words separated by whitespace. Replacements must be single words: token positions
+// are not indexed, so "ny => new york" would make "ny" match any text containing "new".
+// Words are compared case-insensitively if "ignoreCase" is true.
+// The file is loaded only if opts.DataDir is provided.
+func FilterSynonymFunc(settings map[string]interface{}, opts AnalyzerOpts) (AnalyzerFunc, error) {
+	f, err := newSynonymFilter(settings, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s []string) []string {
+		result := make([]string, 0, len(s))
+		for i := 0; i < len(s); {
+			rule := matchSynonymRule(f.rules, s[i:], f.ignoreCase)
+			if rule == nil {
+				result = append(result, s[i])
+				i++
+				continue
+			}
+
+			result = append(result, rule.output...)
+			i += len(rule.match)
+		}
+
+		return result
+	}, nil
+}
+
+func newSynonymFilter(settings map[string]interface{}, opts AnalyzerOpts) (synonymFilter, error) {
+	var (
+		lines      []string
+		file       string
+		expand     = true
+		ignoreCase = false
+	)
+	for k, v := range settings {
+		var err error
+		switch k {
+		case "synonyms":
+			var ok bool
+			lines, ok = toStringSlice(v)
+			if !ok {
+				return synonymFilter{}, errs.Errorf("%q must be a list of strings", k)
+			}
+		case "synonymsPath":
+			var ok bool
+			file, ok = v.(string)
+			if !ok {
+				return synonymFilter{}, errs.Errorf("%q must be a string value", k)
+			}
+		case "expand":
+			expand, err = cast.ToBoolE(v)
+		case "ignoreCase":
+			ignoreCase, err = cast.ToBoolE(v)
+		default:
+			return synonymFilter{}, errs.Errorf("key %q is not allowed", k)
+		}
+		if err != nil {
+			return synonymFilter{}, errs.Errorf("%q must be a boolean value", k)
+		}
+	}
+	if len(lines) == 0 && file == "" {
+		return synonymFilter{}, errs.Errorf("%q or %q key must be provided", "synonyms", "synonymsPath")
+	}
+
+	if file != "" {
+		if filepath.IsAbs(file) || strings.HasPrefix(filepath.Clean(file), "..") {
+			return synonymFilter{}, errs.Errorf("%q must be a path relative to the data dir", "synonymsPath")
+		}
+
+		if opts.DataDir != "" {
+			fileLines, err := readSynonymsFile(filepath.Join(opts.DataDir, file))
+			if err != nil {
+				return synonymFilter{}, err
+			}
+			lines = append(lines, fileLines...)
+		}
+	}
+
+	rules, err := parseSynonymRules(lines, expand, ignoreCase)
+	if err != nil {
+		return synonymFilter{}, err
+	}
+
+	return synonymFilter{rules: rules, ignoreCase: ignoreCase}, nil
+}
+
+// tokenFunc returns the token version of the filter. Synonyms get the position of the first matched token
+// and offsets of all matched tokens
+func (f synonymFilter) tokenFunc() TokenFunc {
+	return func(tokens []Token) []Token {
+		values := make([]string, len(tokens))
+		for i, t := range tokens {
+			values[i] = t.Value
+		}
+
+		result := make([]Token, 0, len(tokens))
+		for i := 0; i < len(tokens); {
+			rule := matchSynonymRule(f.rules, values[i:], f.ignoreCase)
+			if rule == nil {
+				result = append(result, tokens[i])
+				i++
+				continue
+			}
+
+			first, last := tokens[i], tokens[i+len(rule.match)-1]
+			for _, word := range rule.output {
+				token := first.withValue(word)
+				token.EndOffset = last.EndOffset
+				result = append(result, token)
+			}
+			i += len(rule.match)
+		}
+
+		return result
+	}
+}
+
+func readSynonymsFile(src string) ([]string, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return nil, errs.Errorf("synonyms file %q open err: %w", src, err)
+	}
+	defer file.Close()
+
+	var result []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		result = append(result, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errs.Errorf("synonyms file %q read err: %w", src, err)
+	}
+
+	return result, nil
+}
+
+// parseSynonymRules parses rules and groups them by the first word. Rules of each group are sorted by length desc
+func parseSynonymRules(lines []string, expand bool, ignoreCase bool) (map[string][]*synonymRule, error) {
+	rules := make(map[string]*synonymRule)
+	add := func(match []string, output [][]string) {
+		key := strings.Join(match, " ")
+		rule, ok := rules[key]
+		if !ok {
+			rule = &synonymRule{match: match}
+			rules[key] = rule
+		}
+
+		for _, words := range output {
+			for _, word := range words {
+				if !slices.Contains(rule.output, word) {
+					rule.output = append(rule.output, word)
+				}
+			}
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if ignoreCase {
+			line = strings.ToLower(line)
+		}
+
+		left, right, explicit := strings.Cut(line, "=>")
+		from := parseSynonymTerms(left)
+		if len(from) == 0 {
+			return nil, errs.Errorf("invalid synonym rule %q", line)
+		}
+
+		to := from
+		if explicit {
+			to = parseSynonymTerms(right)
+			if len(to) == 0 {
+				return nil, errs.Errorf("invalid synonym rule %q", line)
+			}
+		} else if !expand {
+			to = from[:1]
+		}
+
+		for _, term := range to {
+			if len(term) > 1 {
+				return nil, errs.Errorf("invalid synonym rule %q: replacement %q must be a single word", line, strings.Join(term, " "))
+			}
+		}
+		for _, term := range from {
+			add(term, to)
+		}
+	}
+
+	result := make(map[string][]*synonymRule)
+	for _, rule := range rules {
+		result[rule.match[0]] = append(result[rule.match[0]], rule)
+	}
+	for _, group := range result {
+		sort.Slice(group, func(i, j int) bool { return len(group[i].match) > len(group[j].match) })
+	}
+
+	return result, nil
+}
+
+// parseSynonymTerms splits comma-separated terms into words
+func parseSynonymTerms(str string) [][]string {
+	var result [][]string
+	for _, term := range strings.Split(str, ",") {
+		words := strings.Fields(term)
+		if len(words) == 0 {
+			continue
+		}
+		result = append(result, words)
+	}
+
+	return result
+}
+
+// matchSynonymRule returns the longest rule matching the beginning of tokens
+func matchSynonymRule(rules map[string][]*synonymRule, tokens []string, ignoreCase bool) *synonymRule {
+	key := tokens[0]
+	if ignoreCase {
+		key = strings.ToLower(key)
+	}
+
+	for _, rule := range rules[key] {
+		if len(rule.match) > len(tokens) {
+			continue
+		}
+
+		matched := true
+		for i, word := range rule.match[1:] {
+			token := tokens[i+1]
+			if ignoreCase {
+				token = strings.ToLower(token)
+			}
+			if token != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule
+		}
+	}
+
+	return nil
+}
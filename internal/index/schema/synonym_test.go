@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilterSynonymFunc(t *testing.T) {
+	t.Run("must return error if no rules provided", func(t *testing.T) {
+		f, err := FilterSynonymFunc(nil, AnalyzerOpts{})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"a, b"}, "extra": 4}, AnalyzerOpts{})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if synonyms path is outside the data dir", func(t *testing.T) {
+		for _, p := range []string{"/etc/synonyms.txt", "../synonyms.txt"} {
+			f, err := FilterSynonymFunc(map[string]interface{}{"synonymsPath": p}, AnalyzerOpts{})
+			require.Error(t, err)
+			require.Nil(t, f)
+		}
+	})
+
+	t.Run("must return error if rule is invalid", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"a =>"}}, AnalyzerOpts{})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must expand equivalent synonyms", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"tv, television"}}, AnalyzerOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"a", "tv", "television", "b"}, f([]string{"a", "television", "b"}))
+	})
+
+	t.Run("must replace equivalent synonyms with the first one if expand is false", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"tv, television"}, "expand": false}, AnalyzerOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"tv", "tv"}, f([]string{"television", "tv"}))
+	})
+
+	t.Run("must apply explicit mappings", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"ny, new york => nyc"}}, AnalyzerOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"nyc", "city"}, f([]string{"ny", "city"}))
+		require.Equal(t, []string{"nyc"}, f([]string{"new", "york"}))
+		require.Equal(t, []string{"new", "jersey"}, f([]string{"new", "jersey"}))
+	})
+
+	t.Run("must return error if replacement has several words", func(t *testing.T) {
+		// "new york" replacement would make "ny" query match any text containing "new"
+		for _, rule := range []string{"ny => new york", "ny, new york"} {
+			f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{rule}}, AnalyzerOpts{})
+			require.Error(t, err)
+			require.Nil(t, f)
+		}
+
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"ny, new york"}, "expand": false}, AnalyzerOpts{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ny"}, f([]string{"new", "york"}))
+	})
+
+	t.Run("must match the longest multi-word synonym", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{
+			"new => fresh",
+			"new york => nyc",
+		}}, AnalyzerOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"nyc", "fresh", "city"}, f([]string{"new", "york", "new", "city"}))
+	})
+
+	t.Run("must ignore case", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonyms": []string{"TV => television"}, "ignoreCase": true}, AnalyzerOpts{})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"television", "television"}, f([]string{"tv", "Tv"}))
+	})
+
+	t.Run("must load rules from file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "synonyms.txt"), []byte("# comment\n\nquick, fast\n"), 0644))
+
+		settings := map[string]interface{}{"synonymsPath": "synonyms.txt"}
+
+		f, err := FilterSynonymFunc(settings, AnalyzerOpts{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"quick"}, f([]string{"quick"}))
+
+		f, err = FilterSynonymFunc(settings, AnalyzerOpts{DataDir: dir})
+		require.NoError(t, err)
+		require.Equal(t, []string{"quick", "fast"}, f([]string{"quick"}))
+	})
+
+	t.Run("must return error if file does not exist", func(t *testing.T) {
+		f, err := FilterSynonymFunc(map[string]interface{}{"synonymsPath": "synonyms.txt"}, AnalyzerOpts{DataDir: t.TempDir()})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+}
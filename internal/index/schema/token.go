@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"unicode/utf8"
+)
+
+// TokenFunc is an analyzer working with tokens. Tokenizers set positions and offsets of the tokens they produce, filters keep them
+type TokenFunc func([]Token) []Token
+
+// Token is a term produced by the analyzer. Offsets are character offsets of the term in the source text
+type Token struct {
+	Value       string
+	Position    int
+	StartOffset int
+	EndOffset   int
+
+	// chars are source text offsets of every token character. They are set by char filters which may change the text length
+	chars []charOffsets
+}
+
+type charOffsets struct {
+	start int
+	end   int
+}
+
+// Tokens analyzes the text
+func (f TokenFunc) Tokens(text string) []Token {
+	return f([]Token{{Value: text, EndOffset: utf8.RuneCountInString(text)}})
+}
+
+// withValue returns the token with the value replaced, e.g. by a filter. Position and offsets are kept.
+// Character offsets are kept only if the number of characters is not changed
+func (t Token) withValue(value string) Token {
+	if t.chars != nil && utf8.RuneCountInString(value) != len(t.chars) {
+		t.chars = nil
+	}
+	t.Value = value
+
+	return t
+}
+
+// sub returns a new token of the token characters start:end, e.g. produced by a tokenizer.
+// Offsets of the whole token are used if its characters do not match the source text, e.g. if it was changed by a filter
+func (t Token) sub(value string, start int, end int, position int) Token {
+	result := Token{Value: value, Position: position}
+	switch {
+	case t.chars != nil && start < end && end <= len(t.chars):
+		result.StartOffset, result.EndOffset = t.chars[start].start, t.chars[end-1].end
+		result.chars = t.chars[start:end]
+	case t.chars == nil && utf8.RuneCountInString(t.Value) == t.EndOffset-t.StartOffset:
+		result.StartOffset, result.EndOffset = t.StartOffset+start, t.StartOffset+end
+	default:
+		result.StartOffset, result.EndOffset = t.StartOffset, t.EndOffset
+	}
+
+	return result
+}
+
+// charOffsets returns source text offsets of every token character
+func (t Token) charOffsets() []charOffsets {
+	if t.chars != nil {
+		return t.chars
+	}
+
+	n := utf8.RuneCountInString(t.Value)
+	result := make([]charOffsets, n)
+	for i := range result {
+		if n == t.EndOffset-t.StartOffset {
+			result[i] = charOffsets{start: t.StartOffset + i, end: t.StartOffset + i + 1}
+		} else {
+			result[i] = charOffsets{start: t.StartOffset, end: t.EndOffset}
+		}
+	}
+
+	return result
+}
+
+// perToken returns the token func applying the analyzer func to every token value, e.g. a filter.
+// The token is dropped if its value is removed
+func perToken(f AnalyzerFunc) TokenFunc {
+	return func(tokens []Token) []Token {
+		result := make([]Token, 0, len(tokens))
+		value := make([]string, 1)
+		for _, t := range tokens {
+			value[0] = t.Value
+			for _, v := range f(value) {
+				result = append(result, t.withValue(v))
+			}
+		}
+
+		return result
+	}
+}
+
+// filterTokens returns the token func dropping tokens the func returns false for. Positions of the remaining tokens are kept
+func filterTokens(f func(string) bool) TokenFunc {
+	return func(tokens []Token) []Token {
+		result := make([]Token, 0, len(tokens))
+		for _, t := range tokens {
+			if f(t.Value) {
+				result = append(result, t)
+			}
+		}
+
+		return result
+	}
+}
+
+// tokenize returns the token func splitting token values by the tokenizer split func. New tokens are numbered sequentially
+func tokenize(split splitFunc) TokenFunc {
+	return func(tokens []Token) []Token {
+		if len(tokens) == 0 {
+			return tokens
+		}
+
+		result := make([]Token, 0, len(tokens))
+		for _, t := range tokens {
+			// split returns byte offsets, pos and chars are the byte and the character offsets of the previous token start
+			pos, chars := 0, 0
+			split(t.Value, func(start int, end int) {
+				if start < pos {
+					pos, chars = 0, 0
+				}
+				chars += utf8.RuneCountInString(t.Value[pos:start])
+				n := utf8.RuneCountInString(t.Value[start:end])
+				result = append(result, t.sub(t.Value[start:end], chars, chars+n, len(result)))
+				pos = start
+			})
+		}
+
+		return result
+	}
+}
+
+// tokenizer is used to build token funcs of the tokenizers which have settings
+func tokenizer(split splitFunc, err error) (TokenFunc, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenize(split), nil
+}
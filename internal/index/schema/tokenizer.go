@@ -2,30 +2,73 @@ package schema
 
 import (
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/cyradin/search/internal/errs"
+	"github.com/rivo/uniseg"
+	"github.com/spf13/cast"
 )
 
-// TokenizerWhitespaceFunc splits string by whitespace characters (see strings.Fields)
-func TokenizerWhitespaceFunc() AnalyzerFunc {
+// splitFunc splits string into tokens and passes their byte offsets to emit
+type splitFunc func(str string, emit func(start int, end int))
+
+// splitTokens returns analyzer func splitting every value into tokens
+func splitTokens(split splitFunc) AnalyzerFunc {
 	return func(s []string) []string {
 		if len(s) == 0 {
 			return s
 		}
 
 		result := make([]string, 0, len(s))
-
 		for _, str := range s {
-			result = append(result, strings.Fields(str)...)
+			split(str, func(start int, end int) {
+				result = append(result, str[start:end])
+			})
 		}
 
 		return result
 	}
 }
 
+// TokenizerWhitespaceFunc splits string by whitespace characters (see strings.Fields)
+func TokenizerWhitespaceFunc() AnalyzerFunc {
+	return splitTokens(splitWhitespace)
+}
+
+func splitWhitespace(str string, emit func(start int, end int)) {
+	splitFields(str, unicode.IsSpace, emit)
+}
+
+// splitFields splits string around runs of separator characters (see strings.FieldsFunc)
+func splitFields(str string, isSeparator func(r rune) bool, emit func(start int, end int)) {
+	start := -1
+	for i, r := range str {
+		switch {
+		case isSeparator(r) && start >= 0:
+			emit(start, i)
+			start = -1
+		case !isSeparator(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		emit(start, len(str))
+	}
+}
+
 // TokenizerRegexpFunc splits string by regular expression
 func TokenizerRegexpFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	split, err := regexpSplitter(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitTokens(split), nil
+}
+
+func regexpSplitter(settings map[string]interface{}) (splitFunc, error) {
 	var (
 		expression string
 		ok         bool
@@ -48,22 +91,234 @@ func TokenizerRegexpFunc(settings map[string]interface{}) (AnalyzerFunc, error)
 		return nil, err
 	}
 
-	return func(s []string) []string {
-		if len(s) == 0 {
-			return s
+	// parts between matches are the same as returned by regexp.Split, empty ones are skipped
+	return func(str string, emit func(start int, end int)) {
+		begin, end := 0, 0
+		for _, m := range exp.FindAllStringIndex(str, -1) {
+			end = m[0]
+			if m[1] != 0 && begin < end {
+				emit(begin, end)
+			}
+			begin = m[1]
+		}
+		if end != len(str) && begin < len(str) {
+			emit(begin, len(str))
 		}
+	}, nil
+}
 
-		result := make([]string, 0, len(s))
+var tokenCharClasses = map[string]func(r rune) bool{
+	"letter":      unicode.IsLetter,
+	"digit":       unicode.IsDigit,
+	"whitespace":  unicode.IsSpace,
+	"punctuation": unicode.IsPunct,
+	"symbol":      unicode.IsSymbol,
+}
 
-		for _, str := range s {
-			for _, part := range exp.Split(str, -1) {
-				if part == "" {
-					continue
+// TokenizerNgramFunc splits string into n-grams of "minGram" to "maxGram" characters (1 and 2 by default).
+// If "tokenChars" classes (letter, digit, whitespace, punctuation, symbol) are provided,
+// the string is split into words consisting of these characters first.
+func TokenizerNgramFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	split, err := ngramSplitter(settings, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitTokens(split), nil
+}
+
+// TokenizerEdgeNgramFunc works the same way as TokenizerNgramFunc, but produces only n-grams anchored to the beginning of the word
+func TokenizerEdgeNgramFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	split, err := ngramSplitter(settings, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitTokens(split), nil
+}
+
+func ngramSplitter(settings map[string]interface{}, edge bool) (splitFunc, error) {
+	minGram, maxGram := 1, 2
+	var classes []func(r rune) bool
+	for k, v := range settings {
+		var err error
+		switch k {
+		case "minGram":
+			minGram, err = cast.ToIntE(v)
+		case "maxGram":
+			maxGram, err = cast.ToIntE(v)
+		case "tokenChars":
+			names, ok := toStringSlice(v)
+			if !ok {
+				return nil, errs.Errorf("%q must be a list of strings", k)
+			}
+			for _, name := range names {
+				class, ok := tokenCharClasses[name]
+				if !ok {
+					return nil, errs.Errorf("unknown token chars class %q", name)
 				}
-				result = append(result, part)
+				classes = append(classes, class)
 			}
+			continue
+		default:
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+		if err != nil {
+			return nil, errs.Errorf("%q must be an integer value", k)
 		}
+	}
+	if minGram < 1 {
+		return nil, errs.Errorf("%q must be >= 1", "minGram")
+	}
+	if maxGram < minGram {
+		return nil, errs.Errorf("%q must be >= %q", "maxGram", "minGram")
+	}
 
-		return result
+	isTokenChar := func(r rune) bool {
+		for _, class := range classes {
+			if class(r) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(str string, emit func(start int, end int)) {
+		if len(classes) == 0 {
+			emitNgrams(str, 0, len(str), minGram, maxGram, edge, emit)
+			return
+		}
+
+		splitFields(str, func(r rune) bool { return !isTokenChar(r) }, func(start int, end int) {
+			emitNgrams(str, start, end, minGram, maxGram, edge, emit)
+		})
+	}, nil
+}
+
+// emitNgrams emits n-grams of the word str[wordStart:wordEnd]
+func emitNgrams(str string, wordStart int, wordEnd int, minGram, maxGram int, edge bool, emit func(start int, end int)) {
+	// byte offsets of the word characters and of the word end
+	offsets := make([]int, 0, wordEnd-wordStart+1)
+	for i := range str[wordStart:wordEnd] {
+		offsets = append(offsets, wordStart+i)
+	}
+	offsets = append(offsets, wordEnd)
+
+	length := len(offsets) - 1
+	for start := 0; start < length; start++ {
+		if edge && start > 0 {
+			break
+		}
+
+		for size := minGram; size <= maxGram && start+size <= length; size++ {
+			emit(offsets[start], offsets[start+size])
+		}
+	}
+}
+
+var (
+	urlRegexp   = regexp.MustCompile(`(?i)\b(?:(?:https?|ftp)://|www\.)[^\s<>"']*[^\s<>"'.,;:!?)\]}]`)
+	emailRegexp = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}\b`)
+)
+
+// TokenizerStandardFunc splits string by Unicode word boundaries (UAX#29) dropping whitespace and punctuation.
+// Tokens longer than "maxTokenLength" characters (255 by default) are split.
+func TokenizerStandardFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	split, err := standardSplitter(settings, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitTokens(split), nil
+}
+
+// TokenizerUAXURLEmailFunc works the same way as TokenizerStandardFunc, but keeps URLs and emails as single tokens
+func TokenizerUAXURLEmailFunc(settings map[string]interface{}) (AnalyzerFunc, error) {
+	split, err := standardSplitter(settings, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitTokens(split), nil
+}
+
+func standardSplitter(settings map[string]interface{}, urlEmail bool) (splitFunc, error) {
+	maxTokenLength := 255
+	for k, v := range settings {
+		if k != "maxTokenLength" {
+			return nil, errs.Errorf("key %q is not allowed", k)
+		}
+
+		var err error
+		maxTokenLength, err = cast.ToIntE(v)
+		if err != nil {
+			return nil, errs.Errorf("%q must be an integer value", k)
+		}
+	}
+	if maxTokenLength < 1 {
+		return nil, errs.Errorf("%q must be >= 1", "maxTokenLength")
+	}
+
+	return func(str string, emit func(start int, end int)) {
+		if !urlEmail {
+			emitWords(str, 0, len(str), maxTokenLength, emit)
+			return
+		}
+
+		offset := 0
+		for _, loc := range findURLsAndEmails(str) {
+			emitWords(str, offset, loc[0], maxTokenLength, emit)
+			emitChunks(str, loc[0], loc[1], maxTokenLength, emit)
+			offset = loc[1]
+		}
+		emitWords(str, offset, len(str), maxTokenLength, emit)
 	}, nil
 }
+
+// findURLsAndEmails returns non-overlapping positions of URLs and emails ordered by their start
+func findURLsAndEmails(str string) [][]int {
+	locs := append(urlRegexp.FindAllStringIndex(str, -1), emailRegexp.FindAllStringIndex(str, -1)...)
+	sort.Slice(locs, func(i, j int) bool { return locs[i][0] < locs[j][0] })
+
+	result := make([][]int, 0, len(locs))
+	end := 0
+	for _, loc := range locs {
+		if loc[0] < end {
+			continue
+		}
+		result = append(result, loc)
+		end = loc[1]
+	}
+
+	return result
+}
+
+// emitWords emits words of str[from:to] separated by UAX#29 word boundaries.
+// Segments without letters and digits (whitespace, punctuation) are skipped
+func emitWords(str string, from int, to int, maxTokenLength int, emit func(start int, end int)) {
+	state := -1
+	rest := str[from:to]
+	for len(rest) > 0 {
+		var word string
+		word, rest, state = uniseg.FirstWordInString(rest, state)
+		start := to - len(rest) - len(word)
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		emitChunks(str, start, start+len(word), maxTokenLength, emit)
+	}
+}
+
+// emitChunks emits str[from:to] split into chunks of size characters
+func emitChunks(str string, from int, to int, size int, emit func(start int, end int)) {
+	start, chars := from, 0
+	for i := range str[from:to] {
+		if chars == size {
+			emit(start, from+i)
+			start, chars = from+i, 0
+		}
+		chars++
+	}
+
+	emit(start, to)
+}
@@ -67,3 +67,167 @@ func Test_TokenizerRegexpFunc(t *testing.T) {
 		require.Equal(t, []string{"hello", "world", "hello", "world"}, result)
 	})
 }
+
+func Test_TokenizerNgramFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(map[string]interface{}{"extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if minGram is less than 1", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(map[string]interface{}{"minGram": 0})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if maxGram is less than minGram", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(map[string]interface{}{"minGram": 3, "maxGram": 2})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if token chars class is unknown", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(map[string]interface{}{"tokenChars": []interface{}{"invalid"}})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var data []string
+		f, err := TokenizerNgramFunc(nil)
+		require.NoError(t, err)
+		result := f(data)
+		require.Equal(t, data, result)
+	})
+
+	t.Run("must use default settings", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(nil)
+		require.NoError(t, err)
+		result := f([]string{"a b"})
+		require.Equal(t, []string{"a", "a ", " ", " b", "b"}, result)
+	})
+
+	t.Run("must split by token chars", func(t *testing.T) {
+		f, err := TokenizerNgramFunc(map[string]interface{}{
+			"minGram":    2,
+			"maxGram":    3,
+			"tokenChars": []interface{}{"letter", "digit"},
+		})
+		require.NoError(t, err)
+		result := f([]string{"abcd, ёж"})
+		require.Equal(t, []string{"ab", "abc", "bc", "bcd", "cd", "ёж"}, result)
+	})
+}
+
+func Test_TokenizerEdgeNgramFunc(t *testing.T) {
+	t.Run("must return error if maxGram is less than minGram", func(t *testing.T) {
+		f, err := TokenizerEdgeNgramFunc(map[string]interface{}{"minGram": 3, "maxGram": 2})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		f, err := TokenizerEdgeNgramFunc(map[string]interface{}{
+			"minGram":    1,
+			"maxGram":    3,
+			"tokenChars": []interface{}{"letter"},
+		})
+		require.NoError(t, err)
+		result := f([]string{"quick fox"})
+		require.Equal(t, []string{"q", "qu", "qui", "f", "fo", "fox"}, result)
+	})
+}
+
+func Test_TokenizerStandardFunc(t *testing.T) {
+	t.Run("must return error if extra keys provided", func(t *testing.T) {
+		f, err := TokenizerStandardFunc(map[string]interface{}{"extra": 4})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("must return error if maxTokenLength is less than 1", func(t *testing.T) {
+		f, err := TokenizerStandardFunc(map[string]interface{}{"maxTokenLength": 0})
+		require.Error(t, err)
+		require.Nil(t, f)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var data []string
+		f, err := TokenizerStandardFunc(nil)
+		require.NoError(t, err)
+		result := f(data)
+		require.Equal(t, data, result)
+	})
+
+	t.Run("must split by word boundaries", func(t *testing.T) {
+		f, err := TokenizerStandardFunc(nil)
+		require.NoError(t, err)
+		result := f([]string{"hello,world! The e-mail costs $3.50, isn't it?"})
+		require.Equal(t, []string{"hello", "world", "The", "e", "mail", "costs", "3.50", "isn't", "it"}, result)
+	})
+
+	t.Run("must split CJK text into characters", func(t *testing.T) {
+		f, err := TokenizerStandardFunc(nil)
+		require.NoError(t, err)
+		result := f([]string{"我是中国人"})
+		require.Equal(t, []string{"我", "是", "中", "国", "人"}, result)
+	})
+
+	t.Run("must split long tokens", func(t *testing.T) {
+		f, err := TokenizerStandardFunc(map[string]interface{}{"maxTokenLength": 3})
+		require.NoError(t, err)
+		result := f([]string{"abcdefg"})
+		require.Equal(t, []string{"abc", "def", "g"}, result)
+	})
+}
+
+func Test_TokenizerUAXURLEmailFunc(t *testing.T) {
+	t.Run("must keep urls and emails", func(t *testing.T) {
+		f, err := TokenizerUAXURLEmailFunc(nil)
+		require.NoError(t, err)
+		result := f([]string{"Email john.doe@example.com or visit https://example.com/path?q=1."})
+		require.Equal(t, []string{"Email", "john.doe@example.com", "or", "visit", "https://example.com/path?q=1"}, result)
+	})
+}
+
+func Test_Analyzer_getTokenFunc_Tokenizers(t *testing.T) {
+	offsets := func(tokens []Token) [][3]int {
+		result := make([][3]int, len(tokens))
+		for i, token := range tokens {
+			result[i] = [3]int{token.Position, token.StartOffset, token.EndOffset}
+		}
+		return result
+	}
+	source := []Token{{Value: "ёж,  ёжик", EndOffset: 9}}
+
+	t.Run("whitespace", func(t *testing.T) {
+		f, err := NewAnalyzer(TokenizerWhitespace, nil).getTokenFunc()
+		require.NoError(t, err)
+		require.Equal(t, [][3]int{{0, 0, 3}, {1, 5, 9}}, offsets(f(source)))
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		f, err := NewAnalyzer(TokenizerRegexp, map[string]interface{}{"pattern": "[, ]+"}).getTokenFunc()
+		require.NoError(t, err)
+		require.Equal(t, [][3]int{{0, 0, 2}, {1, 5, 9}}, offsets(f(source)))
+	})
+
+	t.Run("edge_ngram", func(t *testing.T) {
+		f, err := NewAnalyzer(TokenizerEdgeNgram, map[string]interface{}{"tokenChars": []string{"letter"}}).getTokenFunc()
+		require.NoError(t, err)
+		require.Equal(t, [][3]int{{0, 0, 1}, {1, 0, 2}, {2, 5, 6}, {3, 5, 7}}, offsets(f(source)))
+	})
+
+	t.Run("uax_url_email", func(t *testing.T) {
+		f, err := NewAnalyzer(TokenizerUAXURLEmail, map[string]interface{}{"maxTokenLength": 4}).getTokenFunc()
+		require.NoError(t, err)
+		result := f([]Token{{Value: "ёж a@b.ru ёжик", EndOffset: 14}})
+		values := make([]string, len(result))
+		for i, token := range result {
+			values[i] = token.Value
+		}
+		require.Equal(t, []string{"ёж", "a@b.", "ru", "ёжик"}, values)
+		require.Equal(t, [][3]int{{0, 0, 2}, {1, 3, 7}, {2, 7, 9}, {3, 10, 14}}, offsets(result))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/geo"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -33,6 +34,12 @@ func buildRules(s Schema, source map[string]interface{}) validation.MapRule {
 			keyRules = append(keyRules, validation.By(validateKeyword()))
 		case TypeText:
 			keyRules = append(keyRules, validation.By(validateText()))
+		case TypeCompletion:
+			keyRules = append(keyRules, validation.By(validateCompletion()))
+		case TypeDenseVector:
+			keyRules = append(keyRules, validation.By(validateDenseVector(f.Dims, f.Similarity.Type == "" || f.Similarity.Type == SimilarityCosine)))
+		case TypeGeoPoint:
+			keyRules = append(keyRules, validation.By(validateGeoPoint()))
 		case TypeByte:
 			keyRules = append(keyRules, validation.By(validateInt(math.MinInt8, math.MaxInt8)))
 		case TypeShort:
@@ -97,6 +104,121 @@ func validateText() validation.RuleFunc {
 	}
 }
 
+// validateCompletion accepts a string, an object {"input": "value", "weight": 1} where input may be a list of strings,
+// or a list of strings and objects
+func validateCompletion() validation.RuleFunc {
+	var validateItem func(v interface{}, nested bool) error
+	validateItem = func(v interface{}, nested bool) error {
+		switch vv := v.(type) {
+		case string:
+			return nil
+		case []interface{}:
+			if nested {
+				return errs.Errorf("nested lists are not allowed")
+			}
+			for _, item := range vv {
+				if err := validateItem(item, true); err != nil {
+					return err
+				}
+			}
+			return nil
+		case map[string]interface{}:
+			for key, value := range vv {
+				switch key {
+				case "input":
+					if err := validateCompletionInput(value); err != nil {
+						return err
+					}
+				case "weight":
+					if err := validateUint(0, math.MaxInt32)(value); err != nil {
+						return errs.Errorf("weight: %w", err)
+					}
+				default:
+					return errs.Errorf("key %q is not allowed", key)
+				}
+			}
+			if _, ok := vv["input"]; !ok {
+				return errs.Errorf("input is required")
+			}
+			return nil
+		default:
+			return errs.Errorf("required string, list or object, got %#v", v)
+		}
+	}
+
+	return func(v interface{}) error {
+		if v == nil {
+			return nil
+		}
+
+		return validateItem(v, false)
+	}
+}
+
+func validateCompletionInput(v interface{}) error {
+	switch vv := v.(type) {
+	case string:
+		return nil
+	case []interface{}:
+		for _, item := range vv {
+			if _, ok := item.(string); !ok {
+				return errs.Errorf("input: required string, got %#v", item)
+			}
+		}
+		return nil
+	default:
+		return errs.Errorf("input: required string or list of strings, got %#v", v)
+	}
+}
+
+// validateDenseVector accepts a list of dims numbers. Zero vectors cannot be compared by cosine similarity, so they are not allowed if nonZero is true
+func validateDenseVector(dims int, nonZero bool) validation.RuleFunc {
+	validateItem := validateFloat(-1*math.MaxFloat32, math.MaxFloat32)
+
+	return func(v interface{}) error {
+		if v == nil {
+			return nil
+		}
+		vv, ok := v.([]interface{})
+		if !ok {
+			return errs.Errorf("required list of numbers, got %#v", v)
+		}
+		if len(vv) != dims {
+			return errs.Errorf("required %d dimensions, got %d", dims, len(vv))
+		}
+
+		zero := true
+		for i, item := range vv {
+			if item == nil {
+				return errs.Errorf("%d: required float, got nil", i)
+			}
+			if err := validateItem(item); err != nil {
+				return errs.Errorf("%d: %w", i, err)
+			}
+			if f, _ := strconv.ParseFloat(item.(json.Number).String(), 64); f != 0 {
+				zero = false
+			}
+		}
+		if nonZero && zero {
+			return errs.Errorf("zero vector is not allowed for cosine similarity")
+		}
+
+		return nil
+	}
+}
+
+// validateGeoPoint accepts a point as an object {"lat": 1.5, "lon": 2}, a "lat,lon" string or a geohash, or a list of points
+func validateGeoPoint() validation.RuleFunc {
+	return func(v interface{}) error {
+		if v == nil {
+			return nil
+		}
+		_, err := geo.ParsePoints(v)
+
+		return err
+	}
+}
+
 func validateInt(min int64, max int64) validation.RuleFunc {
 	return func(v interface{}) error {
 		if v == nil {
@@ -257,4 +257,87 @@ func Test_ValidateDoc(t *testing.T) {
 			require.Error(t, err)
 		})
 	})
+
+	t.Run("completion", func(t *testing.T) {
+		s := New(map[string]Field{"value": {Type: TypeCompletion, Required: false}}, nil)
+
+		valid := []interface{}{
+			"value",
+			[]interface{}{"value1", "value2"},
+			map[string]interface{}{"input": "value", "weight": json.Number("10")},
+			map[string]interface{}{"input": []interface{}{"value1", "value2"}},
+			[]interface{}{"value1", map[string]interface{}{"input": "value2", "weight": json.Number("2")}},
+		}
+		for _, v := range valid {
+			require.NoError(t, ValidateDoc(s, map[string]interface{}{"value": v}), "%#v", v)
+		}
+
+		invalid := []interface{}{
+			true,
+			json.Number("1"),
+			[]interface{}{[]interface{}{"value"}},
+			map[string]interface{}{"weight": json.Number("1")},
+			map[string]interface{}{"input": true},
+			map[string]interface{}{"input": "value", "weight": json.Number("-1")},
+			map[string]interface{}{"input": "value", "weight": "1"},
+			map[string]interface{}{"input": "value", "unknown": 1},
+		}
+		for _, v := range invalid {
+			require.Error(t, ValidateDoc(s, map[string]interface{}{"value": v}), "%#v", v)
+		}
+	})
+
+	t.Run("dense vector", func(t *testing.T) {
+		s := New(map[string]Field{
+			"cosine": {Type: TypeDenseVector, Dims: 2},
+			"l2":     {Type: TypeDenseVector, Dims: 2, Similarity: Similarity{Type: SimilarityL2Norm}},
+		}, nil)
+
+		require.NoError(t, ValidateDoc(s, map[string]interface{}{"cosine": []interface{}{json.Number("1"), json.Number("-0.5")}}))
+		require.NoError(t, ValidateDoc(s, map[string]interface{}{"l2": []interface{}{json.Number("0"), json.Number("0")}}))
+
+		invalid := []interface{}{
+			"value",
+			json.Number("1"),
+			[]interface{}{json.Number("1")},
+			[]interface{}{json.Number("1"), json.Number("2"), json.Number("3")},
+			[]interface{}{json.Number("1"), "2"},
+			[]interface{}{json.Number("1"), nil},
+			[]interface{}{json.Number("1"), json.Number("1e40")},
+			[]interface{}{json.Number("0"), json.Number("0")},
+		}
+		for _, v := range invalid {
+			require.Error(t, ValidateDoc(s, map[string]interface{}{"cosine": v}), "%#v", v)
+		}
+	})
+
+	t.Run("geo point", func(t *testing.T) {
+		s := New(map[string]Field{"value": {Type: TypeGeoPoint}}, nil)
+
+		valid := []interface{}{
+			map[string]interface{}{"lat": json.Number("55.75"), "lon": json.Number("37.61")},
+			"55.75, 37.61",
+			"ucfv0j",
+			[]interface{}{"55.75,37.61", map[string]interface{}{"lat": json.Number("-10"), "lon": json.Number("180")}},
+		}
+		for _, v := range valid {
+			require.NoError(t, ValidateDoc(s, map[string]interface{}{"value": v}), "%#v", v)
+		}
+
+		invalid := []interface{}{
+			json.Number("1"),
+			map[string]interface{}{"lat": json.Number("55.75")},
+			map[string]interface{}{"lat": "55.75", "lon": json.Number("37.61")},
+			map[string]interface{}{"lat": json.Number("55.75"), "lon": json.Number("37.61"), "alt": json.Number("1")},
+			map[string]interface{}{"lat": json.Number("91"), "lon": json.Number("37.61")},
+			"55.75,181",
+			"55.75,37.61,1",
+			"ucfv0ja",
+			"",
+			[]interface{}{[]interface{}{"55.75,37.61"}},
+		}
+		for _, v := range invalid {
+			require.Error(t, ValidateDoc(s, map[string]interface{}{"value": v}), "%#v", v)
+		}
+	})
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/cyradin/search/internal/errs"
 	"github.com/cyradin/search/internal/index/agg"
 	"github.com/cyradin/search/internal/index/field"
 	"github.com/cyradin/search/internal/index/query"
+	"github.com/cyradin/search/internal/index/suggest"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -16,6 +19,55 @@ type Search struct {
 	Aggs   map[string]jsoniter.RawMessage `json:"aggs"`
 	Limit  int                            `json:"limit"`
 	Offset int                            `json:"offset"`
+	// Explain adds score explanations to hits
+	Explain bool `json:"explain"`
+	// Profile adds query and aggregations execution timings to the result
+	Profile bool `json:"profile"`
+	// Highlight adds highlighted fragments of text fields to hits
+	Highlight *Highlight `json:"highlight"`
+	// Suggest suggesters by names. They are executed independently of the query
+	Suggest map[string]jsoniter.RawMessage `json:"suggest"`
+	// Knn finds nearest neighbors of query vectors. If the query is provided too,
+	// documents matching any of them are returned and their scores are summed
+	Knn KnnSearches `json:"knn"`
+	// SubSearches are secondary queries. They are ranked separately and require rank
+	SubSearches []jsoniter.RawMessage `json:"subSearches"`
+	// Rank fuses ranked lists of the query, sub-searches and kNN searches. Hits are scored by the fused score
+	Rank *Rank `json:"rank"`
+	// Sort orders hits. Hits are ordered by id if not provided
+	Sort []Sort `json:"sort"`
+}
+
+func (s Search) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Highlight),
+		validation.Field(&s.Knn),
+		validation.Field(&s.Sort),
+		validation.Field(&s.SubSearches, validation.When(s.Rank == nil, validation.Empty.Error("sub-searches require rank"))),
+		validation.Field(&s.Rank, validation.By(func(value interface{}) error {
+			if s.Rank == nil {
+				return nil
+			}
+			lists := s.rankedLists()
+			if lists < 2 {
+				return errs.Errorf("rank requires at least 2 of query, sub-searches and knn searches, got %d", lists)
+			}
+			if s.Rank.Linear != nil && len(s.Rank.Linear.Weights) != 0 && len(s.Rank.Linear.Weights) != lists {
+				return errs.Errorf("%d weights required, got %d", lists, len(s.Rank.Linear.Weights))
+			}
+			return nil
+		})),
+	)
+}
+
+// rankedLists returns number of lists to fuse by rank
+func (s Search) rankedLists() int {
+	result := len(s.SubSearches) + len(s.Knn)
+	if s.Query != nil {
+		result++
+	}
+
+	return result
 }
 
 // Search execute search
@@ -26,38 +78,130 @@ func (d *Documents) Search(ctx context.Context, index Index, q Search) (SearchRe
 	}
 	fields := fieldIndex.Fields()
 
+	queryCtx, aggsCtx := ctx, ctx
+	var (
+		queryProfile *query.Profile
+		aggsProfile  agg.Profiles
+	)
+	if q.Profile {
+		queryCtx, queryProfile = query.WithProfile(ctx)
+		aggsCtx, aggsProfile = agg.WithProfile(ctx)
+	}
+	var tokens *query.Tokens
+	if q.Highlight != nil {
+		queryCtx, tokens = query.CollectTokens(queryCtx)
+	}
+
 	t := time.Now()
-	qr, err := d.execQuery(ctx, q, fields)
+	qr, err := d.execQuery(queryCtx, q, fields)
 	if err != nil {
 		return SearchResult{}, err
 	}
+	queryTime := time.Since(t)
 
-	ar, err := d.execAggs(ctx, q, qr, fields)
+	ar, err := d.execAggs(aggsCtx, q, qr, fields)
 	if err != nil {
 		return SearchResult{}, err
 	}
-	took := time.Since(t).Microseconds()
+	aggsTime := time.Since(t) - queryTime
+
+	var sr suggest.Result
+	if len(q.Suggest) > 0 {
+		sr, err = suggest.Exec(suggest.WithIDs(ctx, d.ids), suggest.SuggestRequest(q.Suggest), fields)
+		if err != nil {
+			return SearchResult{}, err
+		}
+	}
+	took := time.Since(t)
+
+	result := NewSearchResult(qr, ar, took.Microseconds(), q.Explain)
+	result.Suggest = sr
+	if err := sortHits(result.Hits.Hits, q.Sort, fields); err != nil {
+		return SearchResult{}, err
+	}
+	if q.Highlight != nil {
+		err = highlight(index.Schema, fieldIndex.AnalyzerOpts(), fields, *q.Highlight, tokens, result.Hits.Hits)
+		if err != nil {
+			return SearchResult{}, err
+		}
+	}
+	if q.Profile {
+		result.Profile = &SearchProfile{
+			QueryTimeNanos:   queryTime.Nanoseconds(),
+			AggsTimeNanos:    aggsTime.Nanoseconds(),
+			SuggestTimeNanos: (took - queryTime - aggsTime).Nanoseconds(),
+			Query:            queryProfile.Children,
+			Aggs:             aggsProfile,
+		}
+	}
 
-	return NewSearchResult(qr, ar, took), nil
+	return result, nil
 }
 
 func (d *Documents) execQuery(ctx context.Context, q Search, fields map[string]field.Field) (query.Result, error) {
-	// exec query by all documents if not provided
-	if q.Query == nil {
-		q.Query = []byte(`{"type": "bool"}`)
+	ctx = query.WithIDs(ctx, d.ids)
+
+	if q.Rank != nil {
+		return d.execRanked(ctx, q, fields)
 	}
 
-	qb, err := query.Build(query.QueryRequest(q.Query))
-	if err != nil {
-		return query.NewEmptyResult(), err
+	// exec query by all documents if neither query nor knn searches are provided
+	qr := query.NewEmptyResult()
+	if q.Query == nil && len(q.Knn) == 0 {
+		q.Query = []byte(`{"type": "match_all"}`)
+	}
+	if q.Query != nil {
+		var err error
+		qr, err = d.buildAndExecQuery(ctx, q.Query, fields)
+		if err != nil {
+			return query.NewEmptyResult(), err
+		}
+	}
+
+	for _, knn := range q.Knn {
+		kr, err := query.Exec(ctx, knn, fields)
+		if err != nil {
+			return query.NewEmptyResult(), err
+		}
+		qr.Or(kr)
+	}
+
+	return qr, nil
+}
+
+// execRanked executes the query, sub-searches and knn searches separately and fuses their results by rank
+func (d *Documents) execRanked(ctx context.Context, q Search, fields map[string]field.Field) (query.Result, error) {
+	requests := q.SubSearches
+	if q.Query != nil {
+		requests = append([]jsoniter.RawMessage{q.Query}, requests...)
+	}
+
+	results := make([]query.Result, 0, len(requests)+len(q.Knn))
+	for _, req := range requests {
+		r, err := d.buildAndExecQuery(ctx, req, fields)
+		if err != nil {
+			return query.NewEmptyResult(), err
+		}
+		results = append(results, r)
+	}
+	for _, knn := range q.Knn {
+		r, err := query.Exec(ctx, knn, fields)
+		if err != nil {
+			return query.NewEmptyResult(), err
+		}
+		results = append(results, r)
 	}
 
-	qr, err := qb.Exec(ctx, fields)
+	return q.Rank.fuse(results), nil
+}
+
+func (d *Documents) buildAndExecQuery(ctx context.Context, req jsoniter.RawMessage, fields map[string]field.Field) (query.Result, error) {
+	qb, err := query.Build(query.QueryRequest(req))
 	if err != nil {
 		return query.NewEmptyResult(), err
 	}
 
-	return qr, nil
+	return query.Exec(ctx, qb, fields)
 }
 
 func (d *Documents) execAggs(ctx context.Context, q Search, qr query.Result, fields map[string]field.Field) (agg.Result, error) {
@@ -65,14 +209,25 @@ func (d *Documents) execAggs(ctx context.Context, q Search, qr query.Result, fie
 }
 
 type SearchResult struct {
-	Took int64                  `json:"took"`
-	Hits SearchHits             `json:"hits"`
-	Aggs map[string]interface{} `json:"aggs"`
+	Took    int64                  `json:"took"`
+	Hits    SearchHits             `json:"hits"`
+	Aggs    map[string]interface{} `json:"aggs"`
+	Profile *SearchProfile         `json:"profile,omitempty"`
+	Suggest map[string]interface{} `json:"suggest,omitempty"`
+}
+
+// SearchProfile breaks down search time. Query, aggregations and suggesters times sum up to the total search time
+type SearchProfile struct {
+	QueryTimeNanos   int64            `json:"queryTimeNanos"`
+	AggsTimeNanos    int64            `json:"aggsTimeNanos"`
+	SuggestTimeNanos int64            `json:"suggestTimeNanos"`
+	Query            []*query.Profile `json:"query"`
+	Aggs             agg.Profiles     `json:"aggs"`
 }
 
-func NewSearchResult(qr query.Result, ar agg.Result, took int64) SearchResult {
+func NewSearchResult(qr query.Result, ar agg.Result, took int64, explain bool) SearchResult {
 	return SearchResult{
-		Hits: NewSearchHits(qr),
+		Hits: NewSearchHits(qr, explain),
 		Aggs: ar,
 		Took: took,
 	}
@@ -84,7 +239,7 @@ type SearchHits struct {
 	MaxScore float64     `json:"maxScore"`
 }
 
-func NewSearchHits(qr query.Result) SearchHits {
+func NewSearchHits(qr query.Result, explain bool) SearchHits {
 	total := qr.Docs().GetCardinality()
 	hits := make([]SearchHit, total)
 	maxScore := 0.0
@@ -97,6 +252,10 @@ func NewSearchHits(qr query.Result) SearchHits {
 			ID:    id,
 			Score: score,
 		}
+		if explain {
+			explanation := qr.Explain(id)
+			hits[i].Explanation = &explanation
+		}
 	}
 
 	return SearchHits{
@@ -115,6 +274,51 @@ type SearchTotal struct {
 }
 
 type SearchHit struct {
-	ID    uint32  `json:"id"`
-	Score float64 `json:"score"`
+	ID          uint32              `json:"id"`
+	Score       float64             `json:"score"`
+	Explanation *field.Explanation  `json:"explanation,omitempty"`
+	Highlight   map[string][]string `json:"highlight,omitempty"`
+	// Sort contains values the hit is sorted by
+	Sort []interface{} `json:"sort,omitempty"`
+}
+
+// ExplainQuery explain request
+type ExplainQuery struct {
+	Query jsoniter.RawMessage `json:"query"`
+}
+
+type ExplainResult struct {
+	ID          string            `json:"id"`
+	Matched     bool              `json:"matched"`
+	Explanation field.Explanation `json:"explanation"`
+}
+
+// Explain describes how the document score is calculated for the query
+func (d *Documents) Explain(ctx context.Context, index Index, guid string, q ExplainQuery) (ExplainResult, error) {
+	fieldIndex, err := d.fields.GetIndex(index.Name)
+	if err != nil {
+		return ExplainResult{}, err
+	}
+
+	id := d.ids.ID(guid)
+	if id == 0 {
+		return ExplainResult{}, ErrDocNotFound
+	}
+	if _, err := fieldIndex.Get(id); err != nil {
+		if err == field.ErrDocNotFound {
+			return ExplainResult{}, ErrDocNotFound
+		}
+		return ExplainResult{}, errs.Errorf("document get err: %w", err)
+	}
+
+	qr, err := d.execQuery(ctx, Search{Query: q.Query}, fieldIndex.Fields())
+	if err != nil {
+		return ExplainResult{}, err
+	}
+
+	return ExplainResult{
+		ID:          guid,
+		Matched:     qr.Docs().Contains(id),
+		Explanation: qr.Explain(id),
+	}, nil
 }
@@ -0,0 +1,242 @@
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	"github.com/cyradin/search/internal/index/suggest"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Documents_Explain(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"text": schema.NewField(schema.TypeText, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	guid1, err := docs.Add(i, "", DocSource{"text": "foo bar"})
+	require.NoError(t, err)
+	guid2, err := docs.Add(i, "", DocSource{"text": "baz"})
+	require.NoError(t, err)
+
+	q := []byte(`{"type": "match", "field": "text", "query": "foo"}`)
+
+	t.Run("must add explanations to search hits", func(t *testing.T) {
+		result, err := docs.Search(ctx, i, Search{Query: q, Explain: true})
+		require.NoError(t, err)
+		require.Len(t, result.Hits.Hits, 1)
+
+		hit := result.Hits.Hits[0]
+		require.NotNil(t, hit.Explanation)
+		require.InDelta(t, hit.Score, hit.Explanation.Value, 1e-9)
+	})
+
+	t.Run("must not add explanations to search hits by default", func(t *testing.T) {
+		result, err := docs.Search(ctx, i, Search{Query: q})
+		require.NoError(t, err)
+		require.Len(t, result.Hits.Hits, 1)
+		require.Nil(t, result.Hits.Hits[0].Explanation)
+	})
+
+	t.Run("must explain matching document", func(t *testing.T) {
+		result, err := docs.Explain(ctx, i, guid1, ExplainQuery{Query: q})
+		require.NoError(t, err)
+		require.Equal(t, guid1, result.ID)
+		require.True(t, result.Matched)
+		require.Greater(t, result.Explanation.Value, 0.0)
+	})
+
+	t.Run("must explain not matching document", func(t *testing.T) {
+		result, err := docs.Explain(ctx, i, guid2, ExplainQuery{Query: q})
+		require.NoError(t, err)
+		require.False(t, result.Matched)
+		require.Equal(t, field.NoMatchExplanation(), result.Explanation)
+	})
+
+	t.Run("must return error if document not found", func(t *testing.T) {
+		_, err := docs.Explain(ctx, i, "unknown", ExplainQuery{Query: q})
+		require.ErrorIs(t, err, ErrDocNotFound)
+	})
+}
+
+func Test_Documents_Search_Profile(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"text":    schema.NewField(schema.TypeText, false, ""),
+				"keyword": schema.NewField(schema.TypeKeyword, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	_, err := docs.Add(i, "", DocSource{"text": "foo bar", "keyword": "a"})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"text": "baz", "keyword": "b"})
+	require.NoError(t, err)
+
+	q := Search{
+		Query: []byte(`{
+			"type": "bool",
+			"should": [
+				{"type": "match", "field": "text", "query": "foo"},
+				{"type": "term", "field": "keyword", "query": "b"}
+			]
+		}`),
+		Aggs: map[string]jsoniter.RawMessage{
+			"keywords": []byte(`{"type": "terms", "field": "keyword", "size": 10}`),
+		},
+	}
+
+	t.Run("must not add profile by default", func(t *testing.T) {
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		require.Nil(t, result.Profile)
+	})
+
+	t.Run("must add query and aggregations profile", func(t *testing.T) {
+		q := q
+		q.Profile = true
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		require.NotNil(t, result.Profile)
+
+		p := result.Profile
+		require.Len(t, p.Query, 1)
+		require.Equal(t, "BoolQuery", p.Query[0].Type)
+		require.Equal(t, uint64(2), p.Query[0].Docs)
+		require.Len(t, p.Query[0].Children, 2)
+		require.Equal(t, "TermsAgg", p.Aggs["keywords"].Type)
+		require.LessOrEqual(t, p.QueryTimeNanos+p.AggsTimeNanos+p.SuggestTimeNanos, (result.Took+1)*1000)
+	})
+}
+
+func Test_Documents_Search_Suggest(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"suggest": {Type: schema.TypeCompletion, Normalizer: "lowercase"},
+			},
+			map[string]schema.FieldAnalyzer{
+				"lowercase": {Analyzers: []schema.Analyzer{{Type: schema.FilterLowercase}}},
+			},
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	guid, err := docs.Add(i, "", DocSource{"suggest": map[string]interface{}{"input": "Nirvana", "weight": json.Number("10")}})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"suggest": "Nine Inch Nails"})
+	require.NoError(t, err)
+
+	result, err := docs.Search(ctx, i, Search{
+		Suggest: map[string]jsoniter.RawMessage{
+			"artists": []byte(`{"type": "completion", "field": "suggest", "prefix": "ni"}`),
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Suggest, 1)
+
+	options := result.Suggest["artists"].(suggest.CompletionResult).Options
+	require.Len(t, options, 2)
+	require.Equal(t, suggest.CompletionOption{Text: "Nirvana", Weight: 10, ID: guid}, options[0])
+	require.Equal(t, "Nine Inch Nails", options[1].Text)
+}
+
+func Test_Documents_Search_Knn(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"vector": {Type: schema.TypeDenseVector, Dims: 2, Similarity: schema.Similarity{Type: schema.SimilarityL2Norm}},
+				"text":   schema.NewField(schema.TypeText, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	vector := func(x, y string) []interface{} {
+		return []interface{}{json.Number(x), json.Number(y)}
+	}
+	_, err := docs.Add(i, "", DocSource{"vector": vector("0", "0"), "text": "foo"})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"vector": vector("1", "0"), "text": "bar"})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"vector": vector("5", "5"), "text": "bar"})
+	require.NoError(t, err)
+	_, err = docs.Add(i, "", DocSource{"vector": vector("0", "1")})
+	require.NoError(t, err)
+
+	_, err = docs.Add(i, "", DocSource{"vector": vector("0", "1"), "text": json.Number("1")})
+	require.Error(t, err)
+	_, err = docs.Add(i, "", DocSource{"vector": vector("0", "1")[:1]})
+	require.Error(t, err)
+
+	t.Run("must return nearest neighbors if query is not provided", func(t *testing.T) {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{"knn": {"field": "vector", "queryVector": [0, 0], "k": 2}}`), &q))
+		require.NoError(t, q.Validate())
+
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Hits.Total.Value)
+		require.Equal(t, uint32(1), result.Hits.Hits[0].ID)
+		require.Equal(t, 1.0, result.Hits.Hits[0].Score)
+		require.Equal(t, 0.5, result.Hits.Hits[1].Score)
+	})
+
+	t.Run("must combine nearest neighbors with query results", func(t *testing.T) {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{
+			"query": {"type": "match", "field": "text", "query": "bar"},
+			"knn": {"field": "vector", "queryVector": [0, 0], "k": 1}
+		}`), &q))
+
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		require.Equal(t, 3, result.Hits.Total.Value)
+
+		scores := make(map[uint32]float64)
+		for _, h := range result.Hits.Hits {
+			scores[h.ID] = h.Score
+		}
+		require.Equal(t, 1.0, scores[1])
+		require.Greater(t, scores[2], 0.0)
+		require.Equal(t, scores[2], scores[3])
+	})
+
+	t.Run("must validate knn request", func(t *testing.T) {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{"knn": {"field": "vector", "queryVector": [0, 0], "k": 0}}`), &q))
+		require.Error(t, q.Validate())
+
+		_, err := docs.Search(ctx, i, Search{Knn: KnnSearches{{Field: "text", QueryVector: []float32{0, 0}}}})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package index
+
+import (
+	"sort"
+
+	"github.com/cyradin/search/internal/errs"
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/geo"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Sort orders search hits. Exactly one mode must be provided
+type Sort struct {
+	GeoDistance *GeoDistanceSort `json:"geoDistance"`
+}
+
+func (s Sort) Validate() error {
+	if s.GeoDistance == nil {
+		return errs.Errorf("sort mode must be provided")
+	}
+
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.GeoDistance),
+	)
+}
+
+// GeoDistanceSort orders hits by distance from the origin to the closest point of the geo_point field.
+// Distance in the unit is added to hit sort values. Hits without points are placed last
+type GeoDistanceSort struct {
+	Field  string      `json:"field"`
+	Origin interface{} `json:"origin"`
+	Order  string      `json:"order"`
+	Unit   string      `json:"unit"`
+}
+
+func (s GeoDistanceSort) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Field, validation.Required, validation.Length(1, 255)),
+		validation.Field(&s.Origin, validation.NotNil, validation.By(func(value interface{}) error {
+			if value == nil {
+				return nil
+			}
+			_, err := geo.ParsePoint(value)
+			return err
+		})),
+		validation.Field(&s.Order, validation.In(SortOrderAsc, SortOrderDesc)),
+		validation.Field(&s.Unit, validation.By(func(value interface{}) error {
+			if _, ok := geo.DistanceUnits[s.Unit]; s.Unit != "" && !ok {
+				return errs.Errorf("unknown distance unit %q", s.Unit)
+			}
+			return nil
+		})),
+	)
+}
+
+// values returns sort values of the hits. Nil values are missing ones
+func (s GeoDistanceSort) values(hits []SearchHit, fields map[string]field.Field) ([]interface{}, error) {
+	result := make([]interface{}, len(hits))
+
+	gf, err := field.GetGeoPoint(fields, s.Field)
+	if err != nil || gf == nil {
+		return result, err
+	}
+	origin, err := geo.ParsePoint(s.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := 1.0
+	if s.Unit != "" {
+		unit = geo.DistanceUnits[s.Unit]
+	}
+	for i, hit := range hits {
+		if d, ok := gf.Distance(hit.ID, origin); ok {
+			result[i] = d / unit
+		}
+	}
+
+	return result, nil
+}
+
+// sortHits orders hits by the sorts and sets their sort values
+func sortHits(hits []SearchHit, sorts []Sort, fields map[string]field.Field) error {
+	if len(sorts) == 0 {
+		return nil
+	}
+
+	desc := make([]bool, len(sorts))
+	for i, s := range sorts {
+		values, err := s.GeoDistance.values(hits, fields)
+		if err != nil {
+			return errs.Errorf("sort %d: %w", i, err)
+		}
+		desc[i] = s.GeoDistance.Order == SortOrderDesc
+		for j := range hits {
+			hits[j].Sort = append(hits[j].Sort, values[j])
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		for k := range sorts {
+			if c := compareSortValues(hits[i].Sort[k], hits[j].Sort[k], desc[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	return nil
+}
+
+// compareSortValues compares distances. Missing values are greater than any other in both orders
+func compareSortValues(a interface{}, b interface{}, desc bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	av, bv := a.(float64), b.(float64)
+	if desc {
+		av, bv = bv, av
+	}
+	switch {
+	case av < bv:
+		return -1
+	case av > bv:
+		return 1
+	}
+
+	return 0
+}
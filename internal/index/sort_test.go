@@ -0,0 +1,111 @@
+package index
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sort_Validate(t *testing.T) {
+	invalid := map[string]string{
+		"mode is missing":    `{}`,
+		"field is missing":   `{"geoDistance": {"origin": "1,2"}}`,
+		"origin is missing":  `{"geoDistance": {"field": "point"}}`,
+		"origin is invalid":  `{"geoDistance": {"field": "point", "origin": "1,a"}}`,
+		"order is invalid":   `{"geoDistance": {"field": "point", "origin": "1,2", "order": "up"}}`,
+		"unit is invalid":    `{"geoDistance": {"field": "point", "origin": "1,2", "unit": "parsec"}}`,
+		"origin is a number": `{"geoDistance": {"field": "point", "origin": 1}}`,
+	}
+	for name, req := range invalid {
+		t.Run("must return error if "+name, func(t *testing.T) {
+			var s Sort
+			require.NoError(t, jsoniter.Unmarshal([]byte(req), &s))
+			require.Error(t, validation.Validate(s))
+		})
+	}
+
+	t.Run("must not return error if sort is valid", func(t *testing.T) {
+		var s Sort
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{"geoDistance": {"field": "point", "origin": {"lat": 1, "lon": 2}, "order": "desc", "unit": "km"}}`), &s))
+		require.NoError(t, validation.Validate(s))
+	})
+}
+
+func Test_Documents_Search_Sort(t *testing.T) {
+	ctx := context.Background()
+	i := New(
+		"name",
+		schema.New(
+			map[string]schema.Field{
+				"point":   {Type: schema.TypeGeoPoint},
+				"keyword": schema.NewField(schema.TypeKeyword, false, ""),
+			},
+			nil,
+		),
+	)
+
+	docs := NewDocuments(t.TempDir())
+	require.NoError(t, docs.AddIndex(i))
+
+	sources := []DocSource{
+		{"point": "51.5074,-0.1278"},                        // London
+		{"point": "55.7558,37.6173"},                        // Moscow
+		{"keyword": "no point"},                             // no point
+		{"point": []interface{}{"59.9343,30.3351", "ucfv"}}, // Saint Petersburg and around Moscow
+	}
+	for _, source := range sources {
+		_, err := docs.Add(i, "", source)
+		require.NoError(t, err)
+	}
+	_, err := docs.Add(i, "", DocSource{"point": "91,0"})
+	require.Error(t, err)
+
+	search := func(req string) SearchResult {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(req), &q))
+		require.NoError(t, q.Validate())
+
+		result, err := docs.Search(ctx, i, q)
+		require.NoError(t, err)
+		return result
+	}
+	ids := func(result SearchResult) []uint32 {
+		result2 := make([]uint32, len(result.Hits.Hits))
+		for i, h := range result.Hits.Hits {
+			result2[i] = h.ID
+		}
+		return result2
+	}
+
+	t.Run("must sort hits by distance", func(t *testing.T) {
+		result := search(`{"sort": [{"geoDistance": {"field": "point", "origin": "55.75,37.61", "unit": "km"}}]}`)
+		require.Equal(t, []uint32{2, 4, 1, 3}, ids(result))
+		require.InDelta(t, 0.8, result.Hits.Hits[0].Sort[0], 0.1)
+		require.InDelta(t, 2500, result.Hits.Hits[2].Sort[0], 10)
+		require.Nil(t, result.Hits.Hits[3].Sort[0])
+	})
+
+	t.Run("must sort hits by distance in descending order", func(t *testing.T) {
+		result := search(`{"sort": [{"geoDistance": {"field": "point", "origin": "55.75,37.61", "order": "desc"}}]}`)
+		require.Equal(t, []uint32{1, 4, 2, 3}, ids(result))
+	})
+
+	t.Run("must sort only matched documents", func(t *testing.T) {
+		result := search(`{
+			"query": {"type": "geo_distance", "field": "point", "origin": "51.5,0", "distance": "700km"},
+			"sort": [{"geoDistance": {"field": "point", "origin": "59.93,30.33"}}]
+		}`)
+		require.Equal(t, []uint32{1}, ids(result))
+	})
+
+	t.Run("must return error if field is not a geo point", func(t *testing.T) {
+		var q Search
+		require.NoError(t, jsoniter.Unmarshal([]byte(`{"sort": [{"geoDistance": {"field": "keyword", "origin": "1,2"}}]}`), &q))
+		_, err := docs.Search(ctx, i, q)
+		require.Error(t, err)
+	})
+}
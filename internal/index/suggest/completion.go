@@ -0,0 +1,66 @@
+package suggest
+
+import (
+	"context"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Suggester = (*CompletionSuggester)(nil)
+
+const CompletionDefaultSize = 5
+
+type CompletionResult struct {
+	Options []CompletionOption `json:"options"`
+}
+
+type CompletionOption struct {
+	Text   string `json:"text"`
+	Weight int    `json:"weight"`
+	ID     string `json:"id"`
+}
+
+// CompletionSuggester returns top weighted inputs of completion field starting with the prefix
+type CompletionSuggester struct {
+	Field  string `json:"field"`
+	Prefix string `json:"prefix"`
+	Size   int    `json:"size"`
+}
+
+func (s *CompletionSuggester) Validate() error {
+	return validation.ValidateStruct(s,
+		validation.Field(&s.Field, validation.Required),
+		validation.Field(&s.Size, validation.Min(0)),
+	)
+}
+
+func (s *CompletionSuggester) Exec(ctx context.Context, fields Fields) (interface{}, error) {
+	result := CompletionResult{Options: []CompletionOption{}}
+
+	f, ok := fields[s.Field]
+	if !ok {
+		return result, nil
+	}
+	completion, ok := f.(*field.Completion)
+	if !ok {
+		return nil, validation.Errors{
+			"field": validation.NewError("validation_field_not_completion", "field is not a completion field"),
+		}
+	}
+
+	size := s.Size
+	if size == 0 {
+		size = CompletionDefaultSize
+	}
+
+	for _, o := range completion.Suggest(s.Prefix, size) {
+		result.Options = append(result.Options, CompletionOption{
+			Text:   o.Text,
+			Weight: o.Weight,
+			ID:     uid(ctx, o.ID),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func mustUnmarshal(t *testing.T, src string, dst interface{}) {
+	err := jsoniter.Unmarshal([]byte(src), dst)
+	require.NoError(t, err)
+}
+
+type testIDs struct{}
+
+func (testIDs) UID(id uint32) string {
+	return fmt.Sprintf("guid%d", id)
+}
+
+func Test_CompletionSuggester_Validate(t *testing.T) {
+	t.Run("must return error if field is empty", func(t *testing.T) {
+		s := new(CompletionSuggester)
+		mustUnmarshal(t, `{"prefix": "foo"}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+	t.Run("must return error if size is negative", func(t *testing.T) {
+		s := new(CompletionSuggester)
+		mustUnmarshal(t, `{"field": "field", "prefix": "foo", "size": -1}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+}
+
+func Test_CompletionSuggester_Exec(t *testing.T) {
+	ctx := WithIDs(context.Background(), testIDs{})
+
+	completion, err := field.New(schema.TypeCompletion)
+	require.NoError(t, err)
+	completion.Add(1, map[string]interface{}{"input": "foo", "weight": 1})
+	completion.Add(2, map[string]interface{}{"input": "foobar", "weight": 2})
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	fields := Fields{"completion": completion, "keyword": keyword}
+
+	t.Run("must return top completions with guids", func(t *testing.T) {
+		s := new(CompletionSuggester)
+		mustUnmarshal(t, `{"field": "completion", "prefix": "fo", "size": 1}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, CompletionResult{Options: []CompletionOption{{Text: "foobar", Weight: 2, ID: "guid2"}}}, result)
+	})
+
+	t.Run("must return empty result if field not found", func(t *testing.T) {
+		s := new(CompletionSuggester)
+		mustUnmarshal(t, `{"field": "unknown", "prefix": "fo"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, CompletionResult{Options: []CompletionOption{}}, result)
+	})
+
+	t.Run("must return error if field is not a completion field", func(t *testing.T) {
+		s := new(CompletionSuggester)
+		mustUnmarshal(t, `{"field": "keyword", "prefix": "fo"}`, s)
+
+		_, err := s.Exec(ctx, fields)
+		require.Error(t, err)
+	})
+}
+
+func Test_Exec(t *testing.T) {
+	t.Run("must return error if suggester type is unknown", func(t *testing.T) {
+		_, err := Exec(context.Background(), SuggestRequest{"name": []byte(`{"type": "unknown"}`)}, Fields{})
+		require.Error(t, err)
+	})
+
+	t.Run("must return error if suggester is invalid", func(t *testing.T) {
+		_, err := Exec(context.Background(), SuggestRequest{"name": []byte(`{"type": "completion"}`)}, Fields{})
+		require.Error(t, err)
+	})
+}
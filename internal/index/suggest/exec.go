@@ -0,0 +1,96 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type Fields map[string]field.Field
+
+type Suggester interface {
+	Exec(ctx context.Context, fields Fields) (interface{}, error)
+}
+
+type SuggesterType struct {
+	Type string `json:"type"`
+}
+
+type Result map[string]interface{}
+type SuggestRequest map[string]jsoniter.RawMessage
+
+// IDs maps internal document ids to GUIDs
+type IDs interface {
+	UID(id uint32) string
+}
+
+func WithIDs(ctx context.Context, ids IDs) context.Context {
+	return context.WithValue(ctx, "suggest.ids", ids)
+}
+
+func uid(ctx context.Context, id uint32) string {
+	if ids, ok := ctx.Value("suggest.ids").(IDs); ok {
+		return ids.UID(id)
+	}
+
+	return ""
+}
+
+func Exec(ctx context.Context, req SuggestRequest, fields Fields) (Result, error) {
+	suggesters, err := build(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Result, len(suggesters))
+	for key, s := range suggesters {
+		r, err := s.Exec(ctx, fields)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = r
+	}
+
+	return result, nil
+}
+
+func build(req SuggestRequest) (map[string]Suggester, error) {
+	result := make(map[string]Suggester, len(req))
+	for key, value := range req {
+		var s Suggester
+
+		suggesterType := new(SuggesterType)
+		err := jsoniter.Unmarshal(value, suggesterType)
+		if err != nil {
+			return nil, err
+		}
+
+		switch suggesterType.Type {
+		case "completion":
+			s = new(CompletionSuggester)
+		case "term":
+			s = new(TermSuggester)
+		case "phrase":
+			s = new(PhraseSuggester)
+		default:
+			return nil, fmt.Errorf("unknown suggester type %q", suggesterType.Type)
+		}
+
+		err = jsoniter.Unmarshal(value, s)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validation.Validate(s)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = s
+	}
+
+	return result, nil
+}
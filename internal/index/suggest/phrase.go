@@ -0,0 +1,190 @@
+package suggest
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Suggester = (*PhraseSuggester)(nil)
+
+const (
+	PhraseDefaultSize      = 5
+	PhraseDefaultMaxErrors = 1
+
+	// phraseCandidatesPerToken max number of corrections of each token
+	phraseCandidatesPerToken = 5
+	// phraseBeamSize max number of partial phrases kept per token
+	phraseBeamSize = 50
+	// phraseBackoff is the discount of unigram probability if tokens never occur together ("stupid backoff")
+	phraseBackoff = 0.4
+)
+
+type PhraseResult struct {
+	Text    string         `json:"text"`
+	Options []PhraseOption `json:"options"`
+}
+
+type PhraseOption struct {
+	Text string `json:"text"`
+	// Score is the ratio of the phrase probability to the text probability
+	Score float64 `json:"score"`
+}
+
+// PhraseSuggester suggests corrections of the whole text. Token corrections are combined
+// and phrases are scored by co-occurrence of adjacent tokens in the field documents.
+// Only phrases scored higher than the text itself are returned
+type PhraseSuggester struct {
+	Field        string `json:"field"`
+	Text         string `json:"text"`
+	Size         int    `json:"size"`
+	MaxEdits     *int   `json:"maxEdits"`
+	PrefixLength *int   `json:"prefixLength"`
+	// MaxErrors max number of corrected tokens
+	MaxErrors *int `json:"maxErrors"`
+}
+
+func (s *PhraseSuggester) Validate() error {
+	return validation.ValidateStruct(s,
+		validation.Field(&s.Field, validation.Required),
+		validation.Field(&s.Text, validation.Required),
+		validation.Field(&s.Size, validation.Min(0)),
+		validation.Field(&s.MaxEdits, validation.NilOrNotEmpty, validation.Min(1), validation.Max(2)),
+		validation.Field(&s.PrefixLength, validation.Min(0)),
+		validation.Field(&s.MaxErrors, validation.NilOrNotEmpty, validation.Min(1)),
+	)
+}
+
+type phrase struct {
+	tokens []string
+	errors int
+	score  float64
+}
+
+func (s *PhraseSuggester) Exec(ctx context.Context, fields Fields) (interface{}, error) {
+	result := PhraseResult{Options: []PhraseOption{}}
+
+	f, ok, err := textField(fields, s.Field)
+	if !ok || err != nil {
+		return result, err
+	}
+
+	tokens := f.SearchTokens(s.Text)
+	result.Text = strings.Join(tokens, " ")
+	if len(tokens) == 0 {
+		return result, nil
+	}
+
+	size := s.Size
+	if size == 0 {
+		size = PhraseDefaultSize
+	}
+	maxEdits := intOrDefault(s.MaxEdits, TermDefaultMaxEdits)
+	prefixLength := intOrDefault(s.PrefixLength, TermDefaultPrefixLength)
+	maxErrors := intOrDefault(s.MaxErrors, PhraseDefaultMaxErrors)
+
+	model := newPhraseModel(f)
+
+	beam := []phrase{{}}
+	for _, token := range tokens {
+		candidates := []string{token}
+		for i, c := range f.TermCandidates(token, maxEdits, prefixLength) {
+			if i >= phraseCandidatesPerToken {
+				break
+			}
+			candidates = append(candidates, c.Term)
+		}
+
+		next := make([]phrase, 0, len(beam)*len(candidates))
+		for _, p := range beam {
+			for _, c := range candidates {
+				errors := p.errors
+				if c != token {
+					errors++
+				}
+				if errors > maxErrors {
+					continue
+				}
+
+				tokens := make([]string, len(p.tokens), len(p.tokens)+1)
+				copy(tokens, p.tokens)
+				next = append(next, phrase{
+					tokens: append(tokens, c),
+					errors: errors,
+					score:  p.score + model.logProb(p.tokens, c),
+				})
+			}
+		}
+
+		sort.SliceStable(next, func(i, j int) bool { return next[i].score > next[j].score })
+		if len(next) > phraseBeamSize {
+			next = next[:phraseBeamSize]
+		}
+		beam = next
+	}
+
+	original := model.score(tokens)
+	for _, p := range beam {
+		if len(result.Options) >= size {
+			break
+		}
+		if p.errors == 0 || p.score <= original {
+			continue
+		}
+
+		result.Options = append(result.Options, PhraseOption{
+			Text:  strings.Join(p.tokens, " "),
+			Score: math.Exp(p.score - original),
+		})
+	}
+
+	return result, nil
+}
+
+// phraseModel is a bigram language model built on documents statistics.
+// Bigram probability is the share of documents containing the previous token which also contain the token
+type phraseModel struct {
+	field     *field.Text
+	docCount  float64
+	termCount float64
+}
+
+func newPhraseModel(f *field.Text) phraseModel {
+	return phraseModel{
+		field:     f,
+		docCount:  float64(f.DocCount()),
+		termCount: float64(f.TermCount()),
+	}
+}
+
+// unigram returns smoothed probability of the token
+func (m phraseModel) unigram(token string) float64 {
+	return (float64(m.field.DocFreq(token)) + 1) / (m.docCount + m.termCount + 1)
+}
+
+// logProb returns log probability of the token following the previous tokens
+func (m phraseModel) logProb(prev []string, token string) float64 {
+	if len(prev) == 0 {
+		return math.Log(m.unigram(token))
+	}
+
+	last := prev[len(prev)-1]
+	if co := m.field.CoDocFreq(last, token); co > 0 {
+		return math.Log(float64(co) / float64(m.field.DocFreq(last)))
+	}
+
+	return math.Log(phraseBackoff * m.unigram(token))
+}
+
+func (m phraseModel) score(tokens []string) float64 {
+	var result float64
+	for i, token := range tokens {
+		result += m.logProb(tokens[:i], token)
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package suggest
+
+import (
+	"context"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PhraseSuggester_Validate(t *testing.T) {
+	t.Run("must return error if field is empty", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"text": "text"}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+	t.Run("must return error if max errors is invalid", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"field": "field", "text": "text", "maxErrors": 0}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+}
+
+func Test_PhraseSuggester_Exec(t *testing.T) {
+	ctx := context.Background()
+	fields := Fields{
+		"text": newTestTextField(t,
+			"new york city",
+			"new york times",
+			"new work schedule",
+			"new work",
+			"york minster",
+		),
+	}
+
+	t.Run("must correct the phrase using co-occurrence", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "new yrk"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+
+		r := result.(PhraseResult)
+		require.Equal(t, "new yrk", r.Text)
+		require.NotEmpty(t, r.Options)
+		require.Equal(t, "new york", r.Options[0].Text)
+		require.Greater(t, r.Options[0].Score, 1.0)
+	})
+
+	t.Run("must prefer tokens occurring together", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "york minstr", "maxErrors": 1}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, "york minster", result.(PhraseResult).Options[0].Text)
+	})
+
+	t.Run("must respect max errors", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "nev yrk"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		for _, o := range result.(PhraseResult).Options {
+			require.NotEqual(t, "new york", o.Text)
+		}
+
+		s.MaxErrors = new(int)
+		*s.MaxErrors = 2
+		result, err = s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, "new york", result.(PhraseResult).Options[0].Text)
+	})
+
+	t.Run("must not suggest anything for a correct phrase", func(t *testing.T) {
+		s := new(PhraseSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "new york"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Empty(t, result.(PhraseResult).Options)
+	})
+}
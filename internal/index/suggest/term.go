@@ -0,0 +1,132 @@
+package suggest
+
+import (
+	"context"
+
+	"github.com/cyradin/search/internal/index/field"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var _ Suggester = (*TermSuggester)(nil)
+
+const (
+	TermDefaultSize         = 5
+	TermDefaultMaxEdits     = 2
+	TermDefaultPrefixLength = 1
+)
+
+type SuggestMode string
+
+const (
+	// SuggestModeMissing suggests only tokens not found in the field
+	SuggestModeMissing SuggestMode = "missing"
+	// SuggestModePopular suggests tokens which occur in more documents than the token itself
+	SuggestModePopular SuggestMode = "popular"
+	// SuggestModeAlways suggests any matching tokens
+	SuggestModeAlways SuggestMode = "always"
+)
+
+type TermResult struct {
+	Tokens []TermToken `json:"tokens"`
+}
+
+type TermToken struct {
+	Text    string       `json:"text"`
+	Options []TermOption `json:"options"`
+}
+
+type TermOption struct {
+	Text     string `json:"text"`
+	DocFreq  int    `json:"docFreq"`
+	Distance int    `json:"distance"`
+}
+
+// TermSuggester suggests corrections of every text token. Candidates are taken from the field terms
+// within the edit distance and ranked by the number of documents containing them
+type TermSuggester struct {
+	Field        string      `json:"field"`
+	Text         string      `json:"text"`
+	Size         int         `json:"size"`
+	MaxEdits     *int        `json:"maxEdits"`
+	PrefixLength *int        `json:"prefixLength"`
+	SuggestMode  SuggestMode `json:"suggestMode"`
+}
+
+func (s *TermSuggester) Validate() error {
+	return validation.ValidateStruct(s,
+		validation.Field(&s.Field, validation.Required),
+		validation.Field(&s.Text, validation.Required),
+		validation.Field(&s.Size, validation.Min(0)),
+		validation.Field(&s.MaxEdits, validation.NilOrNotEmpty, validation.Min(1), validation.Max(2)),
+		validation.Field(&s.PrefixLength, validation.Min(0)),
+		validation.Field(&s.SuggestMode, validation.In(SuggestModeMissing, SuggestModePopular, SuggestModeAlways)),
+	)
+}
+
+func (s *TermSuggester) Exec(ctx context.Context, fields Fields) (interface{}, error) {
+	result := TermResult{Tokens: []TermToken{}}
+
+	f, ok, err := textField(fields, s.Field)
+	if !ok || err != nil {
+		return result, err
+	}
+
+	size := s.Size
+	if size == 0 {
+		size = TermDefaultSize
+	}
+	maxEdits := intOrDefault(s.MaxEdits, TermDefaultMaxEdits)
+	prefixLength := intOrDefault(s.PrefixLength, TermDefaultPrefixLength)
+	mode := s.SuggestMode
+	if mode == "" {
+		mode = SuggestModeMissing
+	}
+
+	for _, token := range f.SearchTokens(s.Text) {
+		item := TermToken{Text: token, Options: []TermOption{}}
+
+		docFreq := f.DocFreq(token)
+		if mode == SuggestModeMissing && docFreq > 0 {
+			result.Tokens = append(result.Tokens, item)
+			continue
+		}
+
+		for _, c := range f.TermCandidates(token, maxEdits, prefixLength) {
+			if len(item.Options) >= size {
+				break
+			}
+			if mode == SuggestModePopular && c.DocFreq <= docFreq {
+				// candidates are ordered by document frequency
+				break
+			}
+			item.Options = append(item.Options, TermOption{Text: c.Term, DocFreq: c.DocFreq, Distance: c.Distance})
+		}
+		result.Tokens = append(result.Tokens, item)
+	}
+
+	return result, nil
+}
+
+// textField returns text field by name. False is returned if the field not found
+func textField(fields Fields, name string) (*field.Text, bool, error) {
+	f, ok := fields[name]
+	if !ok {
+		return nil, false, nil
+	}
+	text, ok := f.(*field.Text)
+	if !ok {
+		return nil, false, validation.Errors{
+			"field": validation.NewError("validation_field_not_text", "field is not a text field"),
+		}
+	}
+
+	return text, true, nil
+}
+
+func intOrDefault(v *int, def int) int {
+	if v == nil {
+		return def
+	}
+
+	return *v
+}
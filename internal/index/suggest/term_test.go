@@ -0,0 +1,93 @@
+package suggest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cyradin/search/internal/index/field"
+	"github.com/cyradin/search/internal/index/schema"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTextField(t *testing.T, values ...string) field.Field {
+	f, err := field.New(schema.TypeText, field.FieldOpts{
+		Analyzer: func(s []string) []string { return strings.Fields(strings.ToLower(s[0])) },
+		Scoring:  field.NewScoring(),
+	})
+	require.NoError(t, err)
+	for i, v := range values {
+		f.Add(uint32(i+1), v)
+	}
+
+	return f
+}
+
+func Test_TermSuggester_Validate(t *testing.T) {
+	t.Run("must return error if text is empty", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "field"}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+	t.Run("must return error if max edits is out of range", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "field", "text": "text", "maxEdits": 3}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+	t.Run("must return error if suggest mode is invalid", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "field", "text": "text", "suggestMode": "invalid"}`, s)
+		require.Error(t, validation.Validate(s))
+	})
+}
+
+func Test_TermSuggester_Exec(t *testing.T) {
+	ctx := context.Background()
+	keyword, err := field.New(schema.TypeKeyword)
+	require.NoError(t, err)
+	fields := Fields{
+		"text":    newTestTextField(t, "search engine", "search query", "seared tuna", "sear"),
+		"keyword": keyword,
+	}
+
+	t.Run("must suggest corrections of missing tokens", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "Serch engine"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, TermResult{Tokens: []TermToken{
+			{Text: "serch", Options: []TermOption{
+				{Text: "search", DocFreq: 2, Distance: 1},
+			}},
+			{Text: "engine", Options: []TermOption{}},
+		}}, result)
+	})
+
+	t.Run("must limit number of options", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "seare", "size": 1}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, []TermOption{{Text: "search", DocFreq: 2, Distance: 2}}, result.(TermResult).Tokens[0].Options)
+	})
+
+	t.Run("must suggest more popular tokens in popular mode", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "text", "text": "sear", "suggestMode": "popular"}`, s)
+
+		result, err := s.Exec(ctx, fields)
+		require.NoError(t, err)
+		require.Equal(t, []TermOption{{Text: "search", DocFreq: 2, Distance: 2}}, result.(TermResult).Tokens[0].Options)
+	})
+
+	t.Run("must return error if field is not a text field", func(t *testing.T) {
+		s := new(TermSuggester)
+		mustUnmarshal(t, `{"field": "keyword", "text": "serch"}`, s)
+
+		_, err := s.Exec(ctx, fields)
+		require.Error(t, err)
+	})
+}